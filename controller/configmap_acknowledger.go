@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// The annotation of the ConfigMap holding the acknowledged fingerprint
+	deletionThresholdAckAnnotationKey = "external-dns.alpha.kubernetes.io/deletion-threshold-ack"
+)
+
+// ConfigMapAcknowledger acknowledges the changes blocked by a plan.DeletionThresholdPolicy when
+// the fingerprint is set as the deletion-threshold-ack annotation of a ConfigMap, so that
+// operators can release them without restarting ExternalDNS.
+type ConfigMapAcknowledger struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Name      string
+}
+
+// Acknowledged returns true if the ConfigMap is annotated with the given fingerprint
+func (a *ConfigMapAcknowledger) Acknowledged(fingerprint string) bool {
+	cm, err := a.Client.CoreV1().ConfigMaps(a.Namespace).Get(a.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("Failed to get deletion threshold acknowledgement from ConfigMap %s/%s: %v", a.Namespace, a.Name, err)
+		return false
+	}
+	return cm.Annotations[deletionThresholdAckAnnotationKey] == fingerprint
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapAcknowledger(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      "external-dns",
+			Annotations: map[string]string{
+				deletionThresholdAckAnnotationKey: "0123456789abcdef",
+			},
+		},
+	})
+
+	ack := &ConfigMapAcknowledger{Client: client, Namespace: "kube-system", Name: "external-dns"}
+	assert.True(t, ack.Acknowledged("0123456789abcdef"))
+	assert.False(t, ack.Acknowledged("fedcba9876543210"))
+
+	missing := &ConfigMapAcknowledger{Client: client, Namespace: "kube-system", Name: "missing"}
+	assert.False(t, missing.Acknowledged("0123456789abcdef"))
+}
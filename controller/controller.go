@@ -17,11 +17,14 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/plan"
 	"github.com/kubernetes-incubator/external-dns/registry"
 	"github.com/kubernetes-incubator/external-dns/source"
@@ -30,14 +33,18 @@ import (
 var (
 	registryErrors = prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Name: "registry_errors_total",
-			Help: "Number of Registry errors.",
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "errors_total",
+			Help:      "Number of Registry errors.",
 		},
 	)
 	sourceErrors = prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Name: "source_errors_total",
-			Help: "Number of Source errors.",
+			Namespace: "external_dns",
+			Subsystem: "source",
+			Name:      "errors_total",
+			Help:      "Number of Source errors.",
 		},
 	)
 	sourceEndpointsTotal = prometheus.NewGauge(
@@ -48,6 +55,15 @@ var (
 			Help:      "Number of Endpoints in all sources",
 		},
 	)
+	sourceInvalidEndpointsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "source",
+			Name:      "invalid_endpoints_total",
+			Help:      "Number of Endpoints dropped because they failed validation",
+		},
+		[]string{"record_type"},
+	)
 	registryEndpointsTotal = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "external_dns",
@@ -56,13 +72,54 @@ var (
 			Help:      "Number of Endpoints in the registry",
 		},
 	)
+	registryZoneRecords = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "zone_records",
+			Help:      "Number of records in the registry per zone, owned by this instance or foreign",
+		},
+		[]string{"zone", "ownership"},
+	)
+	syncStageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "sync_stage_duration_seconds",
+			Help:      "Duration of the stages of a synchronization",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 15),
+		},
+		[]string{"stage"},
+	)
+	appliedChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "applied_changes_total",
+			Help:      "Number of changes applied to DNS records",
+		},
+		[]string{"provider", "zone", "record_type", "action"},
+	)
+	lastSuccessfulSync = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "last_sync_timestamp_seconds",
+			Help:      "Timestamp of the last successful synchronization",
+		},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(registryErrors)
 	prometheus.MustRegister(sourceErrors)
 	prometheus.MustRegister(sourceEndpointsTotal)
+	prometheus.MustRegister(sourceInvalidEndpointsTotal)
 	prometheus.MustRegister(registryEndpointsTotal)
+	prometheus.MustRegister(registryZoneRecords)
+	prometheus.MustRegister(syncStageDuration)
+	prometheus.MustRegister(appliedChanges)
+	prometheus.MustRegister(lastSuccessfulSync)
 }
 
 // Controller is responsible for orchestrating the different components.
@@ -76,54 +133,250 @@ type Controller struct {
 	Registry registry.Registry
 	// The policy that defines which changes to DNS records are allowed
 	Policy plan.Policy
+	// The resolver that picks a record when several resources want the same DNS name
+	Resolver plan.ConflictResolver
 	// The interval between individual synchronizations
 	Interval time.Duration
+	// The minimum interval between two synchronizations triggered by events
+	MinEventSyncInterval time.Duration
+	// The deadline of a single synchronization, unlimited if zero
+	SyncTimeout time.Duration
+	// The health of the synchronizations, optional
+	Status *Status
+	// The zones records and changes are attributed to in the Status and metrics
+	Zones []string
+	// The owner ID of the registry, records of other owners are reported as foreign
+	OwnerID string
+	// The name of the DNS provider, reported in metrics
+	Provider string
+	// The time of the next scheduled synchronization, zero until the first one
+	nextRunAt time.Time
+	// The time of the last synchronization
+	lastRunAt time.Time
+	// Guards nextRunAt and lastRunAt, which event handlers modify concurrently
+	nextRunAtMux sync.Mutex
 }
 
-// RunOnce runs a single iteration of a reconciliation loop.
-func (c *Controller) RunOnce() error {
-	records, err := c.Registry.Records()
+// RunOnce runs a single iteration of a reconciliation loop. It gives up once ctx is done or
+// SyncTimeout passed.
+func (c *Controller) RunOnce(ctx context.Context) error {
+	if c.SyncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.SyncTimeout)
+		defer cancel()
+	}
+
+	plan, err := c.Plan(ctx)
+	if err == nil {
+		// don't start applying changes after a deadline passed during planning
+		err = ctx.Err()
+	}
+	if err != nil {
+		c.Status.recordSync(time.Now(), err)
+		return err
+	}
+
+	diff := c.diff(c.appliedChanges(plan.Changes))
+
+	start := time.Now()
+	err = c.Registry.ApplyChanges(ctx, plan.Changes)
+	now := c.observeStage(StageApply, start, err)
+	c.Status.recordZones(changedZones(diff), now, err)
+	c.Status.recordSync(now, err)
 	if err != nil {
 		registryErrors.Inc()
 		return err
 	}
+
+	c.countAppliedChanges(diff)
+	lastSuccessfulSync.Set(float64(now.Unix()))
+	return nil
+}
+
+// countAppliedChanges counts the changes of the diff per zone, record type and action.
+func (c *Controller) countAppliedChanges(diff *plan.Diff) {
+	for _, entry := range diff.Entries {
+		// replacements are counted once, by their new record
+		if entry.Action != plan.ActionReplace || entry.New != nil {
+			appliedChanges.WithLabelValues(c.Provider, zoneLabel(entry.Zone), entry.RecordType, entry.Action).Inc()
+		}
+	}
+}
+
+// appliedChanges returns the changes the registry applies, e.g. not those of foreign records
+func (c *Controller) appliedChanges(changes *plan.Changes) *plan.Changes {
+	if filter, ok := c.Registry.(registry.ChangesFilter); ok {
+		return filter.FilterChanges(changes)
+	}
+	return changes
+}
+
+// Preview returns the changes by zone which the next synchronization would apply. Unlike Plan
+// it uses a copy of the policy, so that the state of stateful policies, such as tombstones of
+// delayed deletions or blocked deletions, isn't changed by previewing.
+func (c *Controller) Preview(ctx context.Context) (*plan.Diff, error) {
+	p, err := c.plan(ctx, plan.CopyPolicy(c.Policy))
+	if err != nil {
+		return nil, err
+	}
+	return c.diff(c.appliedChanges(p.Changes)), nil
+}
+
+// Plan calculates the changes needed to move the records of the registry towards the
+// endpoints of the source, without applying them.
+func (c *Controller) Plan(ctx context.Context) (*plan.Plan, error) {
+	return c.plan(ctx, c.Policy)
+}
+
+func (c *Controller) plan(ctx context.Context, policy plan.Policy) (*plan.Plan, error) {
+	start := time.Now()
+	records, err := c.Registry.Records(ctx)
+	c.observeStage(StageRegistry, start, err)
+	if err != nil {
+		registryErrors.Inc()
+		return nil, err
+	}
 	registryEndpointsTotal.Set(float64(len(records)))
+	c.countZoneRecords(records)
 
-	endpoints, err := c.Source.Endpoints()
+	start = time.Now()
+	endpoints, err := c.Source.Endpoints(ctx)
+	c.observeStage(StageSource, start, err)
 	if err != nil {
 		sourceErrors.Inc()
-		return err
+		return nil, err
 	}
 	sourceEndpointsTotal.Set(float64(len(endpoints)))
+	endpoints, invalid := filterInvalidEndpoints(endpoints)
+	endpoints = c.Registry.AdjustEndpoints(endpoints)
 
+	start = time.Now()
 	plan := &plan.Plan{
-		Policies: []plan.Policy{c.Policy},
-		Current:  records,
-		Desired:  endpoints,
+		Policies:             []plan.Policy{policy},
+		Resolver:             c.Resolver,
+		ProviderSpecificKeys: c.Registry.ProviderSpecificKeys(),
+		LabelKeys:            c.Registry.LabelKeys(),
+		Current:              records,
+		Desired:              endpoints,
+		Protected:            invalid,
+	}
+	if finder, ok := c.Registry.(registry.OrphanFinder); ok {
+		plan.Orphans = finder.Orphans()
 	}
 
 	plan = plan.Calculate()
+	c.observeStage(StagePlan, start, nil)
+	return plan, nil
+}
 
-	err = c.Registry.ApplyChanges(plan.Changes)
-	if err != nil {
-		registryErrors.Inc()
-		return err
+// observeStage records the duration and result of a stage of a synchronization which started at
+// start, and returns the time it ended.
+func (c *Controller) observeStage(stage string, start time.Time, err error) time.Time {
+	now := time.Now()
+	syncStageDuration.WithLabelValues(stage).Observe(now.Sub(start).Seconds())
+	c.Status.recordStage(stage, now, err)
+	return now
+}
+
+// countZoneRecords updates the number of owned and foreign records per zone
+func (c *Controller) countZoneRecords(records []*endpoint.Endpoint) {
+	registryZoneRecords.Reset()
+	for _, record := range records {
+		ownership := "owned"
+		if record.Labels[endpoint.OwnerLabelKey] != c.OwnerID {
+			ownership = "foreign"
+		}
+		registryZoneRecords.WithLabelValues(zoneLabel(plan.ZoneOf(record.DNSName, c.Zones)), ownership).Inc()
 	}
-	return nil
 }
 
-// Run runs RunOnce in a loop with a delay until stopChan receives a value.
-func (c *Controller) Run(stopChan <-chan struct{}) {
-	ticker := time.NewTicker(c.Interval)
+// diff returns the changes by zone
+func (c *Controller) diff(changes *plan.Changes) *plan.Diff {
+	return plan.NewDiff(changes, c.Zones, c.OwnerID)
+}
+
+// changedZones returns the zones of the entries of the diff
+func changedZones(diff *plan.Diff) []string {
+	seen := map[string]bool{}
+	zones := []string{}
+	for _, entry := range diff.Entries {
+		if !seen[entry.Zone] {
+			seen[entry.Zone] = true
+			zones = append(zones, entry.Zone)
+		}
+	}
+	return zones
+}
+
+// zoneLabel returns the metric label of a zone, records outside of the known zones have an empty zone
+func zoneLabel(zone string) string {
+	if zone == "" {
+		return unknownZone
+	}
+	return zone
+}
+
+// filterInvalidEndpoints separates the endpoints which fail validation, so that a single invalid
+// endpoint isn't rejected by the provider together with the whole batch of changes. The invalid
+// ones are returned as well, so that the records they want aren't deleted either.
+func filterInvalidEndpoints(endpoints []*endpoint.Endpoint) (valid, invalid []*endpoint.Endpoint) {
+	valid = make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if err := ep.Validate(); err != nil {
+			log.Warnf("Dropping invalid %s endpoint %s of %q: %v", ep.RecordType, ep.DNSName, ep.Labels[endpoint.ResourceLabelKey], err)
+			sourceInvalidEndpointsTotal.WithLabelValues(ep.RecordType).Inc()
+			invalid = append(invalid, ep)
+			continue
+		}
+		valid = append(valid, ep)
+	}
+	return valid, invalid
+}
+
+// ScheduleRunOnce makes sure the next synchronization happens no later than now, but no earlier
+// than MinEventSyncInterval after the last one, so that bursts of events are coalesced.
+func (c *Controller) ScheduleRunOnce(now time.Time) {
+	c.nextRunAtMux.Lock()
+	defer c.nextRunAtMux.Unlock()
+
+	next := c.lastRunAt.Add(c.MinEventSyncInterval)
+	if next.Before(now) {
+		next = now
+	}
+	if next.Before(c.nextRunAt) {
+		c.nextRunAt = next
+	}
+}
+
+// ShouldRunOnce returns true if a synchronization is due at now and, if so, schedules the
+// next one after Interval.
+func (c *Controller) ShouldRunOnce(now time.Time) bool {
+	c.nextRunAtMux.Lock()
+	defer c.nextRunAtMux.Unlock()
+
+	if now.Before(c.nextRunAt) {
+		return false
+	}
+	c.lastRunAt = now
+	c.nextRunAt = now.Add(c.Interval)
+	return true
+}
+
+// Run runs RunOnce in a loop until ctx is done, which also cancels an ongoing synchronization.
+// Synchronizations happen every Interval and additionally whenever ScheduleRunOnce asks for one.
+func (c *Controller) Run(ctx context.Context) {
+	c.Status.setRunning(time.Now(), c.Interval)
+	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 	for {
-		err := c.RunOnce()
-		if err != nil {
-			log.Error(err)
+		if c.ShouldRunOnce(time.Now()) {
+			if err := c.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				log.Error(err)
+			}
 		}
 		select {
 		case <-ticker.C:
-		case <-stopChan:
+		case <-ctx.Done():
 			log.Info("Terminating main controller loop")
 			return
 		}
@@ -17,8 +17,10 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/internal/testutils"
@@ -26,6 +28,8 @@ import (
 	"github.com/kubernetes-incubator/external-dns/provider"
 	"github.com/kubernetes-incubator/external-dns/registry"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -37,12 +41,12 @@ type mockProvider struct {
 }
 
 // Records returns the desired mock endpoints.
-func (p *mockProvider) Records() ([]*endpoint.Endpoint, error) {
+func (p *mockProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	return p.RecordsStore, nil
 }
 
 // ApplyChanges validates that the passed in changes satisfy the assumtions.
-func (p *mockProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *mockProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	if len(changes.Create) != len(p.ExpectChanges.Create) {
 		return errors.New("number of created records is wrong")
 	}
@@ -141,8 +145,388 @@ func TestRunOnce(t *testing.T) {
 		Policy:   &plan.SyncPolicy{},
 	}
 
-	assert.NoError(t, ctrl.RunOnce())
+	assert.NoError(t, ctrl.RunOnce(context.Background()))
 
 	// Validate that the mock source was called.
 	source.AssertExpectations(t)
 }
+
+// TestPlan tests that Plan calculates the changes without applying them.
+func TestPlan(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("create-record", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("example.org"))
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+	}
+
+	plan, err := ctrl.Plan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Changes.Create, 1)
+	assert.Equal(t, "create-record", plan.Changes.Create[0].DNSName)
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	source.AssertExpectations(t)
+}
+
+// TestPreview tests that Preview only returns the changes the registry applies.
+func TestPreview(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("update-record.preview.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+	}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("preview.example.org"))
+	r, err := registry.NewTXTRegistry(provider, "", "owner", time.Hour)
+	require.NoError(t, err)
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("update-record.preview.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+	}}))
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foreign.preview.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+	}}))
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+		Zones:    []string{"preview.example.org"},
+		OwnerID:  "owner",
+	}
+
+	diff, err := ctrl.Preview(context.Background())
+	require.NoError(t, err)
+	require.Len(t, diff.Entries, 1, "should not contain the foreign record the registry keeps")
+	assert.Equal(t, plan.ActionUpdate, diff.Entries[0].Action)
+	assert.Equal(t, "update-record.preview.example.org", diff.Entries[0].DNSName)
+}
+
+// TestPreviewKeepsPolicyState tests that Preview doesn't change the state of stateful policies.
+func TestPreviewKeepsPolicyState(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("example.org"))
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+	}}))
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	threshold := &plan.DeletionThresholdPolicy{Policy: &plan.SyncPolicy{}, MaxDeletions: 1}
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   threshold,
+	}
+
+	diff, err := ctrl.Preview(context.Background())
+	require.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+	assert.Nil(t, threshold.Blocked(), "should not block the deletions of the policy")
+
+	_, err = ctrl.Plan(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, threshold.Blocked())
+}
+
+// TestRunOnceStatus tests that RunOnce records the results of the synchronizations in the Status.
+func TestRunOnceStatus(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("create-record.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("example.org"))
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+		Status:   &Status{},
+		Zones:    []string{"example.org"},
+	}
+	ctrl.Status.setRunning(time.Now(), time.Minute)
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.NoError(t, ctrl.Status.Ready())
+	report := ctrl.Status.Report()
+	require.NotNil(t, report.LastSuccessfulSync)
+	for _, stage := range []string{StageSource, StageRegistry, StagePlan, StageApply} {
+		assert.NotNil(t, report.Stages[stage].LastSuccess, stage)
+	}
+	assert.NotNil(t, report.Zones["example.org"].LastSuccess)
+
+	failing := new(testutils.MockSource)
+	failing.On("Endpoints").Return(nil, errors.New("source failed"))
+	ctrl.Source = failing
+
+	require.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Error(t, ctrl.Status.Ready())
+	report = ctrl.Status.Report()
+	assert.Equal(t, "source failed", report.LastSyncError)
+	assert.Equal(t, "source failed", report.Stages[StageSource].LastError)
+	assert.Empty(t, report.Stages[StageApply].LastError)
+}
+
+// metricValue returns the value of a gauge or counter
+func metricValue(t *testing.T, metric prometheus.Metric) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, metric.Write(m))
+	if m.Counter != nil {
+		return m.Counter.GetValue()
+	}
+	return m.Gauge.GetValue()
+}
+
+// TestRunOnceMetrics tests that RunOnce exposes the applied changes and records per zone.
+func TestRunOnceMetrics(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("create-record.metrics.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("metrics.example.org"))
+	r, err := registry.NewTXTRegistry(provider, "", "owner", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foreign.metrics.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+	}}))
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.UpsertOnlyPolicy{},
+		Zones:    []string{"metrics.example.org"},
+		OwnerID:  "owner",
+		Provider: "inmemory",
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 1.0, metricValue(t, appliedChanges.WithLabelValues("inmemory", "metrics.example.org", endpoint.RecordTypeA, plan.ActionCreate)))
+	assert.InDelta(t, float64(time.Now().Unix()), metricValue(t, lastSuccessfulSync), 5)
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 1.0, metricValue(t, registryZoneRecords.WithLabelValues("metrics.example.org", "owned")))
+	assert.Equal(t, 1.0, metricValue(t, registryZoneRecords.WithLabelValues("metrics.example.org", "foreign")))
+}
+
+// TestRunOnceMetricsUpdateDelete tests that RunOnce counts the updates and deletions the registry applied.
+func TestRunOnceMetricsUpdateDelete(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("update-record.changes.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+	}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("changes.example.org"))
+	r, err := registry.NewTXTRegistry(provider, "", "owner", time.Hour)
+	require.NoError(t, err)
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("update-record.changes.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("delete-record.changes.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+	}}))
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foreign.changes.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+	}}))
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+		Zones:    []string{"changes.example.org"},
+		OwnerID:  "owner",
+		Provider: "inmemory",
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 1.0, metricValue(t, appliedChanges.WithLabelValues("inmemory", "changes.example.org", endpoint.RecordTypeA, plan.ActionUpdate)))
+	assert.Equal(t, 1.0, metricValue(t, appliedChanges.WithLabelValues("inmemory", "changes.example.org", endpoint.RecordTypeA, plan.ActionDelete)), "should not count the foreign record the registry kept")
+}
+
+// blockingProvider blocks reading records until the context is done.
+type blockingProvider struct {
+	provider.Provider
+}
+
+func (p blockingProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestRunOnceSyncTimeout tests that RunOnce gives up after SyncTimeout.
+func TestRunOnceSyncTimeout(t *testing.T) {
+	r, err := registry.NewNoopRegistry(blockingProvider{})
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:      new(testutils.MockSource),
+		Registry:    r,
+		Policy:      &plan.SyncPolicy{},
+		SyncTimeout: 10 * time.Millisecond,
+	}
+
+	assert.Equal(t, context.DeadlineExceeded, ctrl.RunOnce(context.Background()))
+}
+
+// TestRunCancel tests that cancelling the context stops Run together with an ongoing synchronization.
+func TestRunCancel(t *testing.T) {
+	r, err := registry.NewNoopRegistry(blockingProvider{})
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:   new(testutils.MockSource),
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+		Interval: time.Minute,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(stopped)
+	}()
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to stop")
+	}
+}
+
+func TestFilterInvalidEndpoints(t *testing.T) {
+	valid := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("valid.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("_sip._tcp.example.org", endpoint.RecordTypeSRV, "10 20 5060 sip.example.org"),
+	}
+	invalid := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("in_valid.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("ipv6.example.org", endpoint.RecordTypeA, "2001:db8::1"),
+		endpoint.NewEndpoint("cname.example.org", endpoint.RecordTypeCNAME, "-bad.example.org"),
+	}
+
+	filteredValid, filteredInvalid := filterInvalidEndpoints(append(valid, invalid...))
+	assert.True(t, testutils.SameEndpoints(valid, filteredValid))
+	assert.True(t, testutils.SameEndpoints(invalid, filteredInvalid))
+}
+
+// TestPlanKeepsRecordsOfInvalidStructuredTargets tests that an endpoint of a DNSEndpoint whose structured
+// targets couldn't be merged is rejected without deleting its existing record.
+func TestPlanKeepsRecordsOfInvalidStructuredTargets(t *testing.T) {
+	invalid := &endpoint.Endpoint{
+		DNSName:    "_sip._tcp.example.org",
+		RecordType: endpoint.RecordTypeSRV,
+		SRVTargets: []endpoint.SRVTarget{endpoint.NewSRVTarget(10, 20, 5060, "")},
+	}
+	require.Error(t, invalid.MergeStructuredTargets())
+
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{invalid}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("example.org"))
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("_sip._tcp.example.org", endpoint.RecordTypeSRV, "10 20 5060 sip.example.org"),
+	}}))
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+	}
+
+	before := metricValue(t, sourceInvalidEndpointsTotal.WithLabelValues(endpoint.RecordTypeSRV))
+	plan, err := ctrl.Plan(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes.Delete)
+	assert.Empty(t, plan.Changes.UpdateNew)
+	assert.Equal(t, before+1, metricValue(t, sourceInvalidEndpointsTotal.WithLabelValues(endpoint.RecordTypeSRV)))
+}
+
+// TestPlanKeepsRecordsOfInvalidEndpoints tests that the records of invalid endpoints aren't deleted.
+func TestPlanKeepsRecordsOfInvalidEndpoints(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("in_valid.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("cname.example.org", endpoint.RecordTypeCNAME, "-bad.example.org"),
+	}, nil)
+
+	provider := provider.NewInMemoryProvider()
+	require.NoError(t, provider.CreateZone("example.org"))
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("in_valid.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("cname.example.org", endpoint.RecordTypeCNAME, "good.example.org"),
+		endpoint.NewEndpoint("gone.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+	}}))
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:   source,
+		Registry: r,
+		Policy:   &plan.SyncPolicy{},
+	}
+
+	plan, err := ctrl.Plan(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes.Create)
+	assert.Empty(t, plan.Changes.UpdateNew)
+	require.Len(t, plan.Changes.Delete, 1)
+	assert.Equal(t, "gone.example.org", plan.Changes.Delete[0].DNSName)
+}
+
+func TestShouldRunOnce(t *testing.T) {
+	ctrl := &Controller{Interval: 10 * time.Minute, MinEventSyncInterval: 5 * time.Second}
+	now := time.Now()
+
+	// the first synchronization happens right away
+	assert.True(t, ctrl.ShouldRunOnce(now))
+	// then not before the interval has passed
+	now = now.Add(time.Second)
+	assert.False(t, ctrl.ShouldRunOnce(now))
+	now = now.Add(10 * time.Minute)
+	assert.True(t, ctrl.ShouldRunOnce(now))
+
+	// events schedule a synchronization no earlier than the minimum interval after the last one
+	ctrl.ScheduleRunOnce(now)
+	assert.False(t, ctrl.ShouldRunOnce(now.Add(time.Second)))
+	now = now.Add(5 * time.Second)
+	assert.True(t, ctrl.ShouldRunOnce(now))
+
+	// and coalesce bursts of events into a single synchronization
+	now = now.Add(time.Minute)
+	ctrl.ScheduleRunOnce(now)
+	ctrl.ScheduleRunOnce(now)
+	assert.True(t, ctrl.ShouldRunOnce(now))
+	assert.False(t, ctrl.ShouldRunOnce(now))
+
+	// events never postpone the next synchronization
+	ctrl.ScheduleRunOnce(now.Add(20 * time.Minute))
+	assert.True(t, ctrl.ShouldRunOnce(now.Add(10*time.Minute)))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+var (
+	leaderElectionLeader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "leader",
+			Help:      "Whether this replica is the leader (1) or a standby (0)",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(leaderElectionLeader)
+}
+
+// LeaderElection makes sure that only one of several replicas of ExternalDNS synchronizes
+// DNS records. The replicas compete for a lock held in a ConfigMap, standby replicas wait
+// until the leader fails to renew it.
+type LeaderElection struct {
+	Client kubernetes.Interface
+	// The namespace and name of the ConfigMap holding the lock
+	Namespace string
+	Name      string
+	// The unique identity of this replica
+	Identity string
+	// The duration standby replicas wait before taking over a lock which wasn't renewed
+	LeaseDuration time.Duration
+	// The duration the leader retries renewing the lock before giving up leadership
+	RenewDeadline time.Duration
+	// The interval between attempts to acquire or renew the lock
+	RetryPeriod time.Duration
+}
+
+// Run waits until this replica becomes the leader and then calls run with a context which is
+// cancelled when either ctx is done or the leadership is lost. It returns nil once run returned
+// because ctx is done, and an error if the leadership was lost.
+func (le *LeaderElection) Run(ctx context.Context, run func(ctx context.Context)) error {
+	lock, err := resourcelock.New(resourcelock.ConfigMapsResourceLock, le.Namespace, le.Name, le.Client.CoreV1(), resourcelock.ResourceLockConfig{
+		Identity:      le.Identity,
+		EventRecorder: logEventRecorder{},
+	})
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: le.LeaseDuration,
+		RenewDeadline: le.RenewDeadline,
+		RetryPeriod:   le.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderStopChan <-chan struct{}) {
+				defer close(done)
+				log.Infof("Became the leader of %s/%s as %s", le.Namespace, le.Name, le.Identity)
+				leaderElectionLeader.Set(1)
+
+				runCtx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				go func() {
+					select {
+					case <-leaderStopChan:
+						cancel()
+					case <-runCtx.Done():
+					}
+				}()
+				run(runCtx)
+			},
+			OnStoppedLeading: func() {
+				leaderElectionLeader.Set(0)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != le.Identity {
+					log.Infof("Waiting as standby, the leader of %s/%s is %s", le.Namespace, le.Name, identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	leaderElectionLeader.Set(0)
+	go elector.Run()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// stopped while waiting as standby
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	default:
+		return errors.New("lost leadership")
+	}
+}
+
+// logEventRecorder logs the events of the leader election instead of recording them in the cluster
+type logEventRecorder struct{}
+
+func (logEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	log.Debugf("Leader election: %s", message)
+}
+
+func (r logEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r logEventRecorder) PastEventf(object runtime.Object, timestamp metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r logEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
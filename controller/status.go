@@ -0,0 +1,273 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Stages of a synchronization reported by the Status
+const (
+	StageSource   = "source"
+	StageRegistry = "registry"
+	StagePlan     = "plan"
+	StageApply    = "apply"
+)
+
+// States of the controller reported by the Status
+const (
+	StateStarting = "starting"
+	StateStandby  = "standby"
+	StateRunning  = "running"
+)
+
+// unknownZone is the key of the records which don't belong to any of the known zones
+const unknownZone = "(unknown)"
+
+// StageStatus is the result of the last runs of a stage or of the changes to a zone
+type StageStatus struct {
+	LastSuccess   *time.Time `json:"lastSuccess,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// StatusReport is a snapshot of the Status
+type StatusReport struct {
+	State              string                 `json:"state"`
+	LastSync           *time.Time             `json:"lastSync,omitempty"`
+	LastSyncError      string                 `json:"lastSyncError,omitempty"`
+	LastSuccessfulSync *time.Time             `json:"lastSuccessfulSync,omitempty"`
+	Stages             map[string]StageStatus `json:"stages"`
+	Zones              map[string]StageStatus `json:"zones"`
+}
+
+// Status tracks the health of the synchronizations of a Controller. It serves a liveness check
+// which fails when the reconciliation loop is stuck, a readiness check which fails when the last
+// synchronization failed, and a JSON report of the last errors per stage and zone.
+type Status struct {
+	// The duration a synchronization may take beyond the interval before the loop is considered stuck,
+	// another interval if zero
+	SyncTimeout time.Duration
+
+	mutex              sync.Mutex
+	state              string
+	interval           time.Duration
+	runningSince       time.Time
+	lastSync           time.Time
+	lastSyncError      error
+	lastSuccessfulSync time.Time
+	stages             map[string]*StageStatus
+	zones              map[string]*StageStatus
+}
+
+// SetStandby marks the controller as waiting, e.g. for the leadership, before running
+func (s *Status) SetStandby() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.state = StateStandby
+}
+
+// setRunning marks the reconciliation loop as started, synchronizing every interval
+func (s *Status) setRunning(now time.Time, interval time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.state = StateRunning
+	s.interval = interval
+	s.runningSince = now
+}
+
+// recordStage records the result of a stage of a synchronization
+func (s *Status) recordStage(stage string, now time.Time, err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stages == nil {
+		s.stages = map[string]*StageStatus{}
+	}
+	recordResult(s.stages, stage, now, err)
+}
+
+// recordZones records the result of applying the changes to the given zones
+func (s *Status) recordZones(zones []string, now time.Time, err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.zones == nil {
+		s.zones = map[string]*StageStatus{}
+	}
+	for _, zone := range zones {
+		if zone == "" {
+			zone = unknownZone
+		}
+		recordResult(s.zones, zone, now, err)
+	}
+}
+
+// recordSync records the result of a whole synchronization
+func (s *Status) recordSync(now time.Time, err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastSync = now
+	s.lastSyncError = err
+	if err == nil {
+		s.lastSuccessfulSync = now
+	}
+}
+
+func recordResult(results map[string]*StageStatus, key string, now time.Time, err error) {
+	result, ok := results[key]
+	if !ok {
+		result = &StageStatus{}
+		results[key] = result
+	}
+	if err != nil {
+		result.LastError = err.Error()
+		result.LastErrorTime = &now
+	} else {
+		result.LastSuccess = &now
+	}
+}
+
+// Live returns an error if the reconciliation loop didn't finish a synchronization within the
+// interval plus the SyncTimeout, or plus another interval if synchronizations don't time out
+func (s *Status) Live(now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.state != StateRunning {
+		return nil
+	}
+	last := s.runningSince
+	if s.lastSync.After(last) {
+		last = s.lastSync
+	}
+	grace := s.SyncTimeout
+	if grace <= 0 {
+		grace = s.interval
+	}
+	if deadline := last.Add(s.interval + grace); now.After(deadline) {
+		return fmt.Errorf("no synchronization finished since %s", last.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Ready returns an error unless the last synchronization succeeded, or the controller waits as standby
+func (s *Status) Ready() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case s.state == StateStandby:
+		return nil
+	case s.state != StateRunning:
+		return errors.New("not running yet")
+	case s.lastSync.IsZero():
+		return errors.New("no synchronization finished yet")
+	case s.lastSyncError != nil:
+		return fmt.Errorf("last synchronization failed: %v", s.lastSyncError)
+	}
+	return nil
+}
+
+// Report returns a snapshot of the Status
+func (s *Status) Report() StatusReport {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	report := StatusReport{
+		State:  s.state,
+		Stages: copyResults(s.stages),
+		Zones:  copyResults(s.zones),
+	}
+	if report.State == "" {
+		report.State = StateStarting
+	}
+	if !s.lastSync.IsZero() {
+		lastSync := s.lastSync
+		report.LastSync = &lastSync
+	}
+	if s.lastSyncError != nil {
+		report.LastSyncError = s.lastSyncError.Error()
+	}
+	if !s.lastSuccessfulSync.IsZero() {
+		lastSuccessfulSync := s.lastSuccessfulSync
+		report.LastSuccessfulSync = &lastSuccessfulSync
+	}
+	return report
+}
+
+func copyResults(results map[string]*StageStatus) map[string]StageStatus {
+	copied := make(map[string]StageStatus, len(results))
+	for key, result := range results {
+		copied[key] = *result
+	}
+	return copied
+}
+
+// ServeLiveness responds with 200 while the reconciliation loop isn't stuck, and with 503 otherwise
+func (s *Status) ServeLiveness(w http.ResponseWriter, r *http.Request) {
+	s.serveCheck(w, s.Live(time.Now()))
+}
+
+// ServeReadiness responds with the time and result of the last synchronization, with 200 if it
+// succeeded and with 503 otherwise
+func (s *Status) ServeReadiness(w http.ResponseWriter, r *http.Request) {
+	s.serveCheck(w, s.Ready())
+}
+
+// ServeHTTP responds with the StatusReport as JSON
+func (s *Status) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Report())
+}
+
+func (s *Status) serveCheck(w http.ResponseWriter, err error) {
+	report := s.Report()
+	status, result := http.StatusOK, "OK"
+	if err != nil {
+		status, result = http.StatusServiceUnavailable, err.Error()
+	}
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "%s (%s)", result, report.State)
+	if report.LastSuccessfulSync != nil {
+		fmt.Fprintf(w, ", last successful synchronization at %s", report.LastSuccessfulSync.Format(time.RFC3339))
+	}
+	fmt.Fprintln(w)
+}
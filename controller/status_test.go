@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusLive(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	status := &Status{SyncTimeout: 5 * time.Minute}
+
+	// not running, e.g. waiting for the leadership
+	assert.NoError(t, status.Live(now.Add(time.Hour)))
+	status.SetStandby()
+	assert.NoError(t, status.Live(now.Add(time.Hour)))
+
+	status.setRunning(now, time.Minute)
+	assert.NoError(t, status.Live(now.Add(6*time.Minute)))
+	assert.Error(t, status.Live(now.Add(7*time.Minute)))
+
+	// failed synchronizations show that the loop isn't stuck
+	status.recordSync(now.Add(5*time.Minute), errors.New("failed"))
+	assert.NoError(t, status.Live(now.Add(11*time.Minute)))
+	assert.Error(t, status.Live(now.Add(12*time.Minute)))
+}
+
+func TestStatusLiveWithoutSyncTimeout(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	status := &Status{}
+
+	// synchronizations get another interval to finish
+	status.setRunning(now, time.Minute)
+	assert.NoError(t, status.Live(now.Add(90*time.Second)))
+	assert.NoError(t, status.Live(now.Add(2*time.Minute)))
+	assert.Error(t, status.Live(now.Add(3*time.Minute)))
+}
+
+func TestStatusReady(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	status := &Status{}
+	assert.Error(t, status.Ready())
+
+	status.SetStandby()
+	assert.NoError(t, status.Ready())
+
+	status.setRunning(now, time.Minute)
+	assert.Error(t, status.Ready())
+
+	status.recordSync(now, nil)
+	assert.NoError(t, status.Ready())
+
+	status.recordSync(now.Add(time.Minute), errors.New("failed"))
+	assert.EqualError(t, status.Ready(), "last synchronization failed: failed")
+	assert.Equal(t, now, *status.Report().LastSuccessfulSync)
+}
+
+func TestStatusReport(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	status := &Status{}
+	assert.Equal(t, StatusReport{State: StateStarting, Stages: map[string]StageStatus{}, Zones: map[string]StageStatus{}}, status.Report())
+
+	status.setRunning(now, time.Minute)
+	status.recordStage(StageSource, now, nil)
+	status.recordStage(StageApply, now, errors.New("throttled"))
+	status.recordZones([]string{"example.org", ""}, now, errors.New("throttled"))
+	status.recordSync(now, errors.New("throttled"))
+
+	report := status.Report()
+	assert.Equal(t, StateRunning, report.State)
+	assert.Equal(t, now, *report.LastSync)
+	assert.Nil(t, report.LastSuccessfulSync)
+	assert.Equal(t, "throttled", report.LastSyncError)
+	assert.Equal(t, StageStatus{LastSuccess: &now}, report.Stages[StageSource])
+	assert.Equal(t, StageStatus{LastError: "throttled", LastErrorTime: &now}, report.Stages[StageApply])
+	assert.Equal(t, StageStatus{LastError: "throttled", LastErrorTime: &now}, report.Zones["example.org"])
+	assert.Equal(t, StageStatus{LastError: "throttled", LastErrorTime: &now}, report.Zones[unknownZone])
+}
+
+func TestStatusHandlers(t *testing.T) {
+	status := &Status{SyncTimeout: time.Minute}
+	status.setRunning(time.Now(), time.Minute)
+
+	get := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, get(status.ServeLiveness).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get(status.ServeReadiness).Code)
+
+	status.recordSync(time.Now(), nil)
+	ready := get(status.ServeReadiness)
+	assert.Equal(t, http.StatusOK, ready.Code)
+	assert.Contains(t, ready.Body.String(), "last successful synchronization at")
+
+	recorder := get(status.ServeHTTP)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	report := StatusReport{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, StateRunning, report.State)
+	assert.NotNil(t, report.LastSuccessfulSync)
+}
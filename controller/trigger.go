@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// Triggers of on-demand synchronizations
+const (
+	TriggerHTTP   = "http"
+	TriggerSignal = "signal"
+)
+
+var (
+	triggeredSyncs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "triggers_total",
+			Help:      "Number of on-demand synchronizations requested, by trigger",
+		},
+		[]string{"trigger"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(triggeredSyncs)
+}
+
+// Trigger requests a synchronization on demand. Like events, triggers which arrive within
+// MinEventSyncInterval of the last synchronization are coalesced into a single one.
+func (c *Controller) Trigger(trigger string) {
+	log.Infof("Synchronization triggered by %s", trigger)
+	triggeredSyncs.WithLabelValues(trigger).Inc()
+	c.ScheduleRunOnce(time.Now())
+}
+
+// TriggerHandler triggers a synchronization of the Controller on POST requests which present
+// the Token as bearer token.
+type TriggerHandler struct {
+	Controller *Controller
+	Token      string
+}
+
+// ServeHTTP responds with 202 once the synchronization is scheduled
+func (h *TriggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.Controller.Trigger(TriggerHTTP)
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintln(w, "synchronization scheduled")
+}
+
+// authorized returns true if the request presents the token, an empty token authorizes nothing
+func (h *TriggerHandler) authorized(r *http.Request) bool {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if h.Token == "" || !strings.HasPrefix(auth, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, prefix)), []byte(h.Token)) == 1
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerHandler(t *testing.T) {
+	for _, tc := range []struct {
+		title  string
+		method string
+		token  string
+		auth   string
+		status int
+	}{
+		{"valid token", http.MethodPost, "secret", "Bearer secret", http.StatusAccepted},
+		{"wrong method", http.MethodGet, "secret", "Bearer secret", http.StatusMethodNotAllowed},
+		{"missing token", http.MethodPost, "secret", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "secret", "Bearer other", http.StatusUnauthorized},
+		{"wrong scheme", http.MethodPost, "secret", "Basic secret", http.StatusUnauthorized},
+		{"empty token", http.MethodPost, "", "Bearer ", http.StatusUnauthorized},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			ctrl := &Controller{Interval: time.Hour}
+			// the first synchronization happened, the next one is due after the interval
+			assert.True(t, ctrl.ShouldRunOnce(time.Now()))
+
+			req := httptest.NewRequest(tc.method, "/trigger", nil)
+			if tc.auth != "" {
+				req.Header.Set("Authorization", tc.auth)
+			}
+			rec := httptest.NewRecorder()
+			(&TriggerHandler{Controller: ctrl, Token: tc.token}).ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, tc.status == http.StatusAccepted, ctrl.ShouldRunOnce(time.Now()))
+		})
+	}
+}
+
+func TestTriggerCoalesced(t *testing.T) {
+	ctrl := &Controller{Interval: time.Hour, MinEventSyncInterval: time.Minute}
+	assert.True(t, ctrl.ShouldRunOnce(time.Now()))
+
+	// triggers shortly after a synchronization wait for the minimum interval
+	ctrl.Trigger(TriggerSignal)
+	ctrl.Trigger(TriggerHTTP)
+	assert.False(t, ctrl.ShouldRunOnce(time.Now()))
+	assert.True(t, ctrl.ShouldRunOnce(time.Now().Add(time.Minute)))
+	assert.False(t, ctrl.ShouldRunOnce(time.Now().Add(time.Minute)))
+}
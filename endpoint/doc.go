@@ -0,0 +1,22 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package endpoint contains the records ExternalDNS manages and the DNSEndpoint custom resource.
+// The deep copy functions of all its types, e.g. of TargetSet, are generated into
+// zz_generated.deepcopy.go by deepcopy-gen.
+//
+// +k8s:deepcopy-gen=package
+package endpoint
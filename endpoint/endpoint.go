@@ -27,12 +27,22 @@ import (
 const (
 	// RecordTypeA is a RecordType enum value
 	RecordTypeA = "A"
+	// RecordTypeAAAA is a RecordType enum value
+	RecordTypeAAAA = "AAAA"
 	// RecordTypeCNAME is a RecordType enum value
 	RecordTypeCNAME = "CNAME"
 	// RecordTypeTXT is a RecordType enum value
 	RecordTypeTXT = "TXT"
 	// RecordTypeSRV is a RecordType enum value
 	RecordTypeSRV = "SRV"
+	// RecordTypeMX is a RecordType enum value
+	RecordTypeMX = "MX"
+	// RecordTypeNS is a RecordType enum value
+	RecordTypeNS = "NS"
+	// RecordTypeCAA is a RecordType enum value
+	RecordTypeCAA = "CAA"
+	// RecordTypePTR is a RecordType enum value
+	RecordTypePTR = "PTR"
 )
 
 // TTL is a structure defining the TTL of a DNS record
@@ -69,46 +79,46 @@ func (t Targets) Swap(i, j int) {
 	t[i], t[j] = t[j], t[i]
 }
 
-// Same compares to Targets and returns true if they are completely identical
+// Same compares to Targets and returns true if they contain exactly the same targets, regardless of their order.
+// Neither of the Targets is modified. Use TargetSet to compare targets in their canonical form.
 func (t Targets) Same(o Targets) bool {
 	if len(t) != len(o) {
 		return false
 	}
-	sort.Stable(t)
-	sort.Stable(o)
+	sortedT, sortedO := t.sorted(), o.sorted()
 
-	for i, e := range t {
-		if e != o[i] {
+	for i, e := range sortedT {
+		if e != sortedO[i] {
 			return false
 		}
 	}
 	return true
 }
 
-// IsLess should fulfill the requirement to compare two targets and chosse the 'lesser' one.
-// In the past target was a simple string so simple string comparison could be used. Now we define 'less'
-// as either being the shorter list of targets or where the first entry is less.
-// FIXME We really need to define under which circumstances a list Targets is considered 'less'
-// than another.
+// IsLess defines a total ordering of Targets: the shorter list of targets is less and lists of the same
+// length are compared target by target in sorted order. Neither of the Targets is modified.
 func (t Targets) IsLess(o Targets) bool {
-	if len(t) < len(o) {
-		return true
-	}
-	if len(t) > len(o) {
-		return false
+	if len(t) != len(o) {
+		return len(t) < len(o)
 	}
+	sortedT, sortedO := t.sorted(), o.sorted()
 
-	sort.Sort(t)
-	sort.Sort(o)
-
-	for i, e := range t {
-		if e != o[i] {
-			return e < o[i]
+	for i, e := range sortedT {
+		if e != sortedO[i] {
+			return e < sortedO[i]
 		}
 	}
 	return false
 }
 
+// sorted returns a sorted copy of the targets
+func (t Targets) sorted() Targets {
+	sorted := make(Targets, len(t))
+	copy(sorted, t)
+	sort.Strings(sorted)
+	return sorted
+}
+
 // ProviderSpecific holds configuration which is specific to individual DNS providers
 type ProviderSpecific map[string]string
 
@@ -128,6 +138,12 @@ type Endpoint struct {
 	// ProviderSpecific stores provider specific config
 	// +optional
 	ProviderSpecific ProviderSpecific `json:"providerSpecific,omitempty"`
+	// SRVTargets are the targets of an SRV record in structured form, see MergeStructuredTargets
+	// +optional
+	SRVTargets []SRVTarget `json:"srvTargets,omitempty"`
+	// MXTargets are the targets of an MX record in structured form, see MergeStructuredTargets
+	// +optional
+	MXTargets []MXTarget `json:"mxTargets,omitempty"`
 }
 
 // NewEndpoint initialization method to be used to create an endpoint
@@ -143,7 +159,7 @@ func NewEndpointWithTTL(dnsName, recordType string, ttl TTL, targets ...string)
 	}
 
 	return &Endpoint{
-		DNSName:    strings.TrimSuffix(dnsName, "."),
+		DNSName:    NormalizeDNSName(strings.TrimSuffix(dnsName, ".")),
 		Targets:    cleanTargets,
 		RecordType: recordType,
 		Labels:     NewLabels(),
@@ -164,8 +180,48 @@ func (e *Endpoint) WithProviderSpecific(key, value string) *Endpoint {
 	return e
 }
 
+// MergeStructuredTargets appends the structured SRV and MX targets to Targets in their textual form,
+// which is the only form used for planning and by the providers. Structured targets can be specified
+// in the DNSEndpoint CRD, an error is returned if any of them is invalid or doesn't match the record type.
+// The endpoint is left unchanged then, so that Validate reports the error as well.
+func (e *Endpoint) MergeStructuredTargets() error {
+	if err := e.validateStructuredTargets(); err != nil {
+		return err
+	}
+	for _, srv := range e.SRVTargets {
+		e.Targets = append(e.Targets, strings.TrimSuffix(srv.String(), "."))
+	}
+	for _, mx := range e.MXTargets {
+		e.Targets = append(e.Targets, strings.TrimSuffix(mx.String(), "."))
+	}
+	e.SRVTargets = nil
+	e.MXTargets = nil
+	return nil
+}
+
+// validateStructuredTargets checks that the structured targets are valid and match the record type
+func (e *Endpoint) validateStructuredTargets() error {
+	if len(e.SRVTargets) > 0 && e.RecordType != RecordTypeSRV {
+		return fmt.Errorf("SRV targets given for %s record %s", e.RecordType, e.DNSName)
+	}
+	if len(e.MXTargets) > 0 && e.RecordType != RecordTypeMX {
+		return fmt.Errorf("MX targets given for %s record %s", e.RecordType, e.DNSName)
+	}
+	for _, srv := range e.SRVTargets {
+		if err := srv.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, mx := range e.MXTargets {
+		if err := mx.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *Endpoint) String() string {
-	return fmt.Sprintf("%s %d IN %s %s %s", e.DNSName, e.RecordTTL, e.RecordType, e.Targets, e.ProviderSpecific)
+	return fmt.Sprintf("%s %d IN %s %s %s", displayDNSName(e.DNSName), e.RecordTTL, e.RecordType, e.Targets, e.ProviderSpecific)
 }
 
 // DNSEndpointSpec defines the desired state of DNSEndpoint
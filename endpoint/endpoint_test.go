@@ -75,3 +75,36 @@ func TestSameFailures(t *testing.T) {
 		}
 	}
 }
+
+func TestTargetsComparisonDoesNotModifyTargets(t *testing.T) {
+	a := Targets{"4.3.2.1", "1.2.3.4"}
+	b := Targets{"1.2.3.4", "4.3.2.1"}
+
+	if !a.Same(b) {
+		t.Errorf("%#v should equal %#v", a, b)
+	}
+	if a.IsLess(b) || b.IsLess(a) {
+		t.Errorf("%#v and %#v should not be less than each other", a, b)
+	}
+	if a[0] != "4.3.2.1" || b[0] != "1.2.3.4" {
+		t.Errorf("targets have been reordered: %#v, %#v", a, b)
+	}
+}
+
+func TestTargetsIsLess(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     Targets
+		expected bool
+	}{
+		{Targets{"1.2.3.4"}, Targets{"1.2.3.4"}, false},
+		{Targets{"1.2.3.4"}, Targets{"4.3.2.1"}, true},
+		{Targets{"4.3.2.1"}, Targets{"1.2.3.4"}, false},
+		{Targets{"4.3.2.1"}, Targets{"1.2.3.4", "4.3.2.1"}, true},
+		{Targets{"1.2.3.4", "4.3.2.1"}, Targets{"4.3.2.1"}, false},
+		{Targets{"4.3.2.1", "1.2.3.4"}, Targets{"1.2.3.4", "5.6.7.8"}, true},
+	} {
+		if actual := tc.a.IsLess(tc.b); actual != tc.expected {
+			t.Errorf("expected %#v.IsLess(%#v) to be %v", tc.a, tc.b, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// idnaProfile converts between the Unicode and ASCII forms of internationalized domain names.
+// Unlike idna.Lookup it doesn't enforce the STD3 rules, so that service labels such as
+// "_sip" and wildcards keep working.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.StrictDomainName(false),
+	idna.Transitional(false),
+)
+
+// NormalizeDNSName returns the ASCII (punycode) form of an internationalized DNS name, e.g.
+// "xn--bcher-kva.example.org" for "bücher.example.org". Names which are already ASCII or
+// can't be converted are returned unchanged, the latter are rejected by ValidateDNSName.
+func NormalizeDNSName(name string) string {
+	if isASCII(name) {
+		return name
+	}
+	ascii, err := idnaProfile.ToASCII(name)
+	if err != nil {
+		return name
+	}
+	return ascii
+}
+
+// UnicodeDNSName returns the Unicode form of a DNS name given in its ASCII (punycode) form.
+// Names without punycode labels are returned unchanged.
+func UnicodeDNSName(name string) string {
+	if !strings.Contains(strings.ToLower(name), "xn--") {
+		return name
+	}
+	unicode, err := idnaProfile.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+	return unicode
+}
+
+// displayDNSName returns the DNS name as shown in logs, which includes the Unicode form
+// for internationalized domain names.
+func displayDNSName(name string) string {
+	if unicode := UnicodeDNSName(name); unicode != name {
+		return fmt.Sprintf("%s (%s)", name, unicode)
+	}
+	return name
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDNSName(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"example.org", "example.org"},
+		{"Example.org", "Example.org"},
+		{"bücher.example.org", "xn--bcher-kva.example.org"},
+		{"Bücher.example.org", "xn--bcher-kva.example.org"},
+		{"bücher.example.org.", "xn--bcher-kva.example.org."},
+		{"*.bücher.example.org", "*.xn--bcher-kva.example.org"},
+		{"_sip._tcp.bücher.example.org", "_sip._tcp.xn--bcher-kva.example.org"},
+		{"xn--bcher-kva.example.org", "xn--bcher-kva.example.org"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+	} {
+		if actual := NormalizeDNSName(tc.name); actual != tc.expected {
+			t.Errorf("expected %q to be normalized to %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestUnicodeDNSName(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"example.org", "example.org"},
+		{"Example.org", "Example.org"},
+		{"xn--bcher-kva.example.org", "bücher.example.org"},
+		{"xn--r8jz45g.xn--zckzah", "例え.テスト"},
+	} {
+		if actual := UnicodeDNSName(tc.name); actual != tc.expected {
+			t.Errorf("expected %q to be converted to %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestNewEndpointNormalizesDNSName(t *testing.T) {
+	e := NewEndpoint("bücher.example.org.", RecordTypeA, "1.2.3.4")
+	if e.DNSName != "xn--bcher-kva.example.org" {
+		t.Errorf("expected DNS name to be normalized, got %q", e.DNSName)
+	}
+	if s := e.String(); !strings.HasPrefix(s, "xn--bcher-kva.example.org (bücher.example.org) 0 IN A 1.2.3.4") {
+		t.Errorf("expected both forms of the DNS name in %q", s)
+	}
+}
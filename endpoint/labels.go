@@ -19,6 +19,7 @@ package endpoint
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 )
@@ -26,14 +27,31 @@ import (
 var (
 	// ErrInvalidHeritage is returned when heritage was not found, or different heritage is found
 	ErrInvalidHeritage = errors.New("heritage is unknown or not found")
+	// ErrUnknownLabelsVersion is returned when the labels are in a format of a newer version
+	ErrUnknownLabelsVersion = errors.New("version of the labels format is unknown")
 )
 
 const (
 	heritage = "external-dns"
+	// labelsVersion is the version of the format written by SerializeTXT. Version 1, written by
+	// Serialize, has no version marker and doesn't escape the keys and values.
+	labelsVersion = "2"
+	// maxTXTStringLength is the maximum length of a single string of a TXT record
+	maxTXTStringLength = 255
+	// signatureToken is the token of the versioned format which holds the signature of the tokens before it
+	signatureToken = ",signature="
 	// OwnerLabelKey is the name of the label that defines the owner of an Endpoint.
 	OwnerLabelKey = "owner"
 	// ResourceLabelKey is the name of the label that identifies k8s resource which wants to acquire the DNS name
 	ResourceLabelKey = "resource"
+	// CreationTimestampLabelKey is the name of the label that holds the creation time (RFC 3339) of the k8s resource
+	CreationTimestampLabelKey = "creation-timestamp"
+	// ConflictPriorityLabelKey is the name of the label that holds the priority of the k8s resource in case of conflicts
+	ConflictPriorityLabelKey = "conflict-priority"
+	// TombstoneLabelKey is the name of the label that holds the time (RFC 3339) since which a record is missing from the desired state
+	TombstoneLabelKey = "tombstone"
+	// DampingLabelKey is the name of the label that opts a record out of change damping when set to "false"
+	DampingLabelKey = "damping"
 
 	// AWSSDDescriptionLabel label responsible for storing raw owner/resource combination information in the Labels
 	// supposed to be inserted by AWS SD Provider, and parsed into OwnerLabelKey and ResourceLabelKey key by AWS SD Registry
@@ -52,7 +70,18 @@ func NewLabels() Labels {
 // NewLabelsFromString constructs endpoints labels from a provided format string
 // if heritage set to another value is found then error is returned
 // no heritage automatically assumes is not owned by external-dns and returns invalidHeritage error
+// Both the format written by Serialize and the versioned one written by SerializeTXT are supported.
 func NewLabelsFromString(labelText string) (Labels, error) {
+	joined := joinTXTStrings(labelText)
+	for _, token := range strings.Split(joined, ",") {
+		if strings.HasPrefix(token, "version=") {
+			if token != "version="+labelsVersion {
+				return nil, ErrUnknownLabelsVersion
+			}
+			return newLabelsFromVersionedString(joined)
+		}
+	}
+
 	endpointLabels := map[string]string{}
 	labelText = strings.Trim(labelText, "\"") // drop quotes
 	tokens := strings.Split(labelText, ",")
@@ -82,18 +111,139 @@ func NewLabelsFromString(labelText string) (Labels, error) {
 	return endpointLabels, nil
 }
 
-// Serialize transforms endpoints labels into a external-dns recognizable format string
-// withQuotes adds additional quotes
-func (l Labels) Serialize(withQuotes bool) string {
-	var tokens []string
-	tokens = append(tokens, fmt.Sprintf("heritage=%s", heritage))
+// newLabelsFromVersionedString parses the format written by SerializeTXT
+func newLabelsFromVersionedString(labelText string) (Labels, error) {
+	endpointLabels := map[string]string{}
+	foundExternalDNSHeritage := false
+	for _, token := range strings.Split(labelText, ",") {
+		parts := strings.SplitN(token, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, err := url.PathUnescape(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid label key %q: %v", parts[0], err)
+		}
+		val, err := url.PathUnescape(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of label %q: %v", key, err)
+		}
+		if key == "heritage" {
+			if val != heritage {
+				return nil, ErrInvalidHeritage
+			}
+			foundExternalDNSHeritage = true
+			continue
+		}
+		if strings.HasPrefix(key, heritage+"/") {
+			endpointLabels[strings.TrimPrefix(key, heritage+"/")] = val
+		}
+	}
+
+	if !foundExternalDNSHeritage {
+		return nil, ErrInvalidHeritage
+	}
+
+	return endpointLabels, nil
+}
+
+// joinTXTStrings joins the quoted strings of a TXT record value, e.g. "abc" "def", and drops
+// the quotes of a single string. Values which aren't quoted strings are returned as they are.
+func joinTXTStrings(text string) string {
+	if len(text) < 2 || !strings.HasPrefix(text, "\"") || !strings.HasSuffix(text, "\"") {
+		return strings.Trim(text, "\"")
+	}
+	// the parts alternate between the contents of the strings and the whitespace between them
+	parts := strings.Split(text[1:len(text)-1], "\"")
+	if len(parts)%2 == 0 {
+		return strings.Trim(text, "\"")
+	}
+	contents := make([]string, 0, len(parts)/2+1)
+	for i, part := range parts {
+		if i%2 == 1 {
+			if strings.TrimSpace(part) != "" {
+				return strings.Trim(text, "\"")
+			}
+			continue
+		}
+		contents = append(contents, part)
+	}
+	return strings.Join(contents, "")
+}
+
+// SerializeTXT transforms endpoints labels into the value of a TXT record in the versioned format:
+// keys and values are escaped, and values longer than a single TXT string are split into several.
+func (l Labels) SerializeTXT() string {
+	return quoteTXTStrings(l.txtPayload())
+}
+
+// SerializeSignedTXT transforms endpoints labels into the value of a TXT record like SerializeTXT,
+// followed by a signature token holding the result of sign for the preceding text
+func (l Labels) SerializeSignedTXT(sign func(payload string) string) string {
+	payload := l.txtPayload()
+	return quoteTXTStrings(payload + signatureToken + sign(payload))
+}
+
+// SplitTXTSignature returns the text of a TXT record value in the versioned format which is
+// covered by its signature, and the signature. The signature is empty if the value has none.
+func SplitTXTSignature(labelText string) (payload, signature string) {
+	joined := joinTXTStrings(labelText)
+	if i := strings.LastIndex(joined, signatureToken); i >= 0 {
+		return joined[:i], joined[i+len(signatureToken):]
+	}
+	return joined, ""
+}
+
+// txtPayload returns the tokens of the versioned format, unquoted
+func (l Labels) txtPayload() string {
+	tokens := []string{"heritage=" + heritage, "version=" + labelsVersion}
+	for _, key := range l.sortedKeys() {
+		tokens = append(tokens, escapeLabel(heritage+"/"+key)+"="+escapeLabel(l[key]))
+	}
+	return strings.Join(tokens, ",")
+}
+
+// quoteTXTStrings splits the text into quoted strings which fit into a TXT record
+func quoteTXTStrings(text string) string {
+	var quoted []string
+	for len(text) > maxTXTStringLength {
+		quoted = append(quoted, "\""+text[:maxTXTStringLength]+"\"")
+		text = text[maxTXTStringLength:]
+	}
+	quoted = append(quoted, "\""+text+"\"")
+	return strings.Join(quoted, " ")
+}
+
+// escapeLabel percent-encodes the separators of the versioned format and everything but printable ASCII
+func escapeLabel(text string) string {
+	escaped := make([]byte, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c <= ' ' || c > '~' || strings.IndexByte(`%,="\`, c) >= 0 {
+			escaped = append(escaped, fmt.Sprintf("%%%02X", c)...)
+		} else {
+			escaped = append(escaped, c)
+		}
+	}
+	return string(escaped)
+}
+
+func (l Labels) sortedKeys() []string {
 	var keys []string
 	for key := range l {
 		keys = append(keys, key)
 	}
 	sort.Strings(keys) // sort for consistency
+	return keys
+}
 
-	for _, key := range keys {
+// Serialize transforms endpoints labels into a external-dns recognizable format string
+// withQuotes adds additional quotes
+// The format has no version and no escaping, ownership records are written with SerializeTXT.
+func (l Labels) Serialize(withQuotes bool) string {
+	var tokens []string
+	tokens = append(tokens, fmt.Sprintf("heritage=%s", heritage))
+	for _, key := range l.sortedKeys() {
 		tokens = append(tokens, fmt.Sprintf("%s/%s=%s", heritage, key, l[key]))
 	}
 	if withQuotes {
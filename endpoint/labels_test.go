@@ -18,6 +18,7 @@ package endpoint
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -87,6 +88,73 @@ func (suite *LabelsSuite) TestDeserialize() {
 	suite.Nil(multipleHeritage, "if error should return nil")
 }
 
+func (suite *LabelsSuite) TestSerializeTXT() {
+	suite.Equal(`"heritage=external-dns,version=2,external-dns/owner=foo-owner,external-dns/resource=foo-resource"`, suite.foo.SerializeTXT())
+
+	special := Labels{
+		"owner":    "foo,owner=1",
+		"resource": "ingress/default/\"quoted\" 100%",
+		"unicode":  "bücher",
+	}
+	text := special.SerializeTXT()
+	suite.Equal(`"heritage=external-dns,version=2,external-dns/owner=foo%2Cowner%3D1,external-dns/resource=ingress/default/%22quoted%22%20100%25,external-dns/unicode=b%C3%BCcher"`, text)
+	labels, err := NewLabelsFromString(text)
+	suite.NoError(err)
+	suite.Equal(special, labels, "should reconstruct labels with separators")
+
+	// the owner is still readable by the unversioned parser
+	legacy := Labels{"owner": "foo-owner", "resource": "ingress/default/foo"}.SerializeTXT()
+	suite.True(strings.Contains(legacy, ",external-dns/owner=foo-owner,"))
+}
+
+func (suite *LabelsSuite) TestSerializeTXTLong() {
+	long := Labels{"owner": "foo-owner", "resource": strings.Repeat("r", 600)}
+	text := long.SerializeTXT()
+	strs := strings.Split(strings.Trim(text, `"`), `" "`)
+	suite.Len(strs, 3, "should be split into several strings")
+	for _, str := range strs {
+		suite.True(len(str) <= 255, "should fit into a TXT string")
+	}
+
+	labels, err := NewLabelsFromString(text)
+	suite.NoError(err)
+	suite.Equal(long, labels, "should join the strings")
+}
+
+func (suite *LabelsSuite) TestDeserializeVersioned() {
+	labels, err := NewLabelsFromString(`"heritage=external-dns,version=2,external-dns/owner=foo-owner" "` + `,external-dns/resource=foo-resource"`)
+	suite.NoError(err)
+	suite.Equal(suite.foo, labels)
+
+	_, err = NewLabelsFromString(`"heritage=mate,version=2,external-dns/owner=foo-owner"`)
+	suite.Equal(ErrInvalidHeritage, err, "should fail if wrong heritage is found")
+
+	_, err = NewLabelsFromString(`"heritage=external-dns,version=3,external-dns/owner=foo-owner"`)
+	suite.Equal(ErrUnknownLabelsVersion, err, "should fail for unknown versions")
+
+	_, err = NewLabelsFromString(`"heritage=external-dns,version=2,external-dns/owner=foo%2"`)
+	suite.Error(err, "should fail for invalid escapes")
+}
+
+func (suite *LabelsSuite) TestSerializeSignedTXT() {
+	text := suite.foo.SerializeSignedTXT(func(payload string) string {
+		return strings.ToUpper(payload[:5])
+	})
+	suite.Equal(`"heritage=external-dns,version=2,external-dns/owner=foo-owner,external-dns/resource=foo-resource,signature=HERIT"`, text)
+
+	labels, err := NewLabelsFromString(text)
+	suite.NoError(err)
+	suite.Equal(suite.foo, labels, "should ignore the signature")
+
+	payload, signature := SplitTXTSignature(text)
+	suite.Equal(strings.Trim(suite.foo.SerializeTXT(), `"`), payload)
+	suite.Equal("HERIT", signature)
+
+	payload, signature = SplitTXTSignature(suite.foo.SerializeTXT())
+	suite.Equal(strings.Trim(suite.foo.SerializeTXT(), `"`), payload)
+	suite.Empty(signature, "should have no signature")
+}
+
 func TestLabels(t *testing.T) {
 	suite.Run(t, new(LabelsSuite))
 }
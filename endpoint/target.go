@@ -0,0 +1,141 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SRVTarget is the structured form of the target of an SRV record, see RFC 2782.
+// Its textual form, as stored in Targets, is "<priority> <weight> <port> <target>".
+type SRVTarget struct {
+	Priority uint16 `json:"priority"`
+	Weight   uint16 `json:"weight"`
+	Port     uint16 `json:"port"`
+	Target   string `json:"target"`
+}
+
+// NewSRVTarget returns a new SRVTarget
+func NewSRVTarget(priority, weight, port uint16, target string) SRVTarget {
+	return SRVTarget{
+		Priority: priority,
+		Weight:   weight,
+		Port:     port,
+		Target:   target,
+	}
+}
+
+// ParseSRVTarget parses the textual form of the target of an SRV record
+func ParseSRVTarget(s string) (SRVTarget, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 {
+		return SRVTarget{}, fmt.Errorf("invalid SRV target %q: expected \"<priority> <weight> <port> <target>\"", s)
+	}
+	values := make([]uint16, 3)
+	for i, name := range []string{"priority", "weight", "port"} {
+		value, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return SRVTarget{}, fmt.Errorf("invalid SRV target %q: %q is not a valid %s", s, fields[i], name)
+		}
+		values[i] = uint16(value)
+	}
+	srv := NewSRVTarget(values[0], values[1], values[2], fields[3])
+	return srv, srv.Validate()
+}
+
+// Validate checks that the SRVTarget has a target host
+func (t SRVTarget) Validate() error {
+	if strings.TrimSpace(t.Target) == "" {
+		return fmt.Errorf("invalid SRV target: target host is empty")
+	}
+	return nil
+}
+
+// String returns the textual form of the SRVTarget
+func (t SRVTarget) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Priority, t.Weight, t.Port, t.Target)
+}
+
+// MXTarget is the structured form of the target of an MX record, see RFC 1035.
+// Its textual form, as stored in Targets, is "<preference> <exchange>".
+type MXTarget struct {
+	Preference uint16 `json:"preference"`
+	Exchange   string `json:"exchange"`
+}
+
+// NewMXTarget returns a new MXTarget
+func NewMXTarget(preference uint16, exchange string) MXTarget {
+	return MXTarget{
+		Preference: preference,
+		Exchange:   exchange,
+	}
+}
+
+// ParseMXTarget parses the textual form of the target of an MX record
+func ParseMXTarget(s string) (MXTarget, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return MXTarget{}, fmt.Errorf("invalid MX target %q: expected \"<preference> <exchange>\"", s)
+	}
+	preference, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return MXTarget{}, fmt.Errorf("invalid MX target %q: %q is not a valid preference", s, fields[0])
+	}
+	mx := NewMXTarget(uint16(preference), fields[1])
+	return mx, mx.Validate()
+}
+
+// Validate checks that the MXTarget has an exchange host
+func (t MXTarget) Validate() error {
+	if strings.TrimSpace(t.Exchange) == "" {
+		return fmt.Errorf("invalid MX target: exchange host is empty")
+	}
+	return nil
+}
+
+// String returns the textual form of the MXTarget
+func (t MXTarget) String() string {
+	return fmt.Sprintf("%d %s", t.Preference, t.Exchange)
+}
+
+// SRV parses all targets as targets of an SRV record
+func (t Targets) SRV() ([]SRVTarget, error) {
+	srvTargets := make([]SRVTarget, 0, len(t))
+	for _, target := range t {
+		srv, err := ParseSRVTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		srvTargets = append(srvTargets, srv)
+	}
+	return srvTargets, nil
+}
+
+// MX parses all targets as targets of an MX record
+func (t Targets) MX() ([]MXTarget, error) {
+	mxTargets := make([]MXTarget, 0, len(t))
+	for _, target := range t {
+		mx, err := ParseMXTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		mxTargets = append(mxTargets, mx)
+	}
+	return mxTargets, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSRVTarget(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected SRVTarget
+		valid    bool
+	}{
+		{"10 20 5060 sip.example.org", NewSRVTarget(10, 20, 5060, "sip.example.org"), true},
+		{" 0  50 30000   node.example.org ", NewSRVTarget(0, 50, 30000, "node.example.org"), true},
+		{"10 20 5060", SRVTarget{}, false},
+		{"10 20 5060 sip.example.org extra", SRVTarget{}, false},
+		{"-1 20 5060 sip.example.org", SRVTarget{}, false},
+		{"10 x 5060 sip.example.org", SRVTarget{}, false},
+		{"10 20 65536 sip.example.org", SRVTarget{}, false},
+		{"", SRVTarget{}, false},
+	} {
+		srv, err := ParseSRVTarget(tc.input)
+		if tc.valid && err != nil {
+			t.Errorf("expected %q to be valid, got %v", tc.input, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected %q to be invalid", tc.input)
+		}
+		if tc.valid && srv != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, srv)
+		}
+	}
+}
+
+func TestParseMXTarget(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected MXTarget
+		valid    bool
+	}{
+		{"10 mail.example.org", NewMXTarget(10, "mail.example.org"), true},
+		{"0   mail.example.org", NewMXTarget(0, "mail.example.org"), true},
+		{"mail.example.org", MXTarget{}, false},
+		{"10 mail.example.org extra", MXTarget{}, false},
+		{"x mail.example.org", MXTarget{}, false},
+		{"70000 mail.example.org", MXTarget{}, false},
+	} {
+		mx, err := ParseMXTarget(tc.input)
+		if tc.valid && err != nil {
+			t.Errorf("expected %q to be valid, got %v", tc.input, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected %q to be invalid", tc.input)
+		}
+		if tc.valid && mx != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, mx)
+		}
+	}
+}
+
+func TestStructuredTargetsString(t *testing.T) {
+	if s := NewSRVTarget(10, 20, 5060, "sip.example.org").String(); s != "10 20 5060 sip.example.org" {
+		t.Errorf("unexpected SRV target %q", s)
+	}
+	if s := NewMXTarget(10, "mail.example.org").String(); s != "10 mail.example.org" {
+		t.Errorf("unexpected MX target %q", s)
+	}
+}
+
+func TestTargetsSRVAndMX(t *testing.T) {
+	srv, err := NewTargets("10 20 5060 sip1.example.org", "20 20 5060 sip2.example.org").SRV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSRV := []SRVTarget{NewSRVTarget(10, 20, 5060, "sip1.example.org"), NewSRVTarget(20, 20, 5060, "sip2.example.org")}
+	if !reflect.DeepEqual(srv, expectedSRV) {
+		t.Errorf("expected %v, got %v", expectedSRV, srv)
+	}
+	if _, err := NewTargets("10 20 5060 sip1.example.org", "1.2.3.4").SRV(); err == nil {
+		t.Error("expected an error for invalid SRV targets")
+	}
+
+	mx, err := NewTargets("10 mail1.example.org", "20 mail2.example.org").MX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMX := []MXTarget{NewMXTarget(10, "mail1.example.org"), NewMXTarget(20, "mail2.example.org")}
+	if !reflect.DeepEqual(mx, expectedMX) {
+		t.Errorf("expected %v, got %v", expectedMX, mx)
+	}
+	if _, err := NewTargets("mail.example.org").MX(); err == nil {
+		t.Error("expected an error for invalid MX targets")
+	}
+}
+
+func TestMergeStructuredTargets(t *testing.T) {
+	e := &Endpoint{
+		DNSName:    "_sip._tcp.example.org",
+		RecordType: RecordTypeSRV,
+		Targets:    Targets{"10 20 5060 sip1.example.org"},
+		SRVTargets: []SRVTarget{NewSRVTarget(10, 20, 5060, "sip2.example.org.")},
+	}
+	if err := e.MergeStructuredTargets(); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Targets.Same(Targets{"10 20 5060 sip1.example.org", "10 20 5060 sip2.example.org"}) {
+		t.Errorf("unexpected targets %v", e.Targets)
+	}
+	if e.SRVTargets != nil {
+		t.Error("structured targets should be cleared")
+	}
+
+	e = &Endpoint{
+		DNSName:    "example.org",
+		RecordType: "MX",
+		MXTargets:  []MXTarget{NewMXTarget(10, "")},
+	}
+	if err := e.MergeStructuredTargets(); err == nil {
+		t.Error("expected an error for invalid MX target")
+	}
+	if len(e.MXTargets) != 1 || len(e.Targets) != 0 {
+		t.Error("endpoint with invalid structured targets should be left unchanged")
+	}
+	if err := e.Validate(); err == nil {
+		t.Error("expected endpoint with invalid structured targets to be invalid")
+	}
+
+	for _, e := range []*Endpoint{
+		{DNSName: "example.org", RecordType: RecordTypeA, SRVTargets: []SRVTarget{NewSRVTarget(10, 20, 5060, "sip.example.org")}},
+		{DNSName: "example.org", RecordType: RecordTypeMX, SRVTargets: []SRVTarget{NewSRVTarget(10, 20, 5060, "sip.example.org")}},
+		{DNSName: "example.org", RecordType: RecordTypeCNAME, MXTargets: []MXTarget{NewMXTarget(10, "mail.example.org")}},
+		{DNSName: "example.org", RecordType: RecordTypeSRV, MXTargets: []MXTarget{NewMXTarget(10, "mail.example.org")}},
+	} {
+		if err := e.MergeStructuredTargets(); err == nil {
+			t.Errorf("expected an error for structured targets of a %s record", e.RecordType)
+		}
+	}
+}
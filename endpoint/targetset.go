@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+// TargetSet is the canonical form of the targets of a record: normalized, sorted and without duplicates.
+// Unlike Targets it doesn't depend on the order of the targets and creating it never modifies them.
+type TargetSet []string
+
+// NewTargetSet returns the canonical form of the targets of a record of the given type
+func NewTargetSet(recordType string, targets Targets) TargetSet {
+	normalized := make([]string, 0, len(targets))
+	for _, target := range targets {
+		normalized = append(normalized, NormalizeTarget(recordType, target))
+	}
+	sort.Strings(normalized)
+
+	set := make(TargetSet, 0, len(normalized))
+	for i, target := range normalized {
+		if i == 0 || target != normalized[i-1] {
+			set = append(set, target)
+		}
+	}
+	return set
+}
+
+// Equal returns true if both sets contain the same targets
+func (s TargetSet) Equal(o TargetSet) bool {
+	if len(s) != len(o) {
+		return false
+	}
+	for i := range s {
+		if s[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Less defines a total ordering of target sets: smaller sets come first and sets of the
+// same size are compared target by target.
+func (s TargetSet) Less(o TargetSet) bool {
+	if len(s) != len(o) {
+		return len(s) < len(o)
+	}
+	for i := range s {
+		if s[i] != o[i] {
+			return s[i] < o[i]
+		}
+	}
+	return false
+}
+
+func (s TargetSet) String() string {
+	return strings.Join(s, ";")
+}
+
+// NormalizeTarget returns the canonical textual form of a target of a record of the given type.
+// IP addresses are formatted like net.IP does, e.g. IPv6 addresses in their shortest form, and
+// hostnames are lower cased and have their trailing dot removed. Targets of other record types
+// and targets which can't be parsed are returned unchanged.
+func NormalizeTarget(recordType, target string) string {
+	switch recordType {
+	case RecordTypeA, RecordTypeAAAA:
+		if ip := net.ParseIP(target); ip != nil {
+			return ip.String()
+		}
+	case RecordTypeCNAME, RecordTypeNS, RecordTypePTR:
+		return normalizeHostname(target)
+	case RecordTypeMX:
+		if mx, err := ParseMXTarget(target); err == nil {
+			mx.Exchange = normalizeHostname(mx.Exchange)
+			return mx.String()
+		}
+	case RecordTypeSRV:
+		if srv, err := ParseSRVTarget(target); err == nil {
+			srv.Target = normalizeHostname(srv.Target)
+			return srv.String()
+		}
+	}
+	return target
+}
+
+func normalizeHostname(hostname string) string {
+	if hostname == "." {
+		return hostname
+	}
+	return strings.ToLower(NormalizeDNSName(strings.TrimSuffix(hostname, ".")))
+}
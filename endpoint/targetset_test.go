@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTarget(t *testing.T) {
+	for _, tc := range []struct {
+		recordType string
+		target     string
+		expected   string
+	}{
+		{RecordTypeA, "1.2.3.4", "1.2.3.4"},
+		{RecordTypeAAAA, "2001:DB8:0:0:0:0:0:1", "2001:db8::1"},
+		{RecordTypeAAAA, "2001:db8::1", "2001:db8::1"},
+		{RecordTypeAAAA, "invalid", "invalid"},
+		{RecordTypeCNAME, "LB.Example.org.", "lb.example.org"},
+		{RecordTypeNS, "ns1.example.org.", "ns1.example.org"},
+		{RecordTypePTR, "Host.example.org", "host.example.org"},
+		{RecordTypeMX, "10  Mail.example.org.", "10 mail.example.org"},
+		{RecordTypeSRV, "10 20 5060 SIP.example.org.", "10 20 5060 sip.example.org"},
+		{RecordTypeSRV, "0 0 0 .", "0 0 0 ."},
+		{RecordTypeTXT, "Heritage=External-DNS.", "Heritage=External-DNS."},
+	} {
+		if actual := NormalizeTarget(tc.recordType, tc.target); actual != tc.expected {
+			t.Errorf("expected %s target %q to be normalized to %q, got %q", tc.recordType, tc.target, tc.expected, actual)
+		}
+	}
+}
+
+func TestNewTargetSet(t *testing.T) {
+	targets := Targets{"lb2.example.org.", "LB1.example.org", "lb1.example.org"}
+	set := NewTargetSet(RecordTypeCNAME, targets)
+
+	if expected := (TargetSet{"lb1.example.org", "lb2.example.org"}); !reflect.DeepEqual(set, expected) {
+		t.Errorf("expected %v, got %v", expected, set)
+	}
+	if expected := (Targets{"lb2.example.org.", "LB1.example.org", "lb1.example.org"}); !reflect.DeepEqual(targets, expected) {
+		t.Errorf("targets have been modified: %v", targets)
+	}
+}
+
+func TestTargetSetEqual(t *testing.T) {
+	for _, tc := range []struct {
+		recordType string
+		a, b       Targets
+		expected   bool
+	}{
+		{RecordTypeA, Targets{"1.2.3.4", "4.3.2.1"}, Targets{"4.3.2.1", "1.2.3.4"}, true},
+		{RecordTypeA, Targets{"1.2.3.4"}, Targets{"4.3.2.1"}, false},
+		{RecordTypeA, Targets{"1.2.3.4"}, Targets{"1.2.3.4", "4.3.2.1"}, false},
+		{RecordTypeAAAA, Targets{"2001:db8::1"}, Targets{"2001:0db8:0000::0001"}, true},
+		{RecordTypeCNAME, Targets{"lb.example.org"}, Targets{"LB.example.org."}, true},
+		{RecordTypeTXT, Targets{"owner=foo"}, Targets{"owner=Foo"}, false},
+	} {
+		if actual := NewTargetSet(tc.recordType, tc.a).Equal(NewTargetSet(tc.recordType, tc.b)); actual != tc.expected {
+			t.Errorf("expected %v and %v to be equal: %v", tc.a, tc.b, tc.expected)
+		}
+	}
+}
+
+func TestTargetSetLess(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     TargetSet
+		expected bool
+	}{
+		{TargetSet{"1.2.3.4"}, TargetSet{"1.2.3.4"}, false},
+		{TargetSet{"1.2.3.4"}, TargetSet{"4.3.2.1"}, true},
+		{TargetSet{"4.3.2.1"}, TargetSet{"1.2.3.4"}, false},
+		{TargetSet{"4.3.2.1"}, TargetSet{"1.2.3.4", "4.3.2.1"}, true},
+		{TargetSet{"1.2.3.4", "4.3.2.1"}, TargetSet{"4.3.2.1"}, false},
+		{TargetSet{}, TargetSet{"1.2.3.4"}, true},
+	} {
+		if actual := tc.a.Less(tc.b); actual != tc.expected {
+			t.Errorf("expected %v.Less(%v) to be %v", tc.a, tc.b, tc.expected)
+		}
+	}
+}
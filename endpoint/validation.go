@@ -0,0 +1,196 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxDNSNameLength is the maximum length of a DNS name in its textual form without the trailing dot, see RFC 1035
+	maxDNSNameLength = 253
+	// maxLabelLength is the maximum length of a single label of a DNS name, see RFC 1035
+	maxLabelLength = 63
+	// maxTXTLength is the maximum length of a single character-string of a TXT record, see RFC 1035
+	maxTXTLength = 255
+	// maxTTL is the maximum TTL of a record, see RFC 2181
+	maxTTL = 1<<31 - 1
+)
+
+// ValidateDNSName checks that name is a valid DNS name according to RFC 1035 and RFC 1123.
+// Labels may start with an underscore, as used by SRV and other service records, and the
+// leftmost label may be a wildcard.
+func ValidateDNSName(name string) error {
+	return validateName(name, true)
+}
+
+// ValidateHostname checks that name is a valid hostname to be used as a target, which is
+// a DNS name without wildcards.
+func ValidateHostname(name string) error {
+	return validateName(name, false)
+}
+
+func validateName(name string, allowWildcard bool) error {
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" {
+		return fmt.Errorf("invalid DNS name %q: name is empty", name)
+	}
+	if len(trimmed) > maxDNSNameLength {
+		return fmt.Errorf("invalid DNS name %q: name is longer than %d characters", name, maxDNSNameLength)
+	}
+	for i, label := range strings.Split(trimmed, ".") {
+		if label == "*" {
+			if !allowWildcard || i != 0 {
+				return fmt.Errorf("invalid DNS name %q: wildcards are only allowed as the leftmost label", name)
+			}
+			continue
+		}
+		if err := validateLabel(label); err != nil {
+			return fmt.Errorf("invalid DNS name %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func validateLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("empty label")
+	}
+	if len(label) > maxLabelLength {
+		return fmt.Errorf("label %q is longer than %d characters", label, maxLabelLength)
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-':
+			if i == 0 || i == len(label)-1 {
+				return fmt.Errorf("label %q starts or ends with a hyphen", label)
+			}
+		case c == '_':
+			if i != 0 {
+				return fmt.Errorf("label %q contains an underscore which is not its first character", label)
+			}
+		case c == '*':
+			return fmt.Errorf("label %q contains a wildcard which is not a label on its own", label)
+		case c > 127:
+			return fmt.Errorf("label %q contains non-ASCII characters", label)
+		default:
+			return fmt.Errorf("label %q contains invalid character %q", label, c)
+		}
+	}
+	return nil
+}
+
+// ValidateTarget checks the syntax of target for the given record type. Targets of
+// record types without known syntax are accepted as is.
+func ValidateTarget(recordType, target string) error {
+	switch recordType {
+	case RecordTypeA:
+		if ip := net.ParseIP(target); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid A target %q: not an IPv4 address", target)
+		}
+	case RecordTypeAAAA:
+		if ip := net.ParseIP(target); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("invalid AAAA target %q: not an IPv6 address", target)
+		}
+	case RecordTypeCNAME, RecordTypeNS, RecordTypePTR:
+		if err := ValidateHostname(target); err != nil {
+			return fmt.Errorf("invalid %s target: %v", recordType, err)
+		}
+	case RecordTypeSRV:
+		srv, err := ParseSRVTarget(target)
+		if err != nil {
+			return err
+		}
+		return validateRootOrHostname(RecordTypeSRV, srv.Target)
+	case RecordTypeMX:
+		mx, err := ParseMXTarget(target)
+		if err != nil {
+			return err
+		}
+		return validateRootOrHostname(RecordTypeMX, mx.Exchange)
+	case RecordTypeCAA:
+		return validateCAATarget(target)
+	case RecordTypeTXT:
+		if len(target) > maxTXTLength {
+			return fmt.Errorf("invalid TXT target: longer than %d characters", maxTXTLength)
+		}
+	}
+	return nil
+}
+
+// validateRootOrHostname accepts "." in addition to hostnames, which is used by SRV and MX records
+// to announce that a service is not available, see RFC 2782 and RFC 7505.
+func validateRootOrHostname(recordType, name string) error {
+	if name == "." {
+		return nil
+	}
+	if err := ValidateHostname(name); err != nil {
+		return fmt.Errorf("invalid %s target: %v", recordType, err)
+	}
+	return nil
+}
+
+// validateCAATarget checks that target has the form "<flag> <tag> <value>", see RFC 6844.
+func validateCAATarget(target string) error {
+	fields := strings.SplitN(strings.TrimSpace(target), " ", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid CAA target %q: expected \"<flag> <tag> <value>\"", target)
+	}
+	if _, err := strconv.ParseUint(fields[0], 10, 8); err != nil {
+		return fmt.Errorf("invalid CAA target %q: %q is not a valid flag", target, fields[0])
+	}
+	if fields[1] == "" || strings.IndexFunc(fields[1], func(c rune) bool {
+		return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9')
+	}) != -1 {
+		return fmt.Errorf("invalid CAA target %q: %q is not a valid tag", target, fields[1])
+	}
+	return nil
+}
+
+// ValidateTTL checks that ttl is within the range allowed by RFC 2181. A TTL of zero
+// means that the TTL is not configured and is accepted.
+func ValidateTTL(ttl TTL) error {
+	if ttl < 0 || ttl > maxTTL {
+		return fmt.Errorf("invalid TTL %d: must be between 0 and %d", ttl, maxTTL)
+	}
+	return nil
+}
+
+// Validate checks the DNS name, targets and TTL of the endpoint, including structured targets
+// which couldn't be merged
+func (e *Endpoint) Validate() error {
+	if err := ValidateDNSName(e.DNSName); err != nil {
+		return err
+	}
+	if err := e.validateStructuredTargets(); err != nil {
+		return err
+	}
+	if len(e.Targets) == 0 {
+		return fmt.Errorf("endpoint %s has no targets", e.DNSName)
+	}
+	for _, target := range e.Targets {
+		if err := ValidateTarget(e.RecordType, target); err != nil {
+			return err
+		}
+	}
+	return ValidateTTL(e.RecordTTL)
+}
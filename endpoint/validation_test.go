@@ -0,0 +1,157 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDNSName(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		valid bool
+	}{
+		{"example.org", true},
+		{"example.org.", true},
+		{"Foo-Bar.example.org", true},
+		{"_sip._tcp.example.org", true},
+		{"*.example.org", true},
+		{"1.2.3.4.in-addr.arpa", true},
+		{strings.Repeat("a", 63) + ".example.org", true},
+		{strings.Repeat(strings.Repeat("a", 49)+".", 5) + "org", true},
+		{"", false},
+		{".", false},
+		{"foo..example.org", false},
+		{".example.org", false},
+		{"foo_bar.example.org", false},
+		{"foo_.example.org", false},
+		{"-foo.example.org", false},
+		{"foo-.example.org", false},
+		{strings.Repeat("a", 64) + ".example.org", false},
+		{strings.Repeat(strings.Repeat("a", 50)+".", 5) + "org", false},
+		{"foo.*.example.org", false},
+		{"*foo.example.org", false},
+		{"foo bar.example.org", false},
+		{"bücher.example.org", false},
+	} {
+		err := ValidateDNSName(tc.name)
+		if tc.valid && err != nil {
+			t.Errorf("expected %q to be valid, got %v", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected %q to be invalid", tc.name)
+		}
+	}
+}
+
+func TestValidateHostname(t *testing.T) {
+	if err := ValidateHostname("lb.example.org"); err != nil {
+		t.Errorf("expected hostname to be valid, got %v", err)
+	}
+	if err := ValidateHostname("*.example.org"); err == nil {
+		t.Error("expected wildcard hostname to be invalid")
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	for _, tc := range []struct {
+		recordType string
+		target     string
+		valid      bool
+	}{
+		{RecordTypeA, "1.2.3.4", true},
+		{RecordTypeA, "2001:db8::1", false},
+		{RecordTypeA, "lb.example.org", false},
+		{RecordTypeAAAA, "2001:db8::1", true},
+		{RecordTypeAAAA, "1.2.3.4", false},
+		{RecordTypeCNAME, "lb.example.org", true},
+		{RecordTypeCNAME, "lb.example.org.", true},
+		{RecordTypeCNAME, "1.2.3.4", true},
+		{RecordTypeCNAME, "lb..example.org", false},
+		{RecordTypeNS, "ns1.example.org", true},
+		{RecordTypePTR, "in_valid.example.org", false},
+		{RecordTypeSRV, "10 20 5060 sip.example.org", true},
+		{RecordTypeSRV, "0 0 0 .", true},
+		{RecordTypeSRV, "10 20 sip.example.org", false},
+		{RecordTypeSRV, "10 20 5060 -sip.example.org", false},
+		{RecordTypeMX, "10 mail.example.org", true},
+		{RecordTypeMX, "0 .", true},
+		{RecordTypeMX, "mail.example.org", false},
+		{RecordTypeCAA, "0 issue \"letsencrypt.org\"", true},
+		{RecordTypeCAA, "128 iodef \"mailto:security@example.org\"", true},
+		{RecordTypeCAA, "256 issue \"letsencrypt.org\"", false},
+		{RecordTypeCAA, "0 is-sue \"letsencrypt.org\"", false},
+		{RecordTypeCAA, "0 issue", false},
+		{RecordTypeTXT, "heritage=external-dns,external-dns/owner=default", true},
+		{RecordTypeTXT, strings.Repeat("a", 256), false},
+		{"SPF", "anything goes", true},
+	} {
+		err := ValidateTarget(tc.recordType, tc.target)
+		if tc.valid && err != nil {
+			t.Errorf("expected %s target %q to be valid, got %v", tc.recordType, tc.target, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected %s target %q to be invalid", tc.recordType, tc.target)
+		}
+	}
+}
+
+func TestValidateTTL(t *testing.T) {
+	for _, tc := range []struct {
+		ttl   TTL
+		valid bool
+	}{
+		{0, true},
+		{300, true},
+		{maxTTL, true},
+		{-1, false},
+		{maxTTL + 1, false},
+	} {
+		err := ValidateTTL(tc.ttl)
+		if tc.valid && err != nil {
+			t.Errorf("expected TTL %d to be valid, got %v", tc.ttl, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected TTL %d to be invalid", tc.ttl)
+		}
+	}
+}
+
+func TestEndpointValidate(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint *Endpoint
+		valid    bool
+	}{
+		{NewEndpoint("example.org", RecordTypeA, "1.2.3.4"), true},
+		{NewEndpointWithTTL("example.org", RecordTypeCNAME, 60, "lb.example.org"), true},
+		{NewEndpoint("in_valid.example.org", RecordTypeA, "1.2.3.4"), false},
+		{NewEndpoint("example.org", RecordTypeA), false},
+		{NewEndpoint("example.org", RecordTypeA, "1.2.3.4", "lb.example.org"), false},
+		{NewEndpointWithTTL("example.org", RecordTypeA, -1, "1.2.3.4"), false},
+		{&Endpoint{DNSName: "example.org", RecordType: RecordTypeA, Targets: Targets{"1.2.3.4"}, SRVTargets: []SRVTarget{NewSRVTarget(10, 20, 5060, "sip.example.org")}}, false},
+		{&Endpoint{DNSName: "example.org", RecordType: RecordTypeMX, Targets: Targets{"10 mail.example.org"}, MXTargets: []MXTarget{NewMXTarget(10, "")}}, false},
+	} {
+		err := tc.endpoint.Validate()
+		if tc.valid && err != nil {
+			t.Errorf("expected %v to be valid, got %v", tc.endpoint, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected %v to be invalid", tc.endpoint)
+		}
+	}
+}
@@ -151,6 +151,16 @@ func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 			(*out)[key] = val
 		}
 	}
+	if in.SRVTargets != nil {
+		in, out := &in.SRVTargets, &out.SRVTargets
+		*out = make([]SRVTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.MXTargets != nil {
+		in, out := &in.MXTargets, &out.MXTargets
+		*out = make([]MXTarget, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -186,6 +196,22 @@ func (in Labels) DeepCopy() Labels {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MXTarget) DeepCopyInto(out *MXTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MXTarget.
+func (in *MXTarget) DeepCopy() *MXTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(MXTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ProviderSpecific) DeepCopyInto(out *ProviderSpecific) {
 	{
@@ -208,6 +234,42 @@ func (in ProviderSpecific) DeepCopy() ProviderSpecific {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRVTarget) DeepCopyInto(out *SRVTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRVTarget.
+func (in *SRVTarget) DeepCopy() *SRVTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SRVTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in TargetSet) DeepCopyInto(out *TargetSet) {
+	{
+		in := &in
+		*out = make(TargetSet, len(*in))
+		copy(*out, *in)
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSet.
+func (in TargetSet) DeepCopy() TargetSet {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetSet)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Targets) DeepCopyInto(out *Targets) {
 	{
@@ -17,6 +17,8 @@ limitations under the License.
 package testutils
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -28,7 +30,7 @@ type MockSource struct {
 }
 
 // Endpoints returns the desired mock endpoints.
-func (m *MockSource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (m *MockSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	args := m.Called()
 
 	endpoints := args.Get(0)
@@ -38,3 +40,7 @@ func (m *MockSource) Endpoints() ([]*endpoint.Endpoint, error) {
 
 	return endpoints.([]*endpoint.Endpoint), args.Error(1)
 }
+
+// AddEventHandler does nothing, the mock endpoints never change.
+func (m *MockSource) AddEventHandler(handler func()) {
+}
@@ -17,14 +17,18 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/kubernetes-incubator/external-dns/controller"
@@ -60,10 +64,14 @@ func main() {
 	}
 	log.SetLevel(ll)
 
-	stopChan := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	status := &controller.Status{SyncTimeout: cfg.SyncTimeout}
 
-	go serveMetrics(cfg.MetricsAddress)
-	go handleSigterm(stopChan)
+	// The plan command exits after a single run, so it doesn't compete with a running instance for the metrics address.
+	if cfg.Command != "plan" {
+		go serveMetrics(cfg.MetricsAddress, status)
+	}
+	go handleSigterm(cancel)
 
 	// Create a source.Config from the flags passed by the user.
 	sourceCfg := &source.Config{
@@ -83,12 +91,14 @@ func main() {
 		IstioIngressGateway:      cfg.IstioIngressGateway,
 	}
 
-	// Lookup all the selected sources by names and pass them the desired configuration.
-	sources, err := source.ByNames(&source.SingletonClientGenerator{
+	clientGenerator := &source.SingletonClientGenerator{
 		KubeConfig:     cfg.KubeConfig,
 		KubeMaster:     cfg.Master,
 		RequestTimeout: cfg.RequestTimeout,
-	}, cfg.Sources, sourceCfg)
+	}
+
+	// Lookup all the selected sources by names and pass them the desired configuration.
+	sources, err := source.ByNames(clientGenerator, cfg.Sources, sourceCfg)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -208,9 +218,19 @@ func main() {
 	case "noop":
 		r, err = registry.NewNoopRegistry(p)
 	case "txt":
-		r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTOwnerID, cfg.TXTCacheInterval)
+		var opts []registry.TXTOption
+		opts, err = txtOptions(cfg)
+		if err == nil {
+			r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTOwnerID, cfg.TXTCacheInterval, opts...)
+		}
 	case "aws-sd":
 		r, err = registry.NewAWSSDRegistry(p.(*provider.AWSSDProvider), cfg.TXTOwnerID)
+	case "configmap":
+		var kubeClient kubernetes.Interface
+		kubeClient, err = clientGenerator.KubeClient()
+		if err == nil {
+			r, err = registry.NewConfigMapRegistry(p, kubeClient, cfg.RegistryNamespace, cfg.RegistryName, cfg.RegistryShards, cfg.TXTOwnerID, cfg.DryRun)
+		}
 	default:
 		log.Fatalf("unknown registry: %s", cfg.Registry)
 	}
@@ -224,37 +244,219 @@ func main() {
 		log.Fatalf("unknown policy: %s", cfg.Policy)
 	}
 
-	ctrl := controller.Controller{
-		Source:   endpointsSource,
-		Registry: r,
-		Policy:   policy,
-		Interval: cfg.Interval,
+	if cfg.DampingCycles > 0 || cfg.DampingDuration > 0 {
+		policy = &plan.DampingPolicy{
+			Policy:         policy,
+			StableCycles:   cfg.DampingCycles,
+			StableDuration: cfg.DampingDuration,
+		}
+	}
+
+	if cfg.DeletionGracePeriod > 0 || cfg.DeletionGraceCycles > 0 {
+		policy = &plan.DelayedDeletionPolicy{
+			Policy:      policy,
+			GracePeriod: cfg.DeletionGracePeriod,
+			GraceCycles: cfg.DeletionGraceCycles,
+			Interval:    cfg.Interval,
+		}
+	}
+
+	// The noop registry doesn't track ownership, all records are considered owned.
+	owner := cfg.TXTOwnerID
+	if cfg.Registry == "noop" {
+		owner = ""
+	}
+
+	if cfg.DeletionThreshold > 0 || cfg.DeletionPercentage > 0 {
+		thresholdPolicy := &plan.DeletionThresholdPolicy{
+			Policy:                policy,
+			MaxDeletions:          cfg.DeletionThreshold,
+			MaxDeletionPercentage: cfg.DeletionPercentage,
+			OwnerID:               owner,
+			Acknowledgers:         []plan.Acknowledger{plan.StaticAcknowledger(cfg.DeletionThresholdAck)},
+		}
+		if cfg.DeletionAckConfigMap != "" {
+			parts := strings.SplitN(cfg.DeletionAckConfigMap, "/", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				log.Fatalf("invalid deletion threshold acknowledgement ConfigMap, expected namespace/name: %s", cfg.DeletionAckConfigMap)
+			}
+			kubeClient, err := clientGenerator.KubeClient()
+			if err != nil {
+				log.Fatal(err)
+			}
+			thresholdPolicy.Acknowledgers = append(thresholdPolicy.Acknowledgers, &controller.ConfigMapAcknowledger{
+				Client:    kubeClient,
+				Namespace: parts[0],
+				Name:      parts[1],
+			})
+		}
+		http.Handle("/deletion-threshold", thresholdPolicy)
+		policy = thresholdPolicy
+	}
+
+	resolver, exists := plan.ConflictResolvers[cfg.ConflictResolver]
+	if !exists {
+		log.Fatalf("unknown conflict resolver: %s", cfg.ConflictResolver)
+	}
+
+	// attribute records and changes to the zones of the provider, or the domain filters if it doesn't list them
+	zones, err := provider.ZoneNames(ctx, p, cfg.DomainFilter)
+	if err != nil {
+		log.Warnf("Failed to list the zones of the provider, attributing records to the domain filters: %v", err)
+		zones = cfg.DomainFilter
+	}
+
+	ctrl := &controller.Controller{
+		Source:               endpointsSource,
+		Registry:             r,
+		Policy:               policy,
+		Resolver:             resolver,
+		Interval:             cfg.Interval,
+		MinEventSyncInterval: cfg.MinEventSyncInterval,
+		SyncTimeout:          cfg.SyncTimeout,
+		Status:               status,
+		Zones:                zones,
+		OwnerID:              owner,
+		Provider:             cfg.Provider,
+	}
+
+	if cfg.Command == "plan" {
+		os.Exit(printPlan(ctx, ctrl, cfg))
 	}
 
 	if cfg.Once {
-		err := ctrl.RunOnce()
+		err := ctrl.RunOnce(ctx)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		os.Exit(0)
 	}
-	ctrl.Run(stopChan)
+
+	if cfg.UpdateEvents {
+		// Add RunOnce as the handler function that will be called when ingress/service sources have changed.
+		// Note that k8s Informers will perform an initial list operation, which results in the handler
+		// function initially being called for every Service/Ingress that exists limited by minInterval.
+		endpointsSource.AddEventHandler(func() { ctrl.ScheduleRunOnce(time.Now()) })
+	}
+
+	if cfg.TriggerToken != "" {
+		http.Handle("/trigger", &controller.TriggerHandler{Controller: ctrl, Token: cfg.TriggerToken})
+	}
+	go handleSighup(ctrl)
+
+	if cfg.LeaderElect {
+		runWithLeaderElection(ctx, ctrl, clientGenerator, cfg)
+		return
+	}
+	ctrl.Run(ctx)
+}
+
+// txtOptions returns the options of the TXT registry
+func txtOptions(cfg *externaldns.Config) ([]registry.TXTOption, error) {
+	opts := []registry.TXTOption{}
+	if cfg.TXTSuffix != "" {
+		opts = append(opts, registry.TXTWithSuffix(cfg.TXTSuffix))
+	}
+	if cfg.TXTEncodeRecordType {
+		opts = append(opts, registry.TXTWithRecordType())
+	}
+	if cfg.TXTMigrate {
+		opts = append(opts, registry.TXTWithMigration())
+	}
+	if len(cfg.TXTSigningKeyFiles) > 0 {
+		keys, err := registry.LoadTXTSigningKeys(cfg.TXTSigningKeyFiles)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, registry.TXTWithSigningKeys(keys))
+	}
+	if cfg.TXTAcceptUnsigned {
+		opts = append(opts, registry.TXTWithUnsignedAccepted())
+	}
+	if cfg.TXTDeleteOrphans {
+		opts = append(opts, registry.TXTWithOrphanDeletion(cfg.DryRun))
+	}
+	return opts, nil
+}
+
+// runWithLeaderElection runs the controller while this replica holds the leader election lock.
+// The sources are already running, so standby replicas keep their caches warm.
+func runWithLeaderElection(ctx context.Context, ctrl *controller.Controller, clientGenerator source.ClientGenerator, cfg *externaldns.Config) {
+	kubeClient, err := clientGenerator.KubeClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("failed to determine the leader election identity: %v", err)
+	}
+
+	le := &controller.LeaderElection{
+		Client:        kubeClient,
+		Namespace:     cfg.LeaderElectNamespace,
+		Name:          cfg.LeaderElectName,
+		Identity:      identity,
+		LeaseDuration: cfg.LeaderLeaseDuration,
+		RenewDeadline: cfg.LeaderRenewDeadline,
+		RetryPeriod:   cfg.LeaderRetryPeriod,
+	}
+	ctrl.Status.SetStandby()
+	if err := le.Run(ctx, ctrl.Run); err != nil {
+		log.Fatal(err)
+	}
 }
 
-func handleSigterm(stopChan chan struct{}) {
+// printPlan prints the changes the controller would apply and returns the exit code of the plan
+// command: 0 if the DNS records are up-to-date and 2 if changes are pending.
+func printPlan(ctx context.Context, ctrl *controller.Controller, cfg *externaldns.Config) int {
+	if cfg.SyncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.SyncTimeout)
+		defer cancel()
+	}
+
+	diff, err := ctrl.Preview(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.PlanOutput == "json" {
+		err = diff.WriteJSON(os.Stdout)
+	} else {
+		err = diff.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if diff.HasChanges() {
+		return 2
+	}
+	return 0
+}
+
+func handleSigterm(cancel context.CancelFunc) {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGTERM)
 	<-signals
 	log.Info("Received SIGTERM. Terminating...")
-	close(stopChan)
+	cancel()
+}
+
+// handleSighup triggers a synchronization whenever SIGHUP is received
+func handleSighup(ctrl *controller.Controller) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		ctrl.Trigger(controller.TriggerSignal)
+	}
 }
 
-func serveMetrics(address string) {
-	http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+func serveMetrics(address string, status *controller.Status) {
+	http.HandleFunc("/healthz", status.ServeLiveness)
+	http.HandleFunc("/readyz", status.ServeReadiness)
+	http.Handle("/status", status)
 
 	http.Handle("/metrics", promhttp.Handler())
 
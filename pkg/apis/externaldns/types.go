@@ -83,14 +83,44 @@ type Config struct {
 	TLSClientCert            string
 	TLSClientCertKey         string
 	Policy                   string
+	ConflictResolver         string
+	DeletionThreshold        int
+	DeletionPercentage       float64
+	DeletionThresholdAck     string
+	DeletionAckConfigMap     string
+	DeletionGracePeriod      time.Duration
+	DeletionGraceCycles      int
+	DampingCycles            int
+	DampingDuration          time.Duration
 	Registry                 string
+	RegistryNamespace        string
+	RegistryName             string
+	RegistryShards           int
 	TXTOwnerID               string
 	TXTPrefix                string
+	TXTSuffix                string
+	TXTEncodeRecordType      bool
+	TXTMigrate               bool
+	TXTSigningKeyFiles       []string
+	TXTAcceptUnsigned        bool
+	TXTDeleteOrphans         bool
 	Interval                 time.Duration
+	MinEventSyncInterval     time.Duration
+	UpdateEvents             bool
+	SyncTimeout              time.Duration
+	LeaderElect              bool
+	LeaderElectNamespace     string
+	LeaderElectName          string
+	LeaderLeaseDuration      time.Duration
+	LeaderRenewDeadline      time.Duration
+	LeaderRetryPeriod        time.Duration
 	Once                     bool
 	DryRun                   bool
+	Command                  string
+	PlanOutput               string
 	LogFormat                string
 	MetricsAddress           string
+	TriggerToken             string
 	LogLevel                 string
 	TXTCacheInterval         time.Duration
 	ExoscaleEndpoint         string
@@ -151,15 +181,45 @@ var defaultConfig = &Config{
 	TLSClientCert:            "",
 	TLSClientCertKey:         "",
 	Policy:                   "sync",
+	ConflictResolver:         "per-resource",
+	DeletionThreshold:        0,
+	DeletionPercentage:       0,
+	DeletionThresholdAck:     "",
+	DeletionAckConfigMap:     "",
+	DeletionGracePeriod:      0,
+	DeletionGraceCycles:      0,
+	DampingCycles:            0,
+	DampingDuration:          0,
 	Registry:                 "txt",
+	RegistryNamespace:        "default",
+	RegistryName:             "external-dns-registry",
+	RegistryShards:           8,
 	TXTOwnerID:               "default",
 	TXTPrefix:                "",
+	TXTSuffix:                "",
+	TXTEncodeRecordType:      false,
+	TXTMigrate:               false,
+	TXTSigningKeyFiles:       []string{},
+	TXTAcceptUnsigned:        false,
+	TXTDeleteOrphans:         false,
 	TXTCacheInterval:         0,
 	Interval:                 time.Minute,
+	MinEventSyncInterval:     5 * time.Second,
+	UpdateEvents:             false,
+	SyncTimeout:              0,
+	LeaderElect:              false,
+	LeaderElectNamespace:     "default",
+	LeaderElectName:          "external-dns-leader",
+	LeaderLeaseDuration:      15 * time.Second,
+	LeaderRenewDeadline:      10 * time.Second,
+	LeaderRetryPeriod:        2 * time.Second,
 	Once:                     false,
 	DryRun:                   false,
+	Command:                  "run",
+	PlanOutput:               "text",
 	LogFormat:                "text",
 	MetricsAddress:           ":7979",
+	TriggerToken:             "",
 	LogLevel:                 logrus.InfoLevel.String(),
 	ExoscaleEndpoint:         "https://api.exoscale.ch/dns",
 	ExoscaleAPIKey:           "",
@@ -194,6 +254,9 @@ func (cfg *Config) String() string {
 	if temp.PDNSAPIKey != "" {
 		temp.PDNSAPIKey = ""
 	}
+	if temp.TriggerToken != "" {
+		temp.TriggerToken = passwordMask
+	}
 
 	return fmt.Sprintf("%+v", temp)
 }
@@ -289,27 +352,63 @@ func (cfg *Config) ParseFlags(args []string) error {
 
 	// Flags related to policies
 	app.Flag("policy", "Modify how DNS records are synchronized between sources and providers (default: sync, options: sync, upsert-only)").Default(defaultConfig.Policy).EnumVar(&cfg.Policy, "sync", "upsert-only")
+	app.Flag("conflict-resolver", "Modify how a winner is picked when several resources want the same DNS name (default: per-resource, options: per-resource, oldest-resource, priority, multi-cluster)").Default(defaultConfig.ConflictResolver).EnumVar(&cfg.ConflictResolver, "per-resource", "oldest-resource", "priority", "multi-cluster")
+	app.Flag("deletion-threshold", "Refuse to apply changes which delete more than this number of owned records until they are acknowledged (default: 0, disabled)").Default(strconv.Itoa(defaultConfig.DeletionThreshold)).IntVar(&cfg.DeletionThreshold)
+	app.Flag("deletion-threshold-percentage", "Refuse to apply changes which delete more than this percentage of owned records until they are acknowledged (default: 0, disabled)").Default(strconv.FormatFloat(defaultConfig.DeletionPercentage, 'f', -1, 64)).Float64Var(&cfg.DeletionPercentage)
+	app.Flag("deletion-threshold-ack", "Acknowledge the changes blocked by the deletion threshold with the given fingerprint (optional)").Default(defaultConfig.DeletionThresholdAck).StringVar(&cfg.DeletionThresholdAck)
+	app.Flag("deletion-threshold-ack-configmap", "Acknowledge the changes blocked by the deletion threshold with the fingerprint in the external-dns.alpha.kubernetes.io/deletion-threshold-ack annotation of this ConfigMap, given as namespace/name (optional)").Default(defaultConfig.DeletionAckConfigMap).StringVar(&cfg.DeletionAckConfigMap)
+	app.Flag("deletion-grace-period", "Only delete records which have been missing from the desired state for this duration (default: disabled)").Default(defaultConfig.DeletionGracePeriod.String()).DurationVar(&cfg.DeletionGracePeriod)
+	app.Flag("deletion-grace-cycles", "Only delete records which have been missing from the desired state for this number of reconcile cycles (default: 0, disabled)").Default(strconv.Itoa(defaultConfig.DeletionGraceCycles)).IntVar(&cfg.DeletionGraceCycles)
+	app.Flag("damping-cycles", "Only change the targets of records once the desired targets have been stable for this number of consecutive reconcile cycles (default: 0, disabled)").Default(strconv.Itoa(defaultConfig.DampingCycles)).IntVar(&cfg.DampingCycles)
+	app.Flag("damping-duration", "Only change the targets of records once the desired targets have been stable for this duration (default: disabled)").Default(defaultConfig.DampingDuration.String()).DurationVar(&cfg.DampingDuration)
 
 	// Flags related to the registry
-	app.Flag("registry", "The registry implementation to use to keep track of DNS record ownership (default: txt, options: txt, noop, aws-sd)").Default(defaultConfig.Registry).EnumVar(&cfg.Registry, "txt", "noop", "aws-sd")
+	app.Flag("registry", "The registry implementation to use to keep track of DNS record ownership (default: txt, options: txt, noop, aws-sd, configmap)").Default(defaultConfig.Registry).EnumVar(&cfg.Registry, "txt", "noop", "aws-sd", "configmap")
+	app.Flag("registry-namespace", "When using the configmap registry, the namespace of the ConfigMaps holding the ownership of the DNS records (default: default)").Default(defaultConfig.RegistryNamespace).StringVar(&cfg.RegistryNamespace)
+	app.Flag("registry-name", "When using the configmap registry, the prefix of the names of the ConfigMaps holding the ownership of the DNS records (default: external-dns-registry)").Default(defaultConfig.RegistryName).StringVar(&cfg.RegistryName)
+	app.Flag("registry-shards", "When using the configmap registry, the number of ConfigMaps the ownership of the DNS records is spread over; must not be changed once records are owned (default: 8)").Default(strconv.Itoa(defaultConfig.RegistryShards)).IntVar(&cfg.RegistryShards)
 	app.Flag("txt-owner-id", "When using the TXT registry, a name that identifies this instance of ExternalDNS (default: default)").Default(defaultConfig.TXTOwnerID).StringVar(&cfg.TXTOwnerID)
 	app.Flag("txt-prefix", "When using the TXT registry, a custom string that's prefixed to each ownership DNS record (optional)").Default(defaultConfig.TXTPrefix).StringVar(&cfg.TXTPrefix)
+	app.Flag("txt-suffix", "When using the TXT registry, a custom string that's inserted after the first label of each ownership DNS record, e.g. foo-txt.example.org, so that it doesn't share the name of a CNAME (optional, mutually exclusive with txt-prefix)").Default(defaultConfig.TXTSuffix).StringVar(&cfg.TXTSuffix)
+	app.Flag("txt-encode-record-type", "When using the TXT registry, encode the record type in the names of the ownership DNS records, e.g. a-foo.example.org, so that CNAMEs and records of several types on one name get their own (default: disabled)").BoolVar(&cfg.TXTEncodeRecordType)
+	app.Flag("txt-migrate", "When using the TXT registry, also read the ownership DNS records named with only txt-prefix, and rewrite the owned ones to the names given by txt-suffix and txt-encode-record-type (default: disabled)").BoolVar(&cfg.TXTMigrate)
+	app.Flag("txt-signing-key-file", "When using the TXT registry, sign the ownership DNS records with the HMAC key in this file, and ignore the ones not signed with it; specify multiple times to rotate keys, the first one signs (optional)").StringsVar(&cfg.TXTSigningKeyFiles)
+	app.Flag("txt-accept-unsigned", "When using the TXT registry with txt-signing-key-file, trust the ownership DNS records without signature and sign the owned ones, to start signing the records of an existing installation (default: disabled)").BoolVar(&cfg.TXTAcceptUnsigned)
+	app.Flag("txt-delete-orphans", "When using the TXT registry, delete the owned ownership DNS records whose records are gone, subject to the policy (default: disabled)").BoolVar(&cfg.TXTDeleteOrphans)
 
 	// Flags related to the main control loop
 	app.Flag("txt-cache-interval", "The interval between cache synchronizations in duration format (default: disabled)").Default(defaultConfig.TXTCacheInterval.String()).DurationVar(&cfg.TXTCacheInterval)
 	app.Flag("interval", "The interval between two consecutive synchronizations in duration format (default: 1m)").Default(defaultConfig.Interval.String()).DurationVar(&cfg.Interval)
+	app.Flag("min-event-sync-interval", "The minimum interval between two consecutive synchronizations triggered from kubernetes events in duration format (default: 5s)").Default(defaultConfig.MinEventSyncInterval.String()).DurationVar(&cfg.MinEventSyncInterval)
+	app.Flag("events", "When enabled, in addition to running every interval, the reconciliation loop will get triggered when supported sources change (default: disabled)").BoolVar(&cfg.UpdateEvents)
+	app.Flag("sync-timeout", "The deadline of a single synchronization in duration format, after which it is cancelled and the liveness check fails (default: disabled)").Default(defaultConfig.SyncTimeout.String()).DurationVar(&cfg.SyncTimeout)
 	app.Flag("once", "When enabled, exits the synchronization loop after the first iteration (default: disabled)").BoolVar(&cfg.Once)
 	app.Flag("dry-run", "When enabled, prints DNS record changes rather than actually performing them (default: disabled)").BoolVar(&cfg.DryRun)
 
+	// Flags related to leader election
+	app.Flag("leader-election", "When enabled, only the replica holding the leader election lock synchronizes, the others wait as standby (default: disabled)").BoolVar(&cfg.LeaderElect)
+	app.Flag("leader-election-namespace", "The namespace of the ConfigMap holding the leader election lock (default: default)").Default(defaultConfig.LeaderElectNamespace).StringVar(&cfg.LeaderElectNamespace)
+	app.Flag("leader-election-name", "The name of the ConfigMap holding the leader election lock (default: external-dns-leader)").Default(defaultConfig.LeaderElectName).StringVar(&cfg.LeaderElectName)
+	app.Flag("leader-election-lease-duration", "The duration standby replicas wait before taking over a lock which wasn't renewed (default: 15s)").Default(defaultConfig.LeaderLeaseDuration.String()).DurationVar(&cfg.LeaderLeaseDuration)
+	app.Flag("leader-election-renew-deadline", "The duration the leader retries renewing the lock before giving up leadership (default: 10s)").Default(defaultConfig.LeaderRenewDeadline.String()).DurationVar(&cfg.LeaderRenewDeadline)
+	app.Flag("leader-election-retry-period", "The interval between attempts to acquire or renew the lock (default: 2s)").Default(defaultConfig.LeaderRetryPeriod.String()).DurationVar(&cfg.LeaderRetryPeriod)
+
 	// Miscellaneous flags
 	app.Flag("log-format", "The format in which log messages are printed (default: text, options: text, json)").Default(defaultConfig.LogFormat).EnumVar(&cfg.LogFormat, "text", "json")
 	app.Flag("metrics-address", "Specify where to serve the metrics and health check endpoint (default: :7979)").Default(defaultConfig.MetricsAddress).StringVar(&cfg.MetricsAddress)
+	app.Flag("trigger-token", "When set, a POST request to /trigger on the metrics address with this bearer token triggers a synchronization (optional)").Default(defaultConfig.TriggerToken).StringVar(&cfg.TriggerToken)
 	app.Flag("log-level", "Set the level of logging. (default: info, options: panic, debug, info, warning, error, fatal").Default(defaultConfig.LogLevel).EnumVar(&cfg.LogLevel, allLogLevelsAsStrings()...)
 
-	_, err := app.Parse(args)
+	// Commands
+	app.Command("run", "Synchronize the DNS records continuously, or once with --once (default)").Default()
+	planCmd := app.Command("plan", "Calculate the changes to the DNS records once and print them without applying them. Exits with 2 if changes are pending")
+	planCmd.Flag("output", "The format in which the changes are printed (default: text, options: text, json)").Default(defaultConfig.PlanOutput).EnumVar(&cfg.PlanOutput, "text", "json")
+
+	command, err := app.Parse(args)
 	if err != nil {
 		return err
 	}
+	cfg.Command = command
 
 	return nil
 }
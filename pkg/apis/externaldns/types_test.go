@@ -62,15 +62,35 @@ var (
 		PDNSServer:              "http://localhost:8081",
 		PDNSAPIKey:              "",
 		Policy:                  "sync",
+		ConflictResolver:        "per-resource",
 		Registry:                "txt",
+		RegistryNamespace:       "default",
+		RegistryName:            "external-dns-registry",
+		RegistryShards:          8,
 		TXTOwnerID:              "default",
 		TXTPrefix:               "",
+		TXTSuffix:               "",
+		TXTEncodeRecordType:     false,
+		TXTMigrate:              false,
+		TXTAcceptUnsigned:       false,
+		TXTDeleteOrphans:        false,
 		TXTCacheInterval:        0,
 		Interval:                time.Minute,
+		MinEventSyncInterval:    5 * time.Second,
+		UpdateEvents:            false,
+		SyncTimeout:             0,
+		LeaderElect:             false,
+		LeaderElectNamespace:    "default",
+		LeaderElectName:         "external-dns-leader",
+		LeaderLeaseDuration:     15 * time.Second,
+		LeaderRenewDeadline:     10 * time.Second,
+		LeaderRetryPeriod:       2 * time.Second,
 		Once:                    false,
 		DryRun:                  false,
+		Command:                 "run",
 		LogFormat:               "text",
 		MetricsAddress:          ":7979",
+		TriggerToken:            "",
 		LogLevel:                logrus.InfoLevel.String(),
 		ConnectorSourceServer:   "localhost:8080",
 		ExoscaleEndpoint:        "https://api.exoscale.ch/dns",
@@ -118,15 +138,44 @@ var (
 		TLSClientCert:           "/path/to/cert.pem",
 		TLSClientCertKey:        "/path/to/key.pem",
 		Policy:                  "upsert-only",
+		ConflictResolver:        "oldest-resource",
+		DeletionThreshold:       10,
+		DeletionPercentage:      25.5,
+		DeletionThresholdAck:    "0123456789abcdef",
+		DeletionAckConfigMap:    "kube-system/external-dns",
+		DeletionGracePeriod:     5 * time.Minute,
+		DeletionGraceCycles:     3,
+		DampingCycles:           2,
+		DampingDuration:         90 * time.Second,
 		Registry:                "noop",
+		RegistryNamespace:       "kube-system",
+		RegistryName:            "dns-ownership",
+		RegistryShards:          4,
 		TXTOwnerID:              "owner-1",
 		TXTPrefix:               "associated-txt-record",
+		TXTSuffix:               "-txt",
+		TXTEncodeRecordType:     true,
+		TXTMigrate:              true,
+		TXTSigningKeyFiles:      []string{"/etc/external-dns/key-2", "/etc/external-dns/key-1"},
+		TXTAcceptUnsigned:       true,
+		TXTDeleteOrphans:        true,
 		TXTCacheInterval:        12 * time.Hour,
 		Interval:                10 * time.Minute,
+		MinEventSyncInterval:    30 * time.Second,
+		UpdateEvents:            true,
+		SyncTimeout:             10 * time.Minute,
+		LeaderElect:             true,
+		LeaderElectNamespace:    "kube-system",
+		LeaderElectName:         "external-dns-lock",
+		LeaderLeaseDuration:     30 * time.Second,
+		LeaderRenewDeadline:     20 * time.Second,
+		LeaderRetryPeriod:       5 * time.Second,
 		Once:                    true,
 		DryRun:                  true,
+		Command:                 "run",
 		LogFormat:               "json",
 		MetricsAddress:          "127.0.0.1:9099",
+		TriggerToken:            "trigger-token",
 		LogLevel:                logrus.DebugLevel.String(),
 		ConnectorSourceServer:   "localhost:8081",
 		ExoscaleEndpoint:        "https://api.foo.ch/dns",
@@ -197,15 +246,44 @@ func TestParseFlags(t *testing.T) {
 				"--aws-batch-change-interval=2s",
 				"--no-aws-evaluate-target-health",
 				"--policy=upsert-only",
+				"--conflict-resolver=oldest-resource",
+				"--deletion-threshold=10",
+				"--deletion-threshold-percentage=25.5",
+				"--deletion-threshold-ack=0123456789abcdef",
+				"--deletion-threshold-ack-configmap=kube-system/external-dns",
+				"--deletion-grace-period=5m",
+				"--deletion-grace-cycles=3",
+				"--damping-cycles=2",
+				"--damping-duration=90s",
 				"--registry=noop",
+				"--registry-namespace=kube-system",
+				"--registry-name=dns-ownership",
+				"--registry-shards=4",
 				"--txt-owner-id=owner-1",
 				"--txt-prefix=associated-txt-record",
+				"--txt-suffix=-txt",
+				"--txt-encode-record-type",
+				"--txt-migrate",
+				"--txt-signing-key-file=/etc/external-dns/key-2",
+				"--txt-signing-key-file=/etc/external-dns/key-1",
+				"--txt-accept-unsigned",
+				"--txt-delete-orphans",
 				"--txt-cache-interval=12h",
 				"--interval=10m",
+				"--min-event-sync-interval=30s",
+				"--events",
+				"--sync-timeout=10m",
+				"--leader-election",
+				"--leader-election-namespace=kube-system",
+				"--leader-election-name=external-dns-lock",
+				"--leader-election-lease-duration=30s",
+				"--leader-election-renew-deadline=20s",
+				"--leader-election-retry-period=5s",
 				"--once",
 				"--dry-run",
 				"--log-format=json",
 				"--metrics-address=127.0.0.1:9099",
+				"--trigger-token=trigger-token",
 				"--log-level=debug",
 				"--connector-source-server=localhost:8081",
 				"--exoscale-endpoint=https://api.foo.ch/dns",
@@ -221,58 +299,86 @@ func TestParseFlags(t *testing.T) {
 			title: "override everything via environment variables",
 			args:  []string{},
 			envVars: map[string]string{
-				"EXTERNAL_DNS_MASTER":                     "http://127.0.0.1:8080",
-				"EXTERNAL_DNS_KUBECONFIG":                 "/some/path",
-				"EXTERNAL_DNS_REQUEST_TIMEOUT":            "77s",
-				"EXTERNAL_DNS_ISTIO_INGRESS_GATEWAY":      "istio-other/istio-otheringressgateway",
-				"EXTERNAL_DNS_SOURCE":                     "service\ningress\nconnector",
-				"EXTERNAL_DNS_NAMESPACE":                  "namespace",
-				"EXTERNAL_DNS_FQDN_TEMPLATE":              "{{.Name}}.service.example.com",
-				"EXTERNAL_DNS_COMPATIBILITY":              "mate",
-				"EXTERNAL_DNS_PROVIDER":                   "google",
-				"EXTERNAL_DNS_GOOGLE_PROJECT":             "project",
-				"EXTERNAL_DNS_AZURE_CONFIG_FILE":          "azure.json",
-				"EXTERNAL_DNS_AZURE_RESOURCE_GROUP":       "arg",
-				"EXTERNAL_DNS_CLOUDFLARE_PROXIED":         "1",
-				"EXTERNAL_DNS_INFOBLOX_GRID_HOST":         "127.0.0.1",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_PORT":         "8443",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME":     "infoblox",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD":     "infoblox",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_VERSION":      "2.6.1",
-				"EXTERNAL_DNS_INFOBLOX_SSL_VERIFY":        "0",
-				"EXTERNAL_DNS_OCI_CONFIG_FILE":            "oci.yaml",
-				"EXTERNAL_DNS_INMEMORY_ZONE":              "example.org\ncompany.com",
-				"EXTERNAL_DNS_DOMAIN_FILTER":              "example.org\ncompany.com",
-				"EXTERNAL_DNS_PDNS_SERVER":                "http://ns.example.com:8081",
-				"EXTERNAL_DNS_PDNS_API_KEY":               "some-secret-key",
-				"EXTERNAL_DNS_PDNS_TLS_ENABLED":           "1",
-				"EXTERNAL_DNS_TLS_CA":                     "/path/to/ca.crt",
-				"EXTERNAL_DNS_TLS_CLIENT_CERT":            "/path/to/cert.pem",
-				"EXTERNAL_DNS_TLS_CLIENT_CERT_KEY":        "/path/to/key.pem",
-				"EXTERNAL_DNS_ZONE_ID_FILTER":             "/hostedzone/ZTST1\n/hostedzone/ZTST2",
-				"EXTERNAL_DNS_AWS_ZONE_TYPE":              "private",
-				"EXTERNAL_DNS_AWS_ZONE_TAGS":              "tag=foo",
-				"EXTERNAL_DNS_AWS_ASSUME_ROLE":            "some-other-role",
-				"EXTERNAL_DNS_AWS_BATCH_CHANGE_SIZE":      "100",
-				"EXTERNAL_DNS_AWS_BATCH_CHANGE_INTERVAL":  "2s",
-				"EXTERNAL_DNS_AWS_EVALUATE_TARGET_HEALTH": "0",
-				"EXTERNAL_DNS_POLICY":                     "upsert-only",
-				"EXTERNAL_DNS_REGISTRY":                   "noop",
-				"EXTERNAL_DNS_TXT_OWNER_ID":               "owner-1",
-				"EXTERNAL_DNS_TXT_PREFIX":                 "associated-txt-record",
-				"EXTERNAL_DNS_TXT_CACHE_INTERVAL":         "12h",
-				"EXTERNAL_DNS_INTERVAL":                   "10m",
-				"EXTERNAL_DNS_ONCE":                       "1",
-				"EXTERNAL_DNS_DRY_RUN":                    "1",
-				"EXTERNAL_DNS_LOG_FORMAT":                 "json",
-				"EXTERNAL_DNS_METRICS_ADDRESS":            "127.0.0.1:9099",
-				"EXTERNAL_DNS_LOG_LEVEL":                  "debug",
-				"EXTERNAL_DNS_CONNECTOR_SOURCE_SERVER":    "localhost:8081",
-				"EXTERNAL_DNS_EXOSCALE_ENDPOINT":          "https://api.foo.ch/dns",
-				"EXTERNAL_DNS_EXOSCALE_APIKEY":            "1",
-				"EXTERNAL_DNS_EXOSCALE_APISECRET":         "2",
-				"EXTERNAL_DNS_CRD_SOURCE_APIVERSION":      "test.k8s.io/v1alpha1",
-				"EXTERNAL_DNS_CRD_SOURCE_KIND":            "Endpoint",
+				"EXTERNAL_DNS_MASTER":                           "http://127.0.0.1:8080",
+				"EXTERNAL_DNS_KUBECONFIG":                       "/some/path",
+				"EXTERNAL_DNS_REQUEST_TIMEOUT":                  "77s",
+				"EXTERNAL_DNS_ISTIO_INGRESS_GATEWAY":            "istio-other/istio-otheringressgateway",
+				"EXTERNAL_DNS_SOURCE":                           "service\ningress\nconnector",
+				"EXTERNAL_DNS_NAMESPACE":                        "namespace",
+				"EXTERNAL_DNS_FQDN_TEMPLATE":                    "{{.Name}}.service.example.com",
+				"EXTERNAL_DNS_COMPATIBILITY":                    "mate",
+				"EXTERNAL_DNS_PROVIDER":                         "google",
+				"EXTERNAL_DNS_GOOGLE_PROJECT":                   "project",
+				"EXTERNAL_DNS_AZURE_CONFIG_FILE":                "azure.json",
+				"EXTERNAL_DNS_AZURE_RESOURCE_GROUP":             "arg",
+				"EXTERNAL_DNS_CLOUDFLARE_PROXIED":               "1",
+				"EXTERNAL_DNS_INFOBLOX_GRID_HOST":               "127.0.0.1",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_PORT":               "8443",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME":           "infoblox",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD":           "infoblox",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_VERSION":            "2.6.1",
+				"EXTERNAL_DNS_INFOBLOX_SSL_VERIFY":              "0",
+				"EXTERNAL_DNS_OCI_CONFIG_FILE":                  "oci.yaml",
+				"EXTERNAL_DNS_INMEMORY_ZONE":                    "example.org\ncompany.com",
+				"EXTERNAL_DNS_DOMAIN_FILTER":                    "example.org\ncompany.com",
+				"EXTERNAL_DNS_PDNS_SERVER":                      "http://ns.example.com:8081",
+				"EXTERNAL_DNS_PDNS_API_KEY":                     "some-secret-key",
+				"EXTERNAL_DNS_PDNS_TLS_ENABLED":                 "1",
+				"EXTERNAL_DNS_TLS_CA":                           "/path/to/ca.crt",
+				"EXTERNAL_DNS_TLS_CLIENT_CERT":                  "/path/to/cert.pem",
+				"EXTERNAL_DNS_TLS_CLIENT_CERT_KEY":              "/path/to/key.pem",
+				"EXTERNAL_DNS_ZONE_ID_FILTER":                   "/hostedzone/ZTST1\n/hostedzone/ZTST2",
+				"EXTERNAL_DNS_AWS_ZONE_TYPE":                    "private",
+				"EXTERNAL_DNS_AWS_ZONE_TAGS":                    "tag=foo",
+				"EXTERNAL_DNS_AWS_ASSUME_ROLE":                  "some-other-role",
+				"EXTERNAL_DNS_AWS_BATCH_CHANGE_SIZE":            "100",
+				"EXTERNAL_DNS_AWS_BATCH_CHANGE_INTERVAL":        "2s",
+				"EXTERNAL_DNS_AWS_EVALUATE_TARGET_HEALTH":       "0",
+				"EXTERNAL_DNS_POLICY":                           "upsert-only",
+				"EXTERNAL_DNS_CONFLICT_RESOLVER":                "oldest-resource",
+				"EXTERNAL_DNS_DELETION_THRESHOLD":               "10",
+				"EXTERNAL_DNS_DELETION_THRESHOLD_PERCENTAGE":    "25.5",
+				"EXTERNAL_DNS_DELETION_THRESHOLD_ACK":           "0123456789abcdef",
+				"EXTERNAL_DNS_DELETION_THRESHOLD_ACK_CONFIGMAP": "kube-system/external-dns",
+				"EXTERNAL_DNS_DELETION_GRACE_PERIOD":            "5m",
+				"EXTERNAL_DNS_DELETION_GRACE_CYCLES":            "3",
+				"EXTERNAL_DNS_DAMPING_CYCLES":                   "2",
+				"EXTERNAL_DNS_DAMPING_DURATION":                 "90s",
+				"EXTERNAL_DNS_REGISTRY":                         "noop",
+				"EXTERNAL_DNS_REGISTRY_NAMESPACE":               "kube-system",
+				"EXTERNAL_DNS_REGISTRY_NAME":                    "dns-ownership",
+				"EXTERNAL_DNS_REGISTRY_SHARDS":                  "4",
+				"EXTERNAL_DNS_TXT_OWNER_ID":                     "owner-1",
+				"EXTERNAL_DNS_TXT_PREFIX":                       "associated-txt-record",
+				"EXTERNAL_DNS_TXT_SUFFIX":                       "-txt",
+				"EXTERNAL_DNS_TXT_ENCODE_RECORD_TYPE":           "1",
+				"EXTERNAL_DNS_TXT_MIGRATE":                      "1",
+				"EXTERNAL_DNS_TXT_SIGNING_KEY_FILE":             "/etc/external-dns/key-2\n/etc/external-dns/key-1",
+				"EXTERNAL_DNS_TXT_ACCEPT_UNSIGNED":              "1",
+				"EXTERNAL_DNS_TXT_DELETE_ORPHANS":               "1",
+				"EXTERNAL_DNS_TXT_CACHE_INTERVAL":               "12h",
+				"EXTERNAL_DNS_INTERVAL":                         "10m",
+				"EXTERNAL_DNS_MIN_EVENT_SYNC_INTERVAL":          "30s",
+				"EXTERNAL_DNS_EVENTS":                           "1",
+				"EXTERNAL_DNS_SYNC_TIMEOUT":                     "10m",
+				"EXTERNAL_DNS_LEADER_ELECTION":                  "1",
+				"EXTERNAL_DNS_LEADER_ELECTION_NAMESPACE":        "kube-system",
+				"EXTERNAL_DNS_LEADER_ELECTION_NAME":             "external-dns-lock",
+				"EXTERNAL_DNS_LEADER_ELECTION_LEASE_DURATION":   "30s",
+				"EXTERNAL_DNS_LEADER_ELECTION_RENEW_DEADLINE":   "20s",
+				"EXTERNAL_DNS_LEADER_ELECTION_RETRY_PERIOD":     "5s",
+				"EXTERNAL_DNS_ONCE":                             "1",
+				"EXTERNAL_DNS_DRY_RUN":                          "1",
+				"EXTERNAL_DNS_LOG_FORMAT":                       "json",
+				"EXTERNAL_DNS_METRICS_ADDRESS":                  "127.0.0.1:9099",
+				"EXTERNAL_DNS_TRIGGER_TOKEN":                    "trigger-token",
+				"EXTERNAL_DNS_LOG_LEVEL":                        "debug",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_SERVER":          "localhost:8081",
+				"EXTERNAL_DNS_EXOSCALE_ENDPOINT":                "https://api.foo.ch/dns",
+				"EXTERNAL_DNS_EXOSCALE_APIKEY":                  "1",
+				"EXTERNAL_DNS_EXOSCALE_APISECRET":               "2",
+				"EXTERNAL_DNS_CRD_SOURCE_APIVERSION":            "test.k8s.io/v1alpha1",
+				"EXTERNAL_DNS_CRD_SOURCE_KIND":                  "Endpoint",
 			},
 			expected: overriddenConfig,
 		},
@@ -288,6 +394,21 @@ func TestParseFlags(t *testing.T) {
 	}
 }
 
+func TestParsePlanCommand(t *testing.T) {
+	cfg := NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{"plan", "--source=service", "--provider=google", "--output=json"}))
+	assert.Equal(t, "plan", cfg.Command)
+	assert.Equal(t, "json", cfg.PlanOutput)
+
+	cfg = NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{"--source=service", "--provider=google", "plan"}))
+	assert.Equal(t, "plan", cfg.Command)
+	assert.Equal(t, "text", cfg.PlanOutput)
+
+	cfg = NewConfig()
+	assert.Error(t, cfg.ParseFlags([]string{"plan", "--source=service", "--provider=google", "--output=yaml"}))
+}
+
 // helper functions
 
 func setEnv(t *testing.T, env map[string]string) map[string]string {
@@ -312,6 +433,7 @@ func TestPasswordsNotLogged(t *testing.T) {
 		DynPassword:          "dyn-pass",
 		InfobloxWapiPassword: "infoblox-pass",
 		PDNSAPIKey:           "pdns-api-key",
+		TriggerToken:         "trigger-token",
 	}
 
 	s := cfg.String()
@@ -319,4 +441,5 @@ func TestPasswordsNotLogged(t *testing.T) {
 	assert.False(t, strings.Contains(s, "dyn-pass"))
 	assert.False(t, strings.Contains(s, "infoblox-pass"))
 	assert.False(t, strings.Contains(s, "pdns-api-key"))
+	assert.False(t, strings.Contains(s, "trigger-token"))
 }
@@ -65,5 +65,28 @@ func ValidateConfig(cfg *externaldns.Config) error {
 			return errors.New("TTL specified for Dyn is negative")
 		}
 	}
+
+	if cfg.Registry == "configmap" && cfg.RegistryShards < 1 {
+		return errors.New("registry-shards must be positive")
+	}
+
+	if cfg.TXTPrefix != "" && cfg.TXTSuffix != "" {
+		return errors.New("txt-prefix and txt-suffix are mutually exclusive")
+	}
+	if cfg.TXTMigrate && cfg.TXTSuffix == "" && !cfg.TXTEncodeRecordType {
+		return errors.New("txt-migrate requires txt-suffix or txt-encode-record-type")
+	}
+	if cfg.TXTAcceptUnsigned && len(cfg.TXTSigningKeyFiles) == 0 {
+		return errors.New("txt-accept-unsigned requires txt-signing-key-file")
+	}
+
+	if cfg.LeaderElect {
+		if cfg.LeaderLeaseDuration <= cfg.LeaderRenewDeadline {
+			return errors.New("leader election lease duration must be greater than the renew deadline")
+		}
+		if cfg.LeaderRenewDeadline <= cfg.LeaderRetryPeriod {
+			return errors.New("leader election renew deadline must be greater than the retry period")
+		}
+	}
 	return nil
 }
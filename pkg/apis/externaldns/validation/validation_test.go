@@ -18,6 +18,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kubernetes-incubator/external-dns/pkg/apis/externaldns"
 
@@ -52,6 +53,68 @@ func TestValidateFlags(t *testing.T) {
 	assert.Error(t, ValidateConfig(cfg))
 }
 
+func TestValidateLeaderElection(t *testing.T) {
+	newLeaderElectionConfig := func() *externaldns.Config {
+		cfg := newValidConfig(t)
+		cfg.LeaderElect = true
+		cfg.LeaderLeaseDuration = 15 * time.Second
+		cfg.LeaderRenewDeadline = 10 * time.Second
+		cfg.LeaderRetryPeriod = 2 * time.Second
+		return cfg
+	}
+	cfg := newLeaderElectionConfig()
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.LeaderRenewDeadline = cfg.LeaderLeaseDuration
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg = newLeaderElectionConfig()
+	cfg.LeaderRetryPeriod = cfg.LeaderRenewDeadline
+	assert.Error(t, ValidateConfig(cfg))
+
+	// not validated unless leader election is enabled
+	cfg.LeaderElect = false
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateTXTNames(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.TXTPrefix = "txt."
+	cfg.TXTSuffix = "-txt"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.TXTPrefix = ""
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg = newValidConfig(t)
+	cfg.TXTMigrate = true
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.TXTEncodeRecordType = true
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateTXTSigning(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.TXTAcceptUnsigned = true
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.TXTSigningKeyFiles = []string{"/etc/external-dns/key"}
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateRegistryShards(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.RegistryShards = 0
+	assert.NoError(t, ValidateConfig(cfg), "should only apply to the configmap registry")
+
+	cfg.Registry = "configmap"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.RegistryShards = 1
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
 func newValidConfig(t *testing.T) *externaldns.Config {
 	cfg := externaldns.NewConfig()
 
@@ -18,6 +18,8 @@ package plan
 
 import (
 	"sort"
+	"strconv"
+	"time"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 )
@@ -29,6 +31,14 @@ type ConflictResolver interface {
 	ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint
 }
 
+// ConflictResolvers is a registry of available conflict resolvers.
+var ConflictResolvers = map[string]ConflictResolver{
+	"per-resource":    PerResource{},
+	"oldest-resource": OldestResource{},
+	"priority":        Priority{},
+	"multi-cluster":   MultiCluster{},
+}
+
 // PerResource allows only one resource to own a given dns name
 type PerResource struct{}
 
@@ -64,7 +74,120 @@ func (s PerResource) ResolveUpdate(current *endpoint.Endpoint, candidates []*end
 
 // less returns true if endpoint x is less than y
 func (s PerResource) less(x, y *endpoint.Endpoint) bool {
-	return x.Targets.IsLess(y.Targets)
+	return endpoint.NewTargetSet(x.RecordType, x.Targets).Less(endpoint.NewTargetSet(y.RecordType, y.Targets))
+}
+
+// OldestResource allows only one resource to own a given dns name, the one created first wins
+// Candidates without creation timestamp are considered to be created last
+type OldestResource struct{}
+
+// ResolveCreate takes the oldest endpoint to acquire the DNS record, ties are resolved by PerResource
+func (s OldestResource) ResolveCreate(candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return PerResource{}.ResolveCreate(s.oldest(candidates))
+}
+
+// ResolveUpdate takes the oldest endpoint to acquire the DNS record, ties are resolved by PerResource
+// i.e. "current" resource is kept if it is among the oldest ones
+func (s OldestResource) ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return PerResource{}.ResolveUpdate(current, s.oldest(candidates))
+}
+
+// oldest returns the candidates with the earliest creation timestamp
+func (s OldestResource) oldest(candidates []*endpoint.Endpoint) []*endpoint.Endpoint {
+	var oldest []*endpoint.Endpoint
+	var min time.Time
+	for _, ep := range candidates {
+		created, err := time.Parse(time.RFC3339, ep.Labels[endpoint.CreationTimestampLabelKey])
+		if err != nil {
+			continue
+		}
+		switch {
+		case len(oldest) == 0 || created.Before(min):
+			oldest, min = []*endpoint.Endpoint{ep}, created
+		case created.Equal(min):
+			oldest = append(oldest, ep)
+		}
+	}
+	if len(oldest) == 0 {
+		return candidates
+	}
+	return oldest
+}
+
+// Priority allows only one resource to own a given dns name, the one with the highest priority wins
+// Candidates without priority are considered to have a priority of 0
+type Priority struct{}
+
+// ResolveCreate takes the endpoint with the highest priority to acquire the DNS record, ties are resolved by PerResource
+func (s Priority) ResolveCreate(candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return PerResource{}.ResolveCreate(s.highest(candidates))
 }
 
-// TODO: with cross-resource/cross-cluster setup alternative variations of ConflictResolver can be used
+// ResolveUpdate takes the endpoint with the highest priority to acquire the DNS record, ties are resolved by PerResource
+// i.e. "current" resource is kept if it is among the ones with the highest priority
+func (s Priority) ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return PerResource{}.ResolveUpdate(current, s.highest(candidates))
+}
+
+// highest returns the candidates with the highest priority
+func (s Priority) highest(candidates []*endpoint.Endpoint) []*endpoint.Endpoint {
+	var highest []*endpoint.Endpoint
+	var max int64
+	for _, ep := range candidates {
+		priority, err := strconv.ParseInt(ep.Labels[endpoint.ConflictPriorityLabelKey], 10, 64)
+		if err != nil {
+			priority = 0
+		}
+		switch {
+		case len(highest) == 0 || priority > max:
+			highest, max = []*endpoint.Endpoint{ep}, priority
+		case priority == max:
+			highest = append(highest, ep)
+		}
+	}
+	return highest
+}
+
+// MultiCluster allows several resources, e.g. the same service deployed to several clusters, to share a given dns name
+// The targets of all candidates are merged into a single record
+type MultiCluster struct{}
+
+// ResolveCreate merges the targets of all candidates into the endpoint picked by PerResource
+func (s MultiCluster) ResolveCreate(candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.merge(PerResource{}.ResolveCreate(candidates), candidates)
+}
+
+// ResolveUpdate merges the targets of all candidates into the endpoint picked by PerResource
+func (s MultiCluster) ResolveUpdate(current *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	return s.merge(PerResource{}.ResolveUpdate(current, candidates), candidates)
+}
+
+// merge returns a copy of base with the targets of all candidates of the same record type
+// CNAME records can only have a single target, hence they are never merged
+func (s MultiCluster) merge(base *endpoint.Endpoint, candidates []*endpoint.Endpoint) *endpoint.Endpoint {
+	if base == nil || base.RecordType == endpoint.RecordTypeCNAME {
+		return base
+	}
+
+	seen := map[string]bool{}
+	targets := endpoint.Targets{}
+	for _, ep := range candidates {
+		if ep.RecordType != base.RecordType {
+			continue
+		}
+		for _, target := range ep.Targets {
+			if key := endpoint.NormalizeTarget(ep.RecordType, target); !seen[key] {
+				seen[key] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+	sort.Strings(targets)
+
+	merged := endpoint.NewEndpointWithTTL(base.DNSName, base.RecordType, base.RecordTTL, targets...)
+	for k, v := range base.Labels {
+		merged.Labels[k] = v
+	}
+	merged.ProviderSpecific = base.ProviderSpecific
+	return merged
+}
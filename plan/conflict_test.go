@@ -24,6 +24,9 @@ import (
 )
 
 var _ ConflictResolver = PerResource{}
+var _ ConflictResolver = OldestResource{}
+var _ ConflictResolver = Priority{}
+var _ ConflictResolver = MultiCluster{}
 
 type ResolverSuite struct {
 	// resolvers
@@ -132,6 +135,71 @@ func (suite *ResolverSuite) TestStrictResolver() {
 	suite.Equal(suite.bar127A, suite.perResource.ResolveUpdate(suite.legacyBar192A, []*endpoint.Endpoint{suite.bar127A, suite.bar192A}), " legacy record's resource value will not match, should pick minimum")
 }
 
+func (suite *ResolverSuite) TestOldestResourceResolver() {
+	oldest := OldestResource{}
+	bar192AOld := withLabel(suite.bar192A, endpoint.CreationTimestampLabelKey, "2018-01-01T00:00:00Z")
+	bar127ANew := withLabel(suite.bar127A, endpoint.CreationTimestampLabelKey, "2018-06-01T00:00:00Z")
+	bar127AOld := withLabel(suite.bar127A, endpoint.CreationTimestampLabelKey, "2018-01-01T00:00:00Z")
+
+	suite.Equal(bar192AOld, oldest.ResolveCreate([]*endpoint.Endpoint{bar127ANew, bar192AOld}), "should pick oldest one")
+	suite.Equal(bar192AOld, oldest.ResolveCreate([]*endpoint.Endpoint{suite.bar127A, bar192AOld}), "should prefer resources with creation timestamp")
+	suite.Equal(bar127AOld, oldest.ResolveCreate([]*endpoint.Endpoint{bar192AOld, bar127AOld}), "should pick min one among the oldest")
+	suite.Equal(suite.bar127A, oldest.ResolveCreate([]*endpoint.Endpoint{suite.bar192A, suite.bar127A}), "should pick min one without creation timestamps")
+
+	suite.Equal(bar192AOld, oldest.ResolveUpdate(suite.bar127A, []*endpoint.Endpoint{bar127ANew, bar192AOld}), "should pick oldest one over existing resource")
+	suite.Equal(bar192AOld, oldest.ResolveUpdate(suite.bar192A, []*endpoint.Endpoint{bar127AOld, bar192AOld}), "should pick existing resource among the oldest")
+}
+
+func (suite *ResolverSuite) TestPriorityResolver() {
+	priority := Priority{}
+	bar192AHigh := withLabel(suite.bar192A, endpoint.ConflictPriorityLabelKey, "10")
+	bar127ALow := withLabel(suite.bar127A, endpoint.ConflictPriorityLabelKey, "-1")
+	bar127AHigh := withLabel(suite.bar127A, endpoint.ConflictPriorityLabelKey, "10")
+
+	suite.Equal(bar192AHigh, priority.ResolveCreate([]*endpoint.Endpoint{suite.bar127A, bar192AHigh}), "should pick highest priority")
+	suite.Equal(suite.bar192A, priority.ResolveCreate([]*endpoint.Endpoint{bar127ALow, suite.bar192A}), "should treat missing priority as 0")
+	suite.Equal(bar127AHigh, priority.ResolveCreate([]*endpoint.Endpoint{bar192AHigh, bar127AHigh}), "should pick min one among the highest")
+
+	suite.Equal(bar192AHigh, priority.ResolveUpdate(suite.bar127A, []*endpoint.Endpoint{suite.bar127A, bar192AHigh}), "should pick highest priority over existing resource")
+	suite.Equal(bar192AHigh, priority.ResolveUpdate(suite.bar192A, []*endpoint.Endpoint{bar127AHigh, bar192AHigh}), "should pick existing resource among the highest")
+}
+
+func (suite *ResolverSuite) TestMultiClusterResolver() {
+	multiCluster := MultiCluster{}
+
+	merged := multiCluster.ResolveCreate([]*endpoint.Endpoint{suite.bar192A, suite.bar127A, suite.bar127AAnother})
+	suite.Equal("bar", merged.DNSName)
+	suite.Equal(endpoint.RecordTypeA, merged.RecordType)
+	suite.Equal(endpoint.Targets{"127.0.0.1", "192.168.0.1", "8.8.8.8"}, merged.Targets, "should merge all targets")
+	suite.Equal(suite.bar127A.Labels[endpoint.ResourceLabelKey], merged.Labels[endpoint.ResourceLabelKey], "should keep labels of min one")
+	suite.Equal(endpoint.Targets{"127.0.0.1"}, suite.bar127A.Targets, "should not modify candidates")
+
+	merged = multiCluster.ResolveUpdate(suite.bar192A, []*endpoint.Endpoint{suite.bar127A, suite.bar192A})
+	suite.Equal(endpoint.Targets{"127.0.0.1", "192.168.0.1"}, merged.Targets, "should merge all targets")
+	suite.Equal(suite.bar192A.Labels[endpoint.ResourceLabelKey], merged.Labels[endpoint.ResourceLabelKey], "should keep labels of existing resource")
+
+	merged = multiCluster.ResolveCreate([]*endpoint.Endpoint{suite.fooV1Cname, suite.fooA5})
+	suite.Equal(endpoint.RecordTypeA, merged.RecordType)
+	suite.Equal(endpoint.Targets{"5.5.5.5"}, merged.Targets, "should only merge targets of the same record type")
+	suite.Equal(suite.fooV1Cname, multiCluster.ResolveCreate([]*endpoint.Endpoint{suite.fooV2Cname, suite.fooV1Cname}), "should not merge CNAME targets")
+}
+
+// withLabel returns a copy of the endpoint with an additional label
+func withLabel(e *endpoint.Endpoint, key, value string) *endpoint.Endpoint {
+	labels := endpoint.NewLabels()
+	for k, v := range e.Labels {
+		labels[k] = v
+	}
+	labels[key] = value
+	return &endpoint.Endpoint{
+		DNSName:    e.DNSName,
+		Targets:    e.Targets,
+		RecordType: e.RecordType,
+		RecordTTL:  e.RecordTTL,
+		Labels:     labels,
+	}
+}
+
 func TestConflictResolver(t *testing.T) {
 	suite.Run(t, new(ResolverSuite))
 }
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+var dampedUpdates = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: "external_dns",
+		Subsystem: "plan",
+		Name:      "damped_updates",
+		Help:      "Number of target changes held back until the desired targets are stable",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(dampedUpdates)
+}
+
+// pendingTargets tracks since when and for how many reconcile cycles the desired targets of a record are stable
+type pendingTargets struct {
+	targets endpoint.TargetSet
+	since   time.Time
+	cycles  int
+}
+
+// DampingPolicy holds back changes of the targets of records until the desired targets have been stable for
+// StableCycles consecutive reconcile cycles or StableDuration, whichever comes first, so that flapping targets
+// don't turn into a provider API call each. Creations, deletions and replacements are applied right away, and
+// so are updates which don't change the targets or whose desired record opts out with the damping label.
+// Updates which are held back are dropped as a whole, including changes of the TTL or of labels.
+type DampingPolicy struct {
+	// Policy which is applied before updates are damped
+	Policy Policy
+	// Number of consecutive reconcile cycles the desired targets must be stable for, 0 disables the check
+	StableCycles int
+	// Duration the desired targets must be stable for, 0 disables the check
+	StableDuration time.Duration
+
+	mutex   sync.Mutex
+	pending map[planKey]pendingTargets
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+	// copies don't update the metrics
+	copied bool
+}
+
+// Copy returns a copy of the policy including the pending targets
+func (p *DampingPolicy) Copy() Policy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pending := make(map[planKey]pendingTargets, len(p.pending))
+	for key, pt := range p.pending {
+		pending[key] = pt
+	}
+	return &DampingPolicy{
+		Policy:         CopyPolicy(p.Policy),
+		StableCycles:   p.StableCycles,
+		StableDuration: p.StableDuration,
+		pending:        pending,
+		now:            p.now,
+		copied:         true,
+	}
+}
+
+// Apply applies the wrapped policy and drops the updates whose desired targets aren't stable yet
+func (p *DampingPolicy) Apply(changes *Changes) *Changes {
+	if p.Policy != nil {
+		changes = p.Policy.Apply(changes)
+	}
+	if p.StableCycles <= 0 && p.StableDuration <= 0 {
+		return changes
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
+	}
+
+	filtered := &Changes{
+		Create:     changes.Create,
+		Delete:     changes.Delete,
+		ReplaceOld: changes.ReplaceOld,
+		ReplaceNew: changes.ReplaceNew,
+	}
+	// only records whose targets still differ keep pending targets, so that stability is counted in consecutive cycles
+	pending := map[planKey]pendingTargets{}
+
+	for i, desired := range changes.UpdateNew {
+		current := changes.UpdateOld[i]
+		targets := endpoint.NewTargetSet(desired.RecordType, desired.Targets)
+		if targets.Equal(endpoint.NewTargetSet(current.RecordType, current.Targets)) || desired.Labels[endpoint.DampingLabelKey] == "false" {
+			filtered.UpdateOld = append(filtered.UpdateOld, current)
+			filtered.UpdateNew = append(filtered.UpdateNew, desired)
+			continue
+		}
+
+		key := newPlanKey(desired)
+		pt, ok := p.pending[key]
+		if !ok || !pt.targets.Equal(targets) {
+			pt = pendingTargets{targets: targets, since: now}
+		}
+		pt.cycles++
+
+		if p.stable(pt, now) {
+			filtered.UpdateOld = append(filtered.UpdateOld, current)
+			filtered.UpdateNew = append(filtered.UpdateNew, desired)
+			continue
+		}
+
+		log.Debugf("Holding back update of %s to %s, stable since %s for %d cycles", current, targets, pt.since.Format(time.RFC3339), pt.cycles)
+		pending[key] = pt
+	}
+
+	p.pending = pending
+	if !p.copied {
+		dampedUpdates.Set(float64(len(pending)))
+	}
+
+	return filtered
+}
+
+// stable returns true if the desired targets have been stable for long enough to be applied
+func (p *DampingPolicy) stable(pt pendingTargets, now time.Time) bool {
+	if p.StableDuration > 0 && now.Sub(pt.since) >= p.StableDuration {
+		return true
+	}
+	return p.StableCycles > 0 && pt.cycles >= p.StableCycles
+}
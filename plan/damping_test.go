@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+func TestDampingCycles(t *testing.T) {
+	current := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.1.1.1")}
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "2.2.2.2")}
+	policy := &DampingPolicy{Policy: &SyncPolicy{}, StableCycles: 3}
+
+	for cycle := 1; cycle < 3; cycle++ {
+		changes := calculateWithPolicy(policy, current, desired)
+		assert.Empty(t, changes.UpdateNew)
+		assert.Empty(t, changes.UpdateOld)
+	}
+
+	changes := calculateWithPolicy(policy, current, desired)
+	assert.Equal(t, desired, changes.UpdateNew)
+	assert.Equal(t, current, changes.UpdateOld)
+}
+
+func TestDampingRestartsOnNewTargets(t *testing.T) {
+	current := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.1.1.1")}
+	flapping := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "2.2.2.2")}
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "3.3.3.3")}
+	policy := &DampingPolicy{Policy: &SyncPolicy{}, StableCycles: 2}
+
+	assert.Empty(t, calculateWithPolicy(policy, current, flapping).UpdateNew)
+	assert.Empty(t, calculateWithPolicy(policy, current, desired).UpdateNew)
+	assert.Equal(t, desired, calculateWithPolicy(policy, current, desired).UpdateNew)
+}
+
+func TestDampingRestartsWhenTargetsSettle(t *testing.T) {
+	current := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.1.1.1")}
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "2.2.2.2")}
+	policy := &DampingPolicy{Policy: &SyncPolicy{}, StableCycles: 2}
+
+	assert.Empty(t, calculateWithPolicy(policy, current, desired).UpdateNew)
+	// the desired targets are back to the current ones for a cycle
+	assert.Empty(t, calculateWithPolicy(policy, current, current).UpdateNew)
+	assert.Empty(t, calculateWithPolicy(policy, current, desired).UpdateNew)
+	assert.Equal(t, desired, calculateWithPolicy(policy, current, desired).UpdateNew)
+}
+
+func TestDampingDuration(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	current := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.1.1.1")}
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "2.2.2.2")}
+	policy := &DampingPolicy{Policy: &SyncPolicy{}, StableDuration: 5 * time.Minute, now: func() time.Time { return now }}
+
+	assert.Empty(t, calculateWithPolicy(policy, current, desired).UpdateNew)
+	now = now.Add(4 * time.Minute)
+	assert.Empty(t, calculateWithPolicy(policy, current, desired).UpdateNew)
+	now = now.Add(time.Minute)
+	assert.Equal(t, desired, calculateWithPolicy(policy, current, desired).UpdateNew)
+}
+
+func TestDampingExemptions(t *testing.T) {
+	current := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("deleted.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpointWithTTL("ttl.example.org", endpoint.RecordTypeA, 300, "1.1.1.1"),
+		endpoint.NewEndpoint("opt-out.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("replaced.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+	}
+	optOut := endpoint.NewEndpoint("opt-out.example.org", endpoint.RecordTypeA, "2.2.2.2")
+	optOut.Labels[endpoint.DampingLabelKey] = "false"
+	ttl := endpoint.NewEndpointWithTTL("ttl.example.org", endpoint.RecordTypeA, 600, "1.1.1.1")
+	created := endpoint.NewEndpoint("created.example.org", endpoint.RecordTypeA, "2.2.2.2")
+	replacement := endpoint.NewEndpoint("replaced.example.org", endpoint.RecordTypeCNAME, "lb.example.org")
+	policy := &DampingPolicy{Policy: &SyncPolicy{}, StableCycles: 5}
+
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{optOut, ttl, created, replacement})
+	validateEntries(t, changes.Create, []*endpoint.Endpoint{created})
+	validateEntries(t, changes.Delete, current[:1])
+	validateEntries(t, changes.UpdateNew, []*endpoint.Endpoint{ttl, optOut})
+	validateEntries(t, changes.UpdateOld, current[1:3])
+	validateEntries(t, changes.ReplaceNew, []*endpoint.Endpoint{replacement})
+	validateEntries(t, changes.ReplaceOld, current[3:])
+}
+
+func TestDampingDisabled(t *testing.T) {
+	current := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.1.1.1")}
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "2.2.2.2")}
+
+	assert.Equal(t, desired, calculateWithPolicy(&DampingPolicy{Policy: &SyncPolicy{}}, current, desired).UpdateNew)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+var tombstonedRecords = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: "external_dns",
+		Subsystem: "plan",
+		Name:      "tombstoned_records",
+		Help:      "Number of records missing from the desired state whose deletion is delayed",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(tombstonedRecords)
+}
+
+// tombstone tracks since when and for how many reconcile cycles a record has been missing
+type tombstone struct {
+	since  time.Time
+	cycles int
+}
+
+// DelayedDeletionPolicy delays the deletion of records which disappear from the desired state, so that
+// records don't flap during rolling deploys or brief outages of a source. A record is only deleted once it
+// has been missing continuously for GracePeriod or GraceCycles reconcile cycles, whichever comes first.
+//
+// When a record goes missing, its deletion is turned into an update which marks the record with a tombstone
+// label holding the time since when it is missing. Further cycles leave the record alone until it is deleted,
+// so the grace period costs a single write. Registries which persist labels, such as the TXT registry, thereby
+// keep the tombstone across restarts, the cycles missed meanwhile are estimated from its time and Interval.
+// A record which reappears in the desired state differs from the current one by its tombstone, hence the plan
+// updates it and the tombstone is removed. For registries which don't persist labels tombstones are only kept
+// in memory.
+type DelayedDeletionPolicy struct {
+	// Policy which is applied before deletions are delayed
+	Policy Policy
+	// Duration a record must be missing before it is deleted, 0 disables the check
+	GracePeriod time.Duration
+	// Number of reconcile cycles a record must be missing before it is deleted, 0 disables the check
+	GraceCycles int
+	// The interval between reconcile cycles, used to estimate the cycles of tombstones found after a restart
+	Interval time.Duration
+
+	mutex      sync.Mutex
+	tombstones map[planKey]tombstone
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+	// copies don't update the metrics
+	copied bool
+}
+
+// Copy returns a copy of the policy including the tombstones
+func (p *DelayedDeletionPolicy) Copy() Policy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	tombstones := make(map[planKey]tombstone, len(p.tombstones))
+	for key, ts := range p.tombstones {
+		tombstones[key] = ts
+	}
+	return &DelayedDeletionPolicy{
+		Policy:      CopyPolicy(p.Policy),
+		GracePeriod: p.GracePeriod,
+		GraceCycles: p.GraceCycles,
+		Interval:    p.Interval,
+		tombstones:  tombstones,
+		now:         p.now,
+		copied:      true,
+	}
+}
+
+// Apply applies the wrapped policy and delays the deletions of records which haven't been missing
+// for long enough. Records which just went missing are updated with a tombstone label instead.
+func (p *DelayedDeletionPolicy) Apply(changes *Changes) *Changes {
+	if p.Policy != nil {
+		changes = p.Policy.Apply(changes)
+	}
+	if p.GracePeriod <= 0 && p.GraceCycles <= 0 {
+		return changes
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
+	}
+
+	filtered := &Changes{
+		Create:     changes.Create,
+		UpdateOld:  changes.UpdateOld,
+		UpdateNew:  changes.UpdateNew,
+		ReplaceOld: changes.ReplaceOld,
+		ReplaceNew: changes.ReplaceNew,
+	}
+	// only records which are still missing keep their tombstones
+	tombstones := map[planKey]tombstone{}
+
+	for _, ep := range changes.Delete {
+		key := newPlanKey(ep)
+		ts, known := p.tombstones[key]
+		since, persisted := tombstoneSince(ep.Labels)
+		if !known {
+			ts = tombstone{since: now}
+			if persisted {
+				// e.g. after a restart, estimate the cycles from the time the record is missing for
+				ts.since = since
+				if p.Interval > 0 {
+					ts.cycles = int(now.Sub(since) / p.Interval)
+				}
+			}
+		}
+		ts.cycles++
+
+		if p.expired(ts, now) {
+			log.Infof("Deleting %s, missing since %s for %d cycles", ep, ts.since.Format(time.RFC3339), ts.cycles)
+			filtered.Delete = append(filtered.Delete, ep)
+			continue
+		}
+
+		log.Infof("Delaying deletion of %s, missing since %s for %d cycles", ep, ts.since.Format(time.RFC3339), ts.cycles)
+		tombstones[key] = ts
+		if !known && !persisted {
+			filtered.UpdateOld = append(filtered.UpdateOld, ep)
+			filtered.UpdateNew = append(filtered.UpdateNew, withTombstone(ep, ts))
+		}
+	}
+
+	p.tombstones = tombstones
+	if !p.copied {
+		tombstonedRecords.Set(float64(len(tombstones)))
+	}
+
+	return filtered
+}
+
+// expired returns true if the record has been missing for long enough to be deleted
+func (p *DelayedDeletionPolicy) expired(ts tombstone, now time.Time) bool {
+	if p.GracePeriod > 0 && now.Sub(ts.since) >= p.GracePeriod {
+		return true
+	}
+	return p.GraceCycles > 0 && ts.cycles >= p.GraceCycles
+}
+
+// tombstoneSince returns the time of the tombstone stored in the labels of a record, if any
+func tombstoneSince(labels endpoint.Labels) (time.Time, bool) {
+	since, err := time.Parse(time.RFC3339, labels[endpoint.TombstoneLabelKey])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// withTombstone returns a copy of the record which carries the tombstone in its labels
+func withTombstone(ep *endpoint.Endpoint, ts tombstone) *endpoint.Endpoint {
+	marked := ep.DeepCopy()
+	if marked.Labels == nil {
+		marked.Labels = endpoint.NewLabels()
+	}
+	marked.Labels[endpoint.TombstoneLabelKey] = ts.since.UTC().Format(time.RFC3339)
+	return marked
+}
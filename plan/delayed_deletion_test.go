@@ -0,0 +1,159 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+// persist returns the current records after applying the changes, like a registry which persists labels would
+func persist(current []*endpoint.Endpoint, changes *Changes) []*endpoint.Endpoint {
+	records := map[planKey]*endpoint.Endpoint{}
+	for _, ep := range current {
+		records[newPlanKey(ep)] = ep
+	}
+	for _, ep := range changes.Delete {
+		delete(records, newPlanKey(ep))
+	}
+	for _, ep := range append(changes.Create, changes.UpdateNew...) {
+		records[newPlanKey(ep)] = ep
+	}
+	result := []*endpoint.Endpoint{}
+	for _, ep := range records {
+		result = append(result, ep)
+	}
+	return result
+}
+
+func TestDelayedDeletionCycles(t *testing.T) {
+	current := ownedRecords("default", 1)
+	policy := &DelayedDeletionPolicy{Policy: &SyncPolicy{}, GraceCycles: 3}
+
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Empty(t, changes.Delete)
+	require.Len(t, changes.UpdateNew, 1)
+	assert.Equal(t, current[0], changes.UpdateOld[0])
+	assert.Equal(t, current[0].Targets, changes.UpdateNew[0].Targets)
+	assert.NotEmpty(t, changes.UpdateNew[0].Labels[endpoint.TombstoneLabelKey])
+	assert.Equal(t, "default", changes.UpdateNew[0].Labels[endpoint.OwnerLabelKey])
+	current = persist(current, changes)
+
+	// the tombstone is only written once
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Equal(t, &Changes{}, changes)
+
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	require.Len(t, changes.Delete, 1)
+	assert.Empty(t, changes.UpdateNew)
+}
+
+func TestDelayedDeletionGracePeriod(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	current := ownedRecords("default", 1)
+	policy := &DelayedDeletionPolicy{Policy: &SyncPolicy{}, GracePeriod: 10 * time.Minute, now: func() time.Time { return now }}
+
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Empty(t, changes.Delete)
+	require.Len(t, changes.UpdateNew, 1)
+	assert.Equal(t, "2018-06-01T10:00:00Z", changes.UpdateNew[0].Labels[endpoint.TombstoneLabelKey])
+	current = persist(current, changes)
+
+	now = now.Add(9 * time.Minute)
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Equal(t, &Changes{}, changes)
+
+	now = now.Add(time.Minute)
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.Delete, 1)
+}
+
+func TestDelayedDeletionSurvivesRestarts(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	newPolicy := func() *DelayedDeletionPolicy {
+		return &DelayedDeletionPolicy{Policy: &SyncPolicy{}, GraceCycles: 3, Interval: time.Minute, now: func() time.Time { return now }}
+	}
+	current := ownedRecords("default", 1)
+	changes := calculateWithPolicy(newPolicy(), current, []*endpoint.Endpoint{})
+	assert.Empty(t, changes.Delete)
+	current = persist(current, changes)
+
+	// a new policy picks up the tombstone from the labels and estimates the cycles from its time
+	now = now.Add(time.Minute)
+	changes = calculateWithPolicy(newPolicy(), current, []*endpoint.Endpoint{})
+	assert.Equal(t, &Changes{}, changes)
+
+	now = now.Add(time.Minute)
+	changes = calculateWithPolicy(newPolicy(), current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.Delete, 1)
+}
+
+func TestDelayedDeletionWithoutPersistedLabels(t *testing.T) {
+	current := ownedRecords("default", 1)
+	policy := &DelayedDeletionPolicy{Policy: &SyncPolicy{}, GraceCycles: 2}
+
+	// current records never carry the tombstone, e.g. with the noop registry
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Empty(t, changes.Delete)
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.Delete, 1)
+}
+
+func TestDelayedDeletionReappearingRecord(t *testing.T) {
+	current := ownedRecords("default", 1)
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint(current[0].DNSName, endpoint.RecordTypeA, current[0].Targets...)}
+	policy := &DelayedDeletionPolicy{Policy: &SyncPolicy{}, GraceCycles: 2}
+
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	current = persist(current, changes)
+
+	// the reappearing record is updated to remove its tombstone
+	p := &Plan{
+		Policies:  []Policy{policy},
+		Current:   current,
+		Desired:   desired,
+		LabelKeys: []string{endpoint.TombstoneLabelKey},
+	}
+	changes = p.Calculate().Changes
+	require.Len(t, changes.UpdateNew, 1)
+	assert.Empty(t, changes.UpdateNew[0].Labels[endpoint.TombstoneLabelKey])
+	current = persist(current, changes)
+
+	// the next disappearance starts a new grace period
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Empty(t, changes.Delete)
+	require.Len(t, changes.UpdateNew, 1)
+	assert.NotEmpty(t, changes.UpdateNew[0].Labels[endpoint.TombstoneLabelKey])
+}
+
+func TestDelayedDeletionDisabled(t *testing.T) {
+	current := ownedRecords("default", 2)
+	changes := calculateWithPolicy(&DelayedDeletionPolicy{Policy: &SyncPolicy{}}, current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.Delete, 2)
+	assert.Empty(t, changes.UpdateNew)
+}
+
+func TestDelayedDeletionWrapsPolicy(t *testing.T) {
+	current := ownedRecords("default", 2)
+	changes := calculateWithPolicy(&DelayedDeletionPolicy{Policy: &UpsertOnlyPolicy{}, GraceCycles: 2}, current, []*endpoint.Endpoint{})
+	assert.Equal(t, &Changes{}, changes)
+}
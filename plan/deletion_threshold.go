@@ -0,0 +1,246 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+var (
+	deletionThresholdBlocked = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "plan",
+			Name:      "deletion_threshold_blocked",
+			Help:      "Whether changes are blocked by the deletion threshold (1) or not (0)",
+		},
+	)
+	deletionThresholdBlockedDeletions = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "plan",
+			Name:      "deletion_threshold_blocked_deletions",
+			Help:      "Number of deletions in the changes blocked by the deletion threshold",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(deletionThresholdBlocked)
+	prometheus.MustRegister(deletionThresholdBlockedDeletions)
+}
+
+// currentRecordsPolicy is implemented by policies which need the current records in addition to the changes
+type currentRecordsPolicy interface {
+	applyWithCurrent(current []*endpoint.Endpoint, changes *Changes) *Changes
+}
+
+// Acknowledger tells whether an operator acknowledged the changes blocked by a DeletionThresholdPolicy
+type Acknowledger interface {
+	// Acknowledged returns true if the blocked changes with the given fingerprint may be applied
+	Acknowledged(fingerprint string) bool
+}
+
+// StaticAcknowledger acknowledges the blocked changes with the fingerprint it holds, e.g. given by a flag
+type StaticAcknowledger string
+
+// Acknowledged returns true if fingerprint matches the acknowledged one
+func (a StaticAcknowledger) Acknowledged(fingerprint string) bool {
+	return string(a) != "" && string(a) == fingerprint
+}
+
+// BlockedChanges describes changes refused by a DeletionThresholdPolicy
+type BlockedChanges struct {
+	// Fingerprint identifies the deletions, it is used to acknowledge them
+	Fingerprint string `json:"fingerprint"`
+	// Deletions which were refused, including the old records of replacements
+	Deletions []*endpoint.Endpoint `json:"deletions"`
+	// Number of records owned before the deletions
+	Owned int `json:"owned"`
+	// Since when changes are blocked
+	Since time.Time `json:"since"`
+}
+
+// DeletionThresholdPolicy protects against deleting most records at once, e.g. when a source suddenly
+// returns no endpoints because of an RBAC change. It refuses all changes when the deletions of records
+// owned by OwnerID, including the old records of replacements, exceed MaxDeletions or
+// MaxDeletionPercentage percent of the owned records. Deletions of records which aren't planned, i.e. of
+// orphaned ownership records, are neither counted as deletions nor as owned records.
+// Once tripped, changes with deletions stay blocked, even if the number of deletions drops below the
+// thresholds, until one of the Acknowledgers acknowledges the fingerprint of the blocked deletions.
+type DeletionThresholdPolicy struct {
+	// Policy which is applied before the thresholds are checked
+	Policy Policy
+	// Maximum number of deletions, 0 disables the check
+	MaxDeletions int
+	// Maximum percentage of the owned records which may be deleted, 0 disables the check
+	MaxDeletionPercentage float64
+	// Owner of the records, all records are considered owned if empty
+	OwnerID string
+	// Acknowledgers which can release blocked changes
+	Acknowledgers []Acknowledger
+
+	mutex   sync.Mutex
+	blocked *BlockedChanges
+	// copies don't update the metrics
+	copied bool
+}
+
+// Copy returns a copy of the policy including the blocked changes
+func (p *DeletionThresholdPolicy) Copy() Policy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return &DeletionThresholdPolicy{
+		Policy:                CopyPolicy(p.Policy),
+		MaxDeletions:          p.MaxDeletions,
+		MaxDeletionPercentage: p.MaxDeletionPercentage,
+		OwnerID:               p.OwnerID,
+		Acknowledgers:         p.Acknowledgers,
+		blocked:               p.blocked,
+		copied:                true,
+	}
+}
+
+// Apply applies the wrapped policy and checks the thresholds. The percentage is only checked
+// when the current records are known, i.e. when the policy is applied by Plan.Calculate.
+func (p *DeletionThresholdPolicy) Apply(changes *Changes) *Changes {
+	return p.applyWithCurrent(nil, changes)
+}
+
+func (p *DeletionThresholdPolicy) applyWithCurrent(current []*endpoint.Endpoint, changes *Changes) *Changes {
+	if p.Policy != nil {
+		changes = p.Policy.Apply(changes)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// replacements delete their old records as well, orphaned ownership records are left out
+	// as they aren't counted as owned records either
+	deletions := p.owned(filterRecordsForPlan(changes.Delete), changes.ReplaceOld)
+	if len(deletions) == 0 {
+		p.setBlocked(nil)
+		return changes
+	}
+	owned := len(p.owned(filterRecordsForPlan(current)))
+	fingerprint := deletionsFingerprint(deletions)
+
+	if p.blocked == nil && !p.exceeded(len(deletions), owned) {
+		return changes
+	}
+	for _, ack := range p.Acknowledgers {
+		if ack.Acknowledged(fingerprint) {
+			log.Infof("Deletion of %d out of %d owned records acknowledged (fingerprint %s)", len(deletions), owned, fingerprint)
+			p.setBlocked(nil)
+			return changes
+		}
+	}
+
+	blocked := &BlockedChanges{
+		Fingerprint: fingerprint,
+		Deletions:   deletions,
+		Owned:       owned,
+		Since:       time.Now(),
+	}
+	if p.blocked != nil {
+		blocked.Since = p.blocked.Since
+	}
+	p.setBlocked(blocked)
+
+	log.Errorf("Refusing to apply changes which delete %d out of %d owned records, acknowledge them with fingerprint %s", len(deletions), owned, fingerprint)
+	for _, ep := range deletions {
+		log.Errorf("Blocked deletion: %s", ep)
+	}
+	return &Changes{}
+}
+
+// exceeded returns true if the number of deletions exceeds one of the thresholds
+func (p *DeletionThresholdPolicy) exceeded(deletions, owned int) bool {
+	if p.MaxDeletions > 0 && deletions > p.MaxDeletions {
+		return true
+	}
+	return p.MaxDeletionPercentage > 0 && owned > 0 && float64(deletions)*100/float64(owned) > p.MaxDeletionPercentage
+}
+
+// owned returns the records of the lists owned by OwnerID
+func (p *DeletionThresholdPolicy) owned(lists ...[]*endpoint.Endpoint) []*endpoint.Endpoint {
+	owned := []*endpoint.Endpoint{}
+	for _, records := range lists {
+		for _, record := range records {
+			if p.OwnerID == "" || record.Labels[endpoint.OwnerLabelKey] == p.OwnerID {
+				owned = append(owned, record)
+			}
+		}
+	}
+	return owned
+}
+
+func (p *DeletionThresholdPolicy) setBlocked(blocked *BlockedChanges) {
+	p.blocked = blocked
+	if p.copied {
+		return
+	}
+	if blocked == nil {
+		deletionThresholdBlocked.Set(0)
+		deletionThresholdBlockedDeletions.Set(0)
+		return
+	}
+	deletionThresholdBlocked.Set(1)
+	deletionThresholdBlockedDeletions.Set(float64(len(blocked.Deletions)))
+}
+
+// Blocked returns the currently blocked changes or nil if nothing is blocked
+func (p *DeletionThresholdPolicy) Blocked() *BlockedChanges {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.blocked
+}
+
+// ServeHTTP exposes the currently blocked changes as JSON, null if nothing is blocked
+func (p *DeletionThresholdPolicy) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(p.Blocked()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// deletionsFingerprint returns a short hash identifying the given deletions regardless of their order
+func deletionsFingerprint(deletions []*endpoint.Endpoint) string {
+	keys := make([]string, 0, len(deletions))
+	for _, ep := range deletions {
+		keys = append(keys, ep.DNSName+" "+ep.RecordType+" "+endpoint.NewTargetSet(ep.RecordType, ep.Targets).String())
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+// ownedRecords returns n A records owned by owner
+func ownedRecords(owner string, n int) []*endpoint.Endpoint {
+	records := []*endpoint.Endpoint{}
+	for i := 0; i < n; i++ {
+		ep := endpoint.NewEndpoint(fmt.Sprintf("record-%d.%s.example.org", i, owner), endpoint.RecordTypeA, fmt.Sprintf("1.2.3.%d", i))
+		ep.Labels[endpoint.OwnerLabelKey] = owner
+		records = append(records, ep)
+	}
+	return records
+}
+
+func calculateWithPolicy(policy Policy, current, desired []*endpoint.Endpoint) *Changes {
+	p := &Plan{
+		Policies: []Policy{policy},
+		Current:  current,
+		Desired:  desired,
+	}
+	return p.Calculate().Changes
+}
+
+func TestDeletionThresholdAbsolute(t *testing.T) {
+	current := ownedRecords("default", 10)
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 3, OwnerID: "default"}
+
+	changes := calculateWithPolicy(policy, current, current[3:])
+	assert.Len(t, changes.Delete, 3)
+	assert.Nil(t, policy.Blocked())
+
+	changes = calculateWithPolicy(policy, current, current[6:])
+	assert.Empty(t, changes.Delete)
+	require.NotNil(t, policy.Blocked())
+	assert.Len(t, policy.Blocked().Deletions, 6)
+	assert.Equal(t, 10, policy.Blocked().Owned)
+}
+
+func TestDeletionThresholdPercentage(t *testing.T) {
+	current := ownedRecords("default", 10)
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletionPercentage: 50, OwnerID: "default"}
+
+	changes := calculateWithPolicy(policy, current, current[5:])
+	assert.Len(t, changes.Delete, 5)
+	assert.Nil(t, policy.Blocked())
+
+	changes = calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Equal(t, &Changes{}, changes)
+	assert.NotNil(t, policy.Blocked())
+}
+
+func TestDeletionThresholdIgnoresForeignRecords(t *testing.T) {
+	current := append(ownedRecords("default", 2), ownedRecords("other", 10)...)
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 2, OwnerID: "default"}
+
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.Delete, 12)
+	assert.Nil(t, policy.Blocked())
+}
+
+func TestDeletionThresholdBlocksCreatesAndUpdates(t *testing.T) {
+	current := ownedRecords("default", 4)
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint("new.example.org", endpoint.RecordTypeA, "8.8.8.8")}
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 1}
+
+	assert.Equal(t, &Changes{}, calculateWithPolicy(policy, current, desired))
+}
+
+func TestDeletionThresholdCountsReplacements(t *testing.T) {
+	replaced := ownedRecords("default", 2)
+	replacements := []*endpoint.Endpoint{}
+	for _, ep := range replaced {
+		replacements = append(replacements, endpoint.NewEndpoint(ep.DNSName, endpoint.RecordTypeCNAME, "elsewhere.example.org"))
+	}
+	policy := &DeletionThresholdPolicy{MaxDeletions: 1, OwnerID: "default"}
+
+	changes := policy.Apply(&Changes{ReplaceOld: replaced, ReplaceNew: replacements})
+	assert.Equal(t, &Changes{}, changes)
+	require.NotNil(t, policy.Blocked())
+	assert.Len(t, policy.Blocked().Deletions, 2)
+	assert.Equal(t, deletionsFingerprint(replaced), policy.Blocked().Fingerprint)
+}
+
+func TestDeletionThresholdIgnoresOrphans(t *testing.T) {
+	current := ownedRecords("default", 2)
+	orphans := []*endpoint.Endpoint{}
+	for i := 0; i < 3; i++ {
+		orphan := endpoint.NewEndpoint(fmt.Sprintf("orphan-%d.default.example.org", i), endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=default\"")
+		orphan.Labels[endpoint.OwnerLabelKey] = "default"
+		orphans = append(orphans, orphan)
+	}
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 1, MaxDeletionPercentage: 50, OwnerID: "default"}
+
+	p := &Plan{
+		Policies: []Policy{policy},
+		Current:  current,
+		Desired:  current[1:],
+		Orphans:  orphans,
+	}
+	changes := p.Calculate().Changes
+	assert.Len(t, changes.Delete, 4, "orphans shouldn't count against the thresholds")
+	assert.Nil(t, policy.Blocked())
+}
+
+func TestDeletionThresholdStaysBlockedUntilAcknowledged(t *testing.T) {
+	current := ownedRecords("default", 10)
+	ack := StaticAcknowledger("")
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 3, OwnerID: "default"}
+	policy.Acknowledgers = []Acknowledger{&ack}
+
+	calculateWithPolicy(policy, current, current[5:])
+	require.NotNil(t, policy.Blocked())
+	since := policy.Blocked().Since
+
+	// fewer deletions than the threshold are still blocked
+	changes := calculateWithPolicy(policy, current, current[1:])
+	assert.Empty(t, changes.Delete)
+	require.NotNil(t, policy.Blocked())
+	assert.Equal(t, since, policy.Blocked().Since)
+
+	// acknowledging a different fingerprint doesn't release the changes
+	ack = StaticAcknowledger("unknown")
+	changes = calculateWithPolicy(policy, current, current[1:])
+	assert.Empty(t, changes.Delete)
+
+	// acknowledging the fingerprint of the blocked deletions releases them
+	ack = StaticAcknowledger(policy.Blocked().Fingerprint)
+	changes = calculateWithPolicy(policy, current, current[1:])
+	assert.Len(t, changes.Delete, 1)
+	assert.Nil(t, policy.Blocked())
+}
+
+func TestDeletionThresholdReleasedWithoutDeletions(t *testing.T) {
+	current := ownedRecords("default", 10)
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 3}
+
+	calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	require.NotNil(t, policy.Blocked())
+
+	changes := calculateWithPolicy(policy, current, current)
+	assert.Empty(t, changes.Delete)
+	assert.Nil(t, policy.Blocked())
+}
+
+func TestDeletionThresholdWrapsPolicy(t *testing.T) {
+	current := ownedRecords("default", 10)
+	policy := &DeletionThresholdPolicy{Policy: &UpsertOnlyPolicy{}, MaxDeletions: 3}
+
+	changes := calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+	assert.Empty(t, changes.Delete)
+	assert.Nil(t, policy.Blocked())
+}
+
+func TestDeletionsFingerprint(t *testing.T) {
+	records := ownedRecords("default", 3)
+	reversed := []*endpoint.Endpoint{records[2], records[1], records[0]}
+
+	assert.Equal(t, deletionsFingerprint(records), deletionsFingerprint(reversed))
+	assert.NotEqual(t, deletionsFingerprint(records), deletionsFingerprint(records[1:]))
+	assert.Len(t, deletionsFingerprint(records), 16)
+}
+
+func TestDeletionThresholdServeHTTP(t *testing.T) {
+	current := ownedRecords("default", 10)
+	policy := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 3}
+
+	rec := httptest.NewRecorder()
+	policy.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "null\n", rec.Body.String())
+
+	calculateWithPolicy(policy, current, []*endpoint.Endpoint{})
+
+	rec = httptest.NewRecorder()
+	policy.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	blocked := &BlockedChanges{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), blocked))
+	assert.Equal(t, policy.Blocked().Fingerprint, blocked.Fingerprint)
+	assert.Len(t, blocked.Deletions, 10)
+}
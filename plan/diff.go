@@ -0,0 +1,241 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+// Actions of the entries of a Diff
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionReplace = "replace"
+)
+
+// actionMarkers are the markers of the actions in the human readable output
+var actionMarkers = map[string]string{
+	ActionCreate:  "+",
+	ActionUpdate:  "~",
+	ActionDelete:  "-",
+	ActionReplace: "-/+",
+}
+
+// RecordState is the state of a record before or after a change
+type RecordState struct {
+	Targets endpoint.Targets `json:"targets"`
+	TTL     endpoint.TTL     `json:"ttl,omitempty"`
+}
+
+// DiffEntry is a single change of a Diff
+type DiffEntry struct {
+	Action     string `json:"action"`
+	Zone       string `json:"zone"`
+	Owner      string `json:"owner"`
+	DNSName    string `json:"dnsName"`
+	RecordType string `json:"recordType"`
+	// State before the change, nil for creations
+	Old *RecordState `json:"old,omitempty"`
+	// State after the change, nil for deletions
+	New *RecordState `json:"new,omitempty"`
+}
+
+// DiffSummary counts the changes of a Diff by action
+type DiffSummary struct {
+	Create  int `json:"create"`
+	Update  int `json:"update"`
+	Delete  int `json:"delete"`
+	Replace int `json:"replace"`
+}
+
+// Diff is a readable representation of Changes, sorted by zone, owner and DNS name
+type Diff struct {
+	Entries []DiffEntry `json:"changes"`
+	Summary DiffSummary `json:"summary"`
+}
+
+// NewDiff returns the Diff of the given changes. Records are assigned to the longest of the given
+// zones their DNS name belongs to, records which don't belong to any of them have an empty zone.
+// Records without an owner, i.e. new ones, are attributed to owner.
+func NewDiff(changes *Changes, zones []string, owner string) *Diff {
+	d := &Diff{Entries: []DiffEntry{}}
+	add := func(action string, ep *endpoint.Endpoint, old, new *RecordState) {
+		entryOwner := ep.Labels[endpoint.OwnerLabelKey]
+		if entryOwner == "" {
+			entryOwner = owner
+		}
+		d.Entries = append(d.Entries, DiffEntry{
+			Action:     action,
+			Zone:       ZoneOf(ep.DNSName, zones),
+			Owner:      entryOwner,
+			DNSName:    ep.DNSName,
+			RecordType: ep.RecordType,
+			Old:        old,
+			New:        new,
+		})
+	}
+
+	for _, ep := range changes.Create {
+		add(ActionCreate, ep, nil, recordState(ep))
+		d.Summary.Create++
+	}
+	for i, ep := range changes.UpdateNew {
+		var old *RecordState
+		if i < len(changes.UpdateOld) {
+			old = recordState(changes.UpdateOld[i])
+		}
+		add(ActionUpdate, ep, old, recordState(ep))
+		d.Summary.Update++
+	}
+	for _, ep := range changes.Delete {
+		add(ActionDelete, ep, recordState(ep), nil)
+		d.Summary.Delete++
+	}
+	for _, ep := range changes.ReplaceOld {
+		add(ActionReplace, ep, recordState(ep), nil)
+	}
+	for _, ep := range changes.ReplaceNew {
+		add(ActionReplace, ep, nil, recordState(ep))
+		d.Summary.Replace++
+	}
+
+	sort.SliceStable(d.Entries, func(i, j int) bool {
+		a, b := d.Entries[i], d.Entries[j]
+		if a.Zone != b.Zone {
+			return a.Zone < b.Zone
+		}
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		if a.DNSName != b.DNSName {
+			return a.DNSName < b.DNSName
+		}
+		// deletions of replaced records come before their replacements
+		if (a.New == nil) != (b.New == nil) {
+			return a.New == nil
+		}
+		return a.RecordType < b.RecordType
+	})
+
+	return d
+}
+
+// HasChanges returns true if the Diff contains any change
+func (d *Diff) HasChanges() bool {
+	return len(d.Entries) > 0
+}
+
+// WriteJSON writes the Diff as JSON
+func (d *Diff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// WriteText writes the Diff in a human readable form, grouped by zone and owner
+func (d *Diff) WriteText(w io.Writer) error {
+	var lines []string
+	group := ""
+	for _, e := range d.Entries {
+		zone := e.Zone
+		if zone == "" {
+			zone = "(unknown)"
+		}
+		if g := fmt.Sprintf("zone %s, owner %s:", zone, e.Owner); g != group {
+			if group != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, g)
+			group = g
+		}
+		lines = append(lines, fmt.Sprintf("  %-3s %s %s %s", actionMarkers[e.Action], endpoint.UnicodeDNSName(e.DNSName), e.RecordType, e.describe()))
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	if d.HasChanges() {
+		lines = append(lines, fmt.Sprintf("Plan: %d to create, %d to update, %d to delete, %d to replace.",
+			d.Summary.Create, d.Summary.Update, d.Summary.Delete, d.Summary.Replace))
+	} else {
+		lines = append(lines, "No changes. DNS records are up-to-date.")
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// describe returns the targets and TTL of the entry, as old -> new for updates
+func (e DiffEntry) describe() string {
+	switch {
+	case e.Old == nil:
+		return e.New.String()
+	case e.New == nil:
+		return e.Old.String()
+	}
+
+	var parts []string
+	if e.Old.Targets.String() != e.New.Targets.String() {
+		parts = append(parts, fmt.Sprintf("%s -> %s", e.Old.Targets, e.New.Targets))
+	} else {
+		parts = append(parts, e.New.Targets.String())
+	}
+	if e.Old.TTL != e.New.TTL {
+		parts = append(parts, fmt.Sprintf("ttl %s -> %s", ttlString(e.Old.TTL), ttlString(e.New.TTL)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *RecordState) String() string {
+	if !s.TTL.IsConfigured() {
+		return s.Targets.String()
+	}
+	return fmt.Sprintf("%s, ttl %d", s.Targets, s.TTL)
+}
+
+func ttlString(ttl endpoint.TTL) string {
+	if !ttl.IsConfigured() {
+		return "default"
+	}
+	return fmt.Sprintf("%d", ttl)
+}
+
+func recordState(ep *endpoint.Endpoint) *RecordState {
+	return &RecordState{Targets: ep.Targets, TTL: ep.RecordTTL}
+}
+
+// ZoneOf returns the longest of the zones the DNS name belongs to, or an empty string if none
+func ZoneOf(dnsName string, zones []string) string {
+	name := strings.ToLower(strings.TrimSuffix(dnsName, "."))
+	match := ""
+	for _, zone := range zones {
+		zone = strings.ToLower(strings.TrimSuffix(endpoint.NormalizeDNSName(strings.TrimSpace(zone)), "."))
+		if zone == "" || len(zone) <= len(match) {
+			continue
+		}
+		if name == zone || strings.HasSuffix(name, "."+zone) {
+			match = zone
+		}
+	}
+	return match
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+func diffTestChanges() *Changes {
+	owned := func(ep *endpoint.Endpoint, owner string) *endpoint.Endpoint {
+		ep.Labels[endpoint.OwnerLabelKey] = owner
+		return ep
+	}
+	return &Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("new.example.org", endpoint.RecordTypeA, 300, "1.2.3.4"),
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+		},
+		UpdateOld: []*endpoint.Endpoint{
+			owned(endpoint.NewEndpointWithTTL("api.example.org", endpoint.RecordTypeA, 300, "1.1.1.1"), "other"),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			owned(endpoint.NewEndpointWithTTL("api.example.org", endpoint.RecordTypeA, 600, "2.2.2.2"), "other"),
+		},
+		Delete: []*endpoint.Endpoint{
+			owned(endpoint.NewEndpoint("old.example.org", endpoint.RecordTypeA, "4.4.4.4"), "default"),
+		},
+		ReplaceOld: []*endpoint.Endpoint{
+			owned(endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeA, "5.5.5.5"), "default"),
+		},
+		ReplaceNew: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeCNAME, "elb.amazonaws.com"),
+		},
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	diff := NewDiff(diffTestChanges(), []string{"example.org"}, "default")
+	assert.True(t, diff.HasChanges())
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, diff.WriteText(buf))
+	assert.Equal(t, `zone (unknown), owner default:
+  +   www.example.com CNAME lb.example.com
+
+zone example.org, owner default:
+  -/+ lb.example.org A 5.5.5.5
+  -/+ lb.example.org CNAME elb.amazonaws.com
+  +   new.example.org A 1.2.3.4, ttl 300
+  -   old.example.org A 4.4.4.4
+
+zone example.org, owner other:
+  ~   api.example.org A 1.1.1.1 -> 2.2.2.2, ttl 300 -> 600
+
+Plan: 2 to create, 1 to update, 1 to delete, 1 to replace.
+`, buf.String())
+}
+
+func TestDiffJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, NewDiff(diffTestChanges(), []string{"example.org", "example.com"}, "default").WriteJSON(buf))
+
+	diff := &Diff{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), diff))
+	assert.Equal(t, DiffSummary{Create: 2, Update: 1, Delete: 1, Replace: 1}, diff.Summary)
+	require.Len(t, diff.Entries, 6)
+	assert.Equal(t, DiffEntry{
+		Action:     ActionCreate,
+		Zone:       "example.com",
+		Owner:      "default",
+		DNSName:    "www.example.com",
+		RecordType: endpoint.RecordTypeCNAME,
+		New:        &RecordState{Targets: endpoint.Targets{"lb.example.com"}},
+	}, diff.Entries[0])
+	assert.Equal(t, &RecordState{Targets: endpoint.Targets{"1.1.1.1"}, TTL: 300}, diff.Entries[5].Old)
+	assert.Equal(t, &RecordState{Targets: endpoint.Targets{"2.2.2.2"}, TTL: 600}, diff.Entries[5].New)
+}
+
+func TestDiffWithoutChanges(t *testing.T) {
+	diff := NewDiff(&Changes{}, nil, "default")
+	assert.False(t, diff.HasChanges())
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, diff.WriteText(buf))
+	assert.Equal(t, "No changes. DNS records are up-to-date.\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, diff.WriteJSON(buf))
+	assert.JSONEq(t, `{"changes": [], "summary": {"create": 0, "update": 0, "delete": 0, "replace": 0}}`, buf.String())
+}
+
+func TestZoneOf(t *testing.T) {
+	zones := []string{"example.org", "sub.example.org.", "bücher.example"}
+	assert.Equal(t, "example.org", ZoneOf("foo.example.org", zones))
+	assert.Equal(t, "example.org", ZoneOf("example.org", zones))
+	assert.Equal(t, "sub.example.org", ZoneOf("foo.sub.example.org", zones))
+	assert.Equal(t, "xn--bcher-kva.example", ZoneOf("www.xn--bcher-kva.example", zones))
+	assert.Equal(t, "", ZoneOf("fooexample.org", zones))
+}
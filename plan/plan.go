@@ -18,6 +18,7 @@ package plan
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -30,8 +31,23 @@ type Plan struct {
 	Current []*endpoint.Endpoint
 	// List of desired records
 	Desired []*endpoint.Endpoint
+	// Ownership records of the registry whose records are gone, see registry.OrphanFinder.
+	// They are deleted like records missing from the desired state, under the same policies.
+	Orphans []*endpoint.Endpoint
+	// Desired records which were rejected, e.g. as invalid. They are neither created nor updated,
+	// but the current records with their name and type aren't deleted either.
+	Protected []*endpoint.Endpoint
 	// Policies under which the desired changes are calculated
 	Policies []Policy
+	// Resolver used to pick a record when several desired records want the same dnsName and type
+	// PerResource is used if not set
+	Resolver ConflictResolver
+	// Keys of Endpoint.ProviderSpecific which are persisted and read back by the DNS provider
+	// A record is updated when the value of any of them changes
+	ProviderSpecificKeys []string
+	// Keys of Endpoint.Labels which are persisted and read back by the registry
+	// A record is updated when the value of any of them changes
+	LabelKeys []string
 	// List of changes necessary to move towards desired state
 	// Populated after calling Calculate()
 	Changes *Changes
@@ -47,28 +63,72 @@ type Changes struct {
 	UpdateNew []*endpoint.Endpoint
 	// Records that need to be deleted
 	Delete []*endpoint.Endpoint
+	// Records that need to be replaced by records of a different type on the same dnsName,
+	// e.g. when an A record turns into a CNAME record (current data)
+	ReplaceOld []*endpoint.Endpoint
+	// Records that replace records of a different type on the same dnsName (desired data)
+	// ReplaceOld and ReplaceNew are not necessarily of the same length, see Stages
+	ReplaceNew []*endpoint.Endpoint
+}
+
+// Stages splits the changes into an ordered list of changes which must be applied one after another.
+// Most DNS providers don't support changing the type of a record in place, and per RFC 1034 a CNAME
+// record cannot coexist with any other record, hence replaced records are deleted in a first stage
+// before their replacements are created together with all other changes in a second stage.
+func (c *Changes) Stages() []*Changes {
+	if len(c.ReplaceOld) == 0 && len(c.ReplaceNew) == 0 {
+		return []*Changes{c}
+	}
+
+	return []*Changes{
+		{
+			Delete: c.ReplaceOld,
+		},
+		{
+			Create:    append(append([]*endpoint.Endpoint{}, c.Create...), c.ReplaceNew...),
+			UpdateOld: c.UpdateOld,
+			UpdateNew: c.UpdateNew,
+			Delete:    c.Delete,
+		},
+	}
 }
 
 // planTable is a supplementary struct for Plan
-// each row correspond to a dnsName -> (current record + all desired records)
+// each row correspond to a (dnsName, recordType) -> (current record + all desired records)
 /*
 planTable: (-> = target)
---------------------------------------------------------
-DNSName | Current record | Desired Records             |
---------------------------------------------------------
-foo.com | -> 1.1.1.1     | [->1.1.1.1, ->elb.com]      |  = no action
---------------------------------------------------------
-bar.com |                | [->191.1.1.1, ->190.1.1.1]  |  = create (bar.com -> 190.1.1.1)
---------------------------------------------------------
+------------------------------------------------------------------
+DNSName | Type  | Current record | Desired Records             |
+------------------------------------------------------------------
+foo.com | A     | -> 1.1.1.1     | [->1.1.1.1]                 |  = no action
+------------------------------------------------------------------
+foo.com | AAAA  |                | [->2001:db8::1]             |  = create (foo.com -> 2001:db8::1)
+------------------------------------------------------------------
+bar.com | A     |                | [->191.1.1.1, ->190.1.1.1]  |  = create (bar.com -> 190.1.1.1)
+------------------------------------------------------------------
 "=", i.e. result of calculation relies on supplied ConflictResolver
 */
 type planTable struct {
-	rows     map[string]*planTableRow
+	rows     map[planKey]*planTableRow
 	resolver ConflictResolver
+	// dnsNames on which records are replaced by records of a different type
+	replaced map[string]bool
+	// keys of provider specific properties and labels compared to detect updates
+	providerSpecificKeys []string
+	labelKeys            []string
 }
 
-func newPlanTable() planTable { //TODO: make resolver configurable
-	return planTable{map[string]*planTableRow{}, PerResource{}}
+// planKey identifies a single row of the planTable
+type planKey struct {
+	dnsName    string
+	recordType string
+}
+
+func newPlanTable(resolver ConflictResolver, providerSpecificKeys, labelKeys []string) planTable {
+	if resolver == nil {
+		resolver = PerResource{}
+	}
+	return planTable{map[planKey]*planTableRow{}, resolver, map[string]bool{}, providerSpecificKeys, labelKeys}
 }
 
 // planTableRow
@@ -84,28 +144,85 @@ func (t planTableRow) String() string {
 }
 
 func (t planTable) addCurrent(e *endpoint.Endpoint) {
-	dnsName := sanitizeDNSName(e.DNSName)
-	if _, ok := t.rows[dnsName]; !ok {
-		t.rows[dnsName] = &planTableRow{}
+	key := newPlanKey(e)
+	if _, ok := t.rows[key]; !ok {
+		t.rows[key] = &planTableRow{}
 	}
-	t.rows[dnsName].current = e
+	t.rows[key].current = e
 }
 
 func (t planTable) addCandidate(e *endpoint.Endpoint) {
-	dnsName := sanitizeDNSName(e.DNSName)
-	if _, ok := t.rows[dnsName]; !ok {
-		t.rows[dnsName] = &planTableRow{}
+	key := newPlanKey(e)
+	if _, ok := t.rows[key]; !ok {
+		t.rows[key] = &planTableRow{}
+	}
+	t.rows[key].candidates = append(t.rows[key].candidates, e)
+}
+
+// resolveCNAMEConflicts enforces the CNAME exclusivity of RFC 1034: a CNAME record
+// cannot share its dnsName with any other record. Whenever a dnsName has CNAME and
+// non-CNAME candidates, the ConflictResolver picks a winner among all of them and the
+// candidates of the losing record type(s) are dropped. Current records of the losing
+// type(s) are thereby left without candidates and get deleted.
+func (t planTable) resolveCNAMEConflicts() {
+	byName := map[string][]planKey{}
+	for key := range t.rows {
+		byName[key.dnsName] = append(byName[key.dnsName], key)
+	}
+
+	for _, keys := range byName {
+		// sort for consistency, so that the same current record is used on every run
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].recordType < keys[j].recordType
+		})
+
+		var current *endpoint.Endpoint
+		var candidates []*endpoint.Endpoint
+		hasCNAME, hasOther := false, false
+		for _, key := range keys {
+			row := t.rows[key]
+			if len(row.candidates) == 0 {
+				continue
+			}
+			if key.recordType == endpoint.RecordTypeCNAME {
+				hasCNAME = true
+			} else {
+				hasOther = true
+			}
+			candidates = append(candidates, row.candidates...)
+		}
+		if !hasCNAME || !hasOther {
+			continue
+		}
+
+		for _, key := range keys {
+			if row := t.rows[key]; row.current != nil && (current == nil || key.recordType == endpoint.RecordTypeCNAME) {
+				current = row.current
+			}
+		}
+
+		var winner *endpoint.Endpoint
+		if current != nil {
+			winner = t.resolver.ResolveUpdate(current, candidates)
+		} else {
+			winner = t.resolver.ResolveCreate(candidates)
+		}
+
+		for _, key := range keys {
+			if (key.recordType == endpoint.RecordTypeCNAME) != (winner.RecordType == endpoint.RecordTypeCNAME) {
+				t.rows[key].candidates = nil
+			}
+		}
 	}
-	t.rows[dnsName].candidates = append(t.rows[dnsName].candidates, e)
 }
 
-// TODO: allows record type change, which might not be supported by all dns providers
 func (t planTable) getUpdates() (updateNew []*endpoint.Endpoint, updateOld []*endpoint.Endpoint) {
 	for _, row := range t.rows {
 		if row.current != nil && len(row.candidates) > 0 { //dns name is taken
 			update := t.resolver.ResolveUpdate(row.current, row.candidates)
 			// compare "update" to "current" to figure out if actual update is required
-			if shouldUpdateTTL(update, row.current) || targetChanged(update, row.current) {
+			if shouldUpdateTTL(update, row.current) || targetChanged(update, row.current) ||
+				providerSpecificChanged(update, row.current, t.providerSpecificKeys) || labelsChanged(update, row.current, t.labelKeys) {
 				inheritOwner(row.current, update)
 				updateNew = append(updateNew, update)
 				updateOld = append(updateOld, row.current)
@@ -117,8 +234,8 @@ func (t planTable) getUpdates() (updateNew []*endpoint.Endpoint, updateOld []*en
 }
 
 func (t planTable) getCreates() (createList []*endpoint.Endpoint) {
-	for _, row := range t.rows {
-		if row.current == nil { //dns name not taken
+	for key, row := range t.rows {
+		if row.current == nil && len(row.candidates) > 0 && !t.replaced[key.dnsName] { //dns name and record type not taken
 			createList = append(createList, t.resolver.ResolveCreate(row.candidates))
 		}
 	}
@@ -126,19 +243,60 @@ func (t planTable) getCreates() (createList []*endpoint.Endpoint) {
 }
 
 func (t planTable) getDeletes() (deleteList []*endpoint.Endpoint) {
-	for _, row := range t.rows {
-		if row.current != nil && len(row.candidates) == 0 {
+	for key, row := range t.rows {
+		if row.current != nil && len(row.candidates) == 0 && !t.replaced[key.dnsName] {
 			deleteList = append(deleteList, row.current)
 		}
 	}
 	return
 }
 
+// markReplacements finds the dnsNames on which records have to be deleted before other records
+// can be created, because either of them is a CNAME, e.g. when a Service moves from an IP-based to
+// a hostname-based load balancer. Creations and deletions on these names are turned into replacements.
+func (t planTable) markReplacements() {
+	creates, deletes, cnames := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for key, row := range t.rows {
+		switch {
+		case row.current == nil && len(row.candidates) > 0:
+			creates[key.dnsName] = true
+		case row.current != nil && len(row.candidates) == 0:
+			deletes[key.dnsName] = true
+		default:
+			continue
+		}
+		if key.recordType == endpoint.RecordTypeCNAME {
+			cnames[key.dnsName] = true
+		}
+	}
+
+	for dnsName := range cnames {
+		if creates[dnsName] && deletes[dnsName] {
+			t.replaced[dnsName] = true
+		}
+	}
+}
+
+func (t planTable) getReplacements() (replaceNew []*endpoint.Endpoint, replaceOld []*endpoint.Endpoint) {
+	for key, row := range t.rows {
+		if !t.replaced[key.dnsName] {
+			continue
+		}
+		if row.current == nil && len(row.candidates) > 0 {
+			replaceNew = append(replaceNew, t.resolver.ResolveCreate(row.candidates))
+		}
+		if row.current != nil && len(row.candidates) == 0 {
+			replaceOld = append(replaceOld, row.current)
+		}
+	}
+	return
+}
+
 // Calculate computes the actions needed to move current state towards desired
 // state. It then passes those changes to the current policy for further
 // processing. It returns a copy of Plan with the changes populated.
 func (p *Plan) Calculate() *Plan {
-	t := newPlanTable()
+	t := newPlanTable(p.Resolver, p.ProviderSpecificKeys, p.LabelKeys)
 
 	for _, current := range filterRecordsForPlan(p.Current) {
 		t.addCurrent(current)
@@ -146,24 +304,55 @@ func (p *Plan) Calculate() *Plan {
 	for _, desired := range filterRecordsForPlan(p.Desired) {
 		t.addCandidate(desired)
 	}
+	t.resolveCNAMEConflicts()
+	t.markReplacements()
 
 	changes := &Changes{}
 	changes.Create = t.getCreates()
-	changes.Delete = t.getDeletes()
+	changes.Delete = append(withoutProtected(t.getDeletes(), p.Protected), p.Orphans...)
 	changes.UpdateNew, changes.UpdateOld = t.getUpdates()
+	changes.ReplaceNew, changes.ReplaceOld = t.getReplacements()
 	for _, pol := range p.Policies {
+		if cp, ok := pol.(currentRecordsPolicy); ok {
+			changes = cp.applyWithCurrent(p.Current, changes)
+			continue
+		}
 		changes = pol.Apply(changes)
 	}
 
 	plan := &Plan{
-		Current: p.Current,
-		Desired: p.Desired,
-		Changes: changes,
+		Current:              p.Current,
+		Desired:              p.Desired,
+		Orphans:              p.Orphans,
+		Protected:            p.Protected,
+		Resolver:             p.Resolver,
+		ProviderSpecificKeys: p.ProviderSpecificKeys,
+		LabelKeys:            p.LabelKeys,
+		Changes:              changes,
 	}
 
 	return plan
 }
 
+// withoutProtected returns the deletions without the ones of records with the name and type of
+// a protected record
+func withoutProtected(deletes, protected []*endpoint.Endpoint) []*endpoint.Endpoint {
+	if len(protected) == 0 {
+		return deletes
+	}
+	keys := map[planKey]bool{}
+	for _, ep := range protected {
+		keys[newPlanKey(ep)] = true
+	}
+	filtered := []*endpoint.Endpoint{}
+	for _, ep := range deletes {
+		if !keys[newPlanKey(ep)] {
+			filtered = append(filtered, ep)
+		}
+	}
+	return filtered
+}
+
 func inheritOwner(from, to *endpoint.Endpoint) {
 	if to.Labels == nil {
 		to.Labels = map[string]string{}
@@ -175,7 +364,33 @@ func inheritOwner(from, to *endpoint.Endpoint) {
 }
 
 func targetChanged(desired, current *endpoint.Endpoint) bool {
-	return !desired.Targets.Same(current.Targets)
+	return !endpoint.NewTargetSet(desired.RecordType, desired.Targets).Equal(endpoint.NewTargetSet(current.RecordType, current.Targets))
+}
+
+// providerSpecificChanged compares the given provider specific properties, missing ones are considered empty
+func providerSpecificChanged(desired, current *endpoint.Endpoint, keys []string) bool {
+	for _, key := range keys {
+		if desired.ProviderSpecific[key] != current.ProviderSpecific[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// labelsChanged compares the given labels. Labels missing from the current record are skipped, as records
+// written by earlier versions lack the labels introduced since, e.g. the creation timestamp, and shouldn't
+// all be updated at once. They get the labels with their next update.
+func labelsChanged(desired, current *endpoint.Endpoint, keys []string) bool {
+	for _, key := range keys {
+		value, ok := current.Labels[key]
+		if !ok {
+			continue
+		}
+		if desired.Labels[key] != value {
+			return true
+		}
+	}
+	return false
 }
 
 func shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
@@ -190,16 +405,15 @@ func shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
 // deleted erroneously by the planner (only the TXT registry should do this.)
 //
 // Per RFC 1034, CNAME records conflict with all other records - it is the
-// only record with this property. The behavior of the planner may need to be
-// made more sophisticated to codify this.
+// only record with this property. This is taken care of by resolveCNAMEConflicts.
 func filterRecordsForPlan(records []*endpoint.Endpoint) []*endpoint.Endpoint {
 	filtered := []*endpoint.Endpoint{}
 
 	for _, record := range records {
 		// Explicitly specify which records we want to use for planning.
-		// TODO: Add AAAA records as well when they are supported.
 		switch record.RecordType {
-		case endpoint.RecordTypeA, endpoint.RecordTypeCNAME:
+		case endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeSRV,
+			endpoint.RecordTypeMX, endpoint.RecordTypeNS, endpoint.RecordTypeCAA, endpoint.RecordTypePTR:
 			filtered = append(filtered, record)
 		default:
 			continue
@@ -209,8 +423,15 @@ func filterRecordsForPlan(records []*endpoint.Endpoint) []*endpoint.Endpoint {
 	return filtered
 }
 
+func newPlanKey(e *endpoint.Endpoint) planKey {
+	return planKey{
+		dnsName:    sanitizeDNSName(e.DNSName),
+		recordType: e.RecordType,
+	}
+}
+
 // sanitizeDNSName checks if the DNS name is correct
-// for now it only removes space and lower case
+// for now it removes space, converts internationalized names to punycode and lower cases
 func sanitizeDNSName(dnsName string) string {
-	return strings.TrimSpace(strings.ToLower(dnsName))
+	return strings.ToLower(endpoint.NormalizeDNSName(strings.TrimSpace(dnsName)))
 }
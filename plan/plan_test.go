@@ -36,6 +36,10 @@ type PlanTestSuite struct {
 	bar127A                *endpoint.Endpoint
 	bar127AWithTTL         *endpoint.Endpoint
 	bar192A                *endpoint.Endpoint
+	bar2001AAAA            *endpoint.Endpoint
+	barSRV                 *endpoint.Endpoint
+	barCname               *endpoint.Endpoint
+	baz2001AAAA            *endpoint.Endpoint
 }
 
 func (suite *PlanTestSuite) SetupTest() {
@@ -108,6 +112,38 @@ func (suite *PlanTestSuite) SetupTest() {
 			endpoint.ResourceLabelKey: "ingress/default/bar-192",
 		},
 	}
+	suite.bar2001AAAA = &endpoint.Endpoint{
+		DNSName:    "bar",
+		Targets:    endpoint.Targets{"2001:db8::1"},
+		RecordType: "AAAA",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/default/bar-127",
+		},
+	}
+	suite.barSRV = &endpoint.Endpoint{
+		DNSName:    "bar",
+		Targets:    endpoint.Targets{"0 50 30000 bar"},
+		RecordType: "SRV",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "service/default/bar",
+		},
+	}
+	suite.barCname = &endpoint.Endpoint{
+		DNSName:    "bar",
+		Targets:    endpoint.Targets{"elb.com"},
+		RecordType: "CNAME",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/default/bar-127",
+		},
+	}
+	suite.baz2001AAAA = &endpoint.Endpoint{
+		DNSName:    "baz",
+		Targets:    endpoint.Targets{"2001:db8::1"},
+		RecordType: "AAAA",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/default/baz-2001",
+		},
+	}
 }
 
 func (suite *PlanTestSuite) TestSyncFirstRound() {
@@ -250,9 +286,11 @@ func (suite *PlanTestSuite) TestDifferentTypes() {
 	current := []*endpoint.Endpoint{suite.fooV1Cname}
 	desired := []*endpoint.Endpoint{suite.fooV2Cname, suite.fooA5}
 	expectedCreate := []*endpoint.Endpoint{}
-	expectedUpdateOld := []*endpoint.Endpoint{suite.fooV1Cname}
-	expectedUpdateNew := []*endpoint.Endpoint{suite.fooA5}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
 	expectedDelete := []*endpoint.Endpoint{}
+	expectedReplaceOld := []*endpoint.Endpoint{suite.fooV1Cname}
+	expectedReplaceNew := []*endpoint.Endpoint{suite.fooA5}
 
 	p := &Plan{
 		Policies: []Policy{&SyncPolicy{}},
@@ -265,6 +303,58 @@ func (suite *PlanTestSuite) TestDifferentTypes() {
 	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
 	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
+	validateEntries(suite.T(), changes.ReplaceOld, expectedReplaceOld)
+	validateEntries(suite.T(), changes.ReplaceNew, expectedReplaceNew)
+}
+
+func (suite *PlanTestSuite) TestTypeChangeFromAToCNAME() {
+	current := []*endpoint.Endpoint{suite.bar127A, suite.bar2001AAAA, suite.fooV1Cname}
+	desired := []*endpoint.Endpoint{suite.barCname, suite.fooV1Cname}
+	expectedCreate := []*endpoint.Endpoint{}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+	expectedReplaceOld := []*endpoint.Endpoint{suite.bar127A, suite.bar2001AAAA}
+	expectedReplaceNew := []*endpoint.Endpoint{suite.barCname}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+	validateEntries(suite.T(), changes.ReplaceOld, expectedReplaceOld)
+	validateEntries(suite.T(), changes.ReplaceNew, expectedReplaceNew)
+}
+
+func (suite *PlanTestSuite) TestTypeChangeWithoutCNAME() {
+	current := []*endpoint.Endpoint{suite.bar127A}
+	desired := []*endpoint.Endpoint{suite.bar2001AAAA}
+	expectedCreate := []*endpoint.Endpoint{suite.bar2001AAAA}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{suite.bar127A}
+	expectedReplaceOld := []*endpoint.Endpoint{}
+	expectedReplaceNew := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+	validateEntries(suite.T(), changes.ReplaceOld, expectedReplaceOld)
+	validateEntries(suite.T(), changes.ReplaceNew, expectedReplaceNew)
 }
 
 func (suite *PlanTestSuite) TestIgnoreTXT() {
@@ -288,6 +378,191 @@ func (suite *PlanTestSuite) TestIgnoreTXT() {
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
 }
 
+func (suite *PlanTestSuite) TestAAAARecords() {
+	current := []*endpoint.Endpoint{suite.bar192A}
+	desired := []*endpoint.Endpoint{suite.bar192A, suite.baz2001AAAA}
+	expectedCreate := []*endpoint.Endpoint{suite.baz2001AAAA}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestExtendedRecordTypes() {
+	currentMX := endpoint.NewEndpoint("example.org", endpoint.RecordTypeMX, "10 mail.example.org")
+	desiredMX := endpoint.NewEndpoint("example.org", endpoint.RecordTypeMX, "10 mail.example.org", "20 backup.example.org")
+	desiredCAA := endpoint.NewEndpoint("example.org", endpoint.RecordTypeCAA, "0 issue \"letsencrypt.org\"")
+	desiredNS := endpoint.NewEndpoint("sub.example.org", endpoint.RecordTypeNS, "ns1.example.net", "ns2.example.net")
+	currentPTR := endpoint.NewEndpoint("4.3.2.1.in-addr.arpa", endpoint.RecordTypePTR, "host.example.org")
+
+	current := []*endpoint.Endpoint{currentMX, currentPTR}
+	desired := []*endpoint.Endpoint{desiredMX, desiredCAA, desiredNS}
+	expectedCreate := []*endpoint.Endpoint{desiredCAA, desiredNS}
+	expectedUpdateOld := []*endpoint.Endpoint{currentMX}
+	expectedUpdateNew := []*endpoint.Endpoint{desiredMX}
+	expectedDelete := []*endpoint.Endpoint{currentPTR}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestInternationalizedNames() {
+	current := []*endpoint.Endpoint{
+		{DNSName: "xn--bcher-kva.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4"}},
+	}
+	desired := []*endpoint.Endpoint{
+		{DNSName: "bücher.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4"}},
+	}
+	expectedCreate := []*endpoint.Endpoint{}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestEquivalentTargets() {
+	current := []*endpoint.Endpoint{
+		{DNSName: "foo", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"LB.example.org."}},
+		{DNSName: "bar", RecordType: endpoint.RecordTypeAAAA, Targets: endpoint.Targets{"2001:db8::2", "2001:0db8:0000::0001"}},
+	}
+	desired := []*endpoint.Endpoint{
+		{DNSName: "foo", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.example.org"}},
+		{DNSName: "bar", RecordType: endpoint.RecordTypeAAAA, Targets: endpoint.Targets{"2001:db8::1", "2001:db8::2"}},
+	}
+	expectedCreate := []*endpoint.Endpoint{}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+	suite.Equal(endpoint.Targets{"2001:db8::1", "2001:db8::2"}, desired[1].Targets)
+}
+
+func (suite *PlanTestSuite) TestMultipleTypesSameName() {
+	current := []*endpoint.Endpoint{suite.bar127A}
+	desired := []*endpoint.Endpoint{suite.bar127A, suite.bar2001AAAA, suite.barSRV}
+	expectedCreate := []*endpoint.Endpoint{suite.bar2001AAAA, suite.barSRV}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestMultipleTypesSameNameUpdateAndDelete() {
+	current := []*endpoint.Endpoint{suite.bar127A, suite.bar2001AAAA}
+	desired := []*endpoint.Endpoint{suite.bar127AWithTTL}
+	expectedCreate := []*endpoint.Endpoint{}
+	expectedUpdateOld := []*endpoint.Endpoint{suite.bar127A}
+	expectedUpdateNew := []*endpoint.Endpoint{suite.bar127AWithTTL}
+	expectedDelete := []*endpoint.Endpoint{suite.bar2001AAAA}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestCNAMEExclusivityOnCreate() {
+	current := []*endpoint.Endpoint{}
+	desired := []*endpoint.Endpoint{suite.fooV1Cname, suite.fooA5}
+	expectedCreate := []*endpoint.Endpoint{suite.fooA5} //A is chosen because of resolver taking "min"
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestCNAMEExclusivityKeepsCurrentResource() {
+	current := []*endpoint.Endpoint{suite.fooV1Cname}
+	desired := []*endpoint.Endpoint{suite.fooV1Cname, suite.fooA5}
+	expectedCreate := []*endpoint.Endpoint{}
+	expectedUpdateOld := []*endpoint.Endpoint{}
+	expectedUpdateNew := []*endpoint.Endpoint{}
+	expectedDelete := []*endpoint.Endpoint{}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+	validateEntries(suite.T(), changes.UpdateNew, expectedUpdateNew)
+	validateEntries(suite.T(), changes.UpdateOld, expectedUpdateOld)
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
 func (suite *PlanTestSuite) TestRemoveEndpoint() {
 	current := []*endpoint.Endpoint{suite.fooV1Cname, suite.bar192A}
 	desired := []*endpoint.Endpoint{suite.fooV1Cname}
@@ -330,6 +605,26 @@ func (suite *PlanTestSuite) TestRemoveEndpointWithUpsert() {
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
 }
 
+func (suite *PlanTestSuite) TestOrphans() {
+	orphan := endpoint.NewEndpoint("txt.orphan", endpoint.RecordTypeTXT, "\"heritage=external-dns,external-dns/owner=owner\"")
+	current := []*endpoint.Endpoint{suite.fooV1Cname, suite.bar192A}
+	desired := []*endpoint.Endpoint{suite.fooV1Cname}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+		Orphans:  []*endpoint.Endpoint{orphan},
+	}
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{suite.bar192A, orphan})
+
+	p.Policies = []Policy{&UpsertOnlyPolicy{}}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{})
+}
+
 //TODO: remove once multiple-target per endpoint is supported
 func (suite *PlanTestSuite) TestDuplicatedEndpointsForSameResourceReplace() {
 	current := []*endpoint.Endpoint{suite.fooV3CnameSameResource, suite.bar192A}
@@ -374,6 +669,149 @@ func (suite *PlanTestSuite) TestDuplicatedEndpointsForSameResourceRetain() {
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
 }
 
+func (suite *PlanTestSuite) TestProviderSpecificChange() {
+	current := []*endpoint.Endpoint{suite.fooV1Cname}
+	fooV1CnameAlias := &endpoint.Endpoint{
+		DNSName:          suite.fooV1Cname.DNSName,
+		Targets:          suite.fooV1Cname.Targets,
+		RecordType:       suite.fooV1Cname.RecordType,
+		Labels:           map[string]string{endpoint.ResourceLabelKey: "ingress/default/foo-v1"},
+		ProviderSpecific: endpoint.ProviderSpecific{"alias": "true", "ignored": "true"},
+	}
+	desired := []*endpoint.Endpoint{fooV1CnameAlias}
+
+	// not persisted provider specific properties are ignored
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{})
+
+	p.ProviderSpecificKeys = []string{"alias"}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{fooV1CnameAlias})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{suite.fooV1Cname})
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{})
+
+	// same the other way around
+	p = &Plan{
+		Policies:             []Policy{&SyncPolicy{}},
+		ProviderSpecificKeys: []string{"alias"},
+		Current:              []*endpoint.Endpoint{fooV1CnameAlias},
+		Desired:              []*endpoint.Endpoint{suite.fooV1Cname},
+	}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{suite.fooV1Cname})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{fooV1CnameAlias})
+}
+
+func (suite *PlanTestSuite) TestLabelChange() {
+	fooV1CnameLowPriority := &endpoint.Endpoint{
+		DNSName:    suite.fooV1Cname.DNSName,
+		Targets:    suite.fooV1Cname.Targets,
+		RecordType: suite.fooV1Cname.RecordType,
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey:         "ingress/default/foo-v1",
+			endpoint.OwnerLabelKey:            "pwner",
+			endpoint.ConflictPriorityLabelKey: "5",
+		},
+	}
+	current := []*endpoint.Endpoint{fooV1CnameLowPriority}
+	fooV1CnamePriority := &endpoint.Endpoint{
+		DNSName:    suite.fooV1Cname.DNSName,
+		Targets:    suite.fooV1Cname.Targets,
+		RecordType: suite.fooV1Cname.RecordType,
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey:         "ingress/default/foo-v1",
+			endpoint.ConflictPriorityLabelKey: "10",
+		},
+	}
+	desired := []*endpoint.Endpoint{fooV1CnamePriority}
+
+	// not persisted labels are ignored
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Current:  current,
+		Desired:  desired,
+	}
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{})
+
+	p.LabelKeys = []string{endpoint.ResourceLabelKey, endpoint.ConflictPriorityLabelKey}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{fooV1CnamePriority})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{fooV1CnameLowPriority})
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{})
+	suite.Equal("pwner", fooV1CnamePriority.Labels[endpoint.OwnerLabelKey], "should inherit owner")
+
+	// records written before the label existed aren't updated just to add it
+	p.Current = []*endpoint.Endpoint{suite.fooV1Cname}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{})
+}
+
+func (suite *PlanTestSuite) TestConfiguredResolver() {
+	current := []*endpoint.Endpoint{}
+	desired := []*endpoint.Endpoint{suite.bar127A, suite.bar192A}
+	expectedCreate := []*endpoint.Endpoint{
+		{
+			DNSName:    "bar",
+			Targets:    endpoint.Targets{"127.0.0.1", "192.168.0.1"},
+			RecordType: "A",
+			Labels: map[string]string{
+				endpoint.ResourceLabelKey: "ingress/default/bar-127",
+			},
+		},
+	}
+
+	p := &Plan{
+		Policies: []Policy{&SyncPolicy{}},
+		Resolver: MultiCluster{},
+		Current:  current,
+		Desired:  desired,
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, expectedCreate)
+}
+
+func TestChangesStages(t *testing.T) {
+	create := []*endpoint.Endpoint{endpoint.NewEndpoint("foo", endpoint.RecordTypeA, "1.2.3.4")}
+	updateOld := []*endpoint.Endpoint{endpoint.NewEndpoint("bar", endpoint.RecordTypeA, "1.2.3.4")}
+	updateNew := []*endpoint.Endpoint{endpoint.NewEndpoint("bar", endpoint.RecordTypeA, "5.6.7.8")}
+	del := []*endpoint.Endpoint{endpoint.NewEndpoint("baz", endpoint.RecordTypeA, "1.2.3.4")}
+	replaceOld := []*endpoint.Endpoint{endpoint.NewEndpoint("qux", endpoint.RecordTypeA, "1.2.3.4")}
+	replaceNew := []*endpoint.Endpoint{endpoint.NewEndpoint("qux", endpoint.RecordTypeCNAME, "elb.com")}
+
+	changes := &Changes{Create: create, UpdateOld: updateOld, UpdateNew: updateNew, Delete: del}
+	stages := changes.Stages()
+	assert.Len(t, stages, 1)
+	assert.Equal(t, changes, stages[0])
+
+	changes.ReplaceOld = replaceOld
+	changes.ReplaceNew = replaceNew
+	stages = changes.Stages()
+	assert.Len(t, stages, 2)
+
+	validateEntries(t, stages[0].Create, []*endpoint.Endpoint{})
+	validateEntries(t, stages[0].UpdateOld, []*endpoint.Endpoint{})
+	validateEntries(t, stages[0].UpdateNew, []*endpoint.Endpoint{})
+	validateEntries(t, stages[0].Delete, replaceOld)
+
+	validateEntries(t, stages[1].Create, append(append([]*endpoint.Endpoint{}, create...), replaceNew...))
+	validateEntries(t, stages[1].UpdateOld, updateOld)
+	validateEntries(t, stages[1].UpdateNew, updateNew)
+	validateEntries(t, stages[1].Delete, del)
+	assert.Len(t, changes.Create, 1, "Stages must not modify the original changes")
+}
+
 func TestPlan(t *testing.T) {
 	suite.Run(t, new(PlanTestSuite))
 }
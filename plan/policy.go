@@ -21,6 +21,23 @@ type Policy interface {
 	Apply(changes *Changes) *Changes
 }
 
+// StatefulPolicy is a policy which keeps state across reconcile cycles, e.g. to delay changes.
+type StatefulPolicy interface {
+	Policy
+	// Copy returns a copy of the policy and its state. Applying the copy leaves the policy untouched
+	// and doesn't update any metrics.
+	Copy() Policy
+}
+
+// CopyPolicy returns a policy which can be applied without changing the state of policy,
+// e.g. to calculate a plan which won't be applied. Stateless policies are returned as is.
+func CopyPolicy(policy Policy) Policy {
+	if stateful, ok := policy.(StatefulPolicy); ok {
+		return stateful.Copy()
+	}
+	return policy
+}
+
 // Policies is a registry of available policies.
 var Policies = map[string]Policy{
 	"sync":        &SyncPolicy{},
@@ -39,6 +56,8 @@ func (p *SyncPolicy) Apply(changes *Changes) *Changes {
 type UpsertOnlyPolicy struct{}
 
 // Apply applies the upsert-only policy which strips out any deletions.
+// Replacements are stripped out as well, as they delete the record of the old type before the
+// record of the new type is created, and the new record would conflict with the old one.
 func (p *UpsertOnlyPolicy) Apply(changes *Changes) *Changes {
 	return &Changes{
 		Create:    changes.Create,
@@ -20,6 +20,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 )
 
@@ -34,6 +36,9 @@ func TestApply(t *testing.T) {
 	// another two simple entries
 	bar := []*endpoint.Endpoint{{DNSName: "bar", Targets: endpoint.Targets{"v1"}}}
 	baz := []*endpoint.Endpoint{{DNSName: "baz", Targets: endpoint.Targets{"v1"}}}
+	// an entry changing its type
+	quxA := []*endpoint.Endpoint{{DNSName: "qux", Targets: endpoint.Targets{"1.2.3.4"}, RecordType: endpoint.RecordTypeA}}
+	quxCNAME := []*endpoint.Endpoint{{DNSName: "qux", Targets: endpoint.Targets{"v1"}, RecordType: endpoint.RecordTypeCNAME}}
 
 	for _, tc := range []struct {
 		policy   Policy
@@ -52,6 +57,12 @@ func TestApply(t *testing.T) {
 			&Changes{Create: baz, UpdateOld: fooV1, UpdateNew: fooV2, Delete: bar},
 			&Changes{Create: baz, UpdateOld: fooV1, UpdateNew: fooV2, Delete: empty},
 		},
+		{
+			// UpsertOnlyPolicy drops replacements of records by records of a different type, as they delete the old ones.
+			&UpsertOnlyPolicy{},
+			&Changes{Create: baz, Delete: bar, ReplaceOld: quxA, ReplaceNew: quxCNAME},
+			&Changes{Create: baz, Delete: empty, ReplaceOld: empty, ReplaceNew: empty},
+		},
 	} {
 		// apply policy
 		changes := tc.policy.Apply(tc.changes)
@@ -61,7 +72,31 @@ func TestApply(t *testing.T) {
 		validateEntries(t, changes.UpdateOld, tc.expected.UpdateOld)
 		validateEntries(t, changes.UpdateNew, tc.expected.UpdateNew)
 		validateEntries(t, changes.Delete, tc.expected.Delete)
+		validateEntries(t, changes.ReplaceOld, tc.expected.ReplaceOld)
+		validateEntries(t, changes.ReplaceNew, tc.expected.ReplaceNew)
+	}
+}
+
+// TestUpsertOnlyNeverDeletes tests that plans calculated with the UpsertOnlyPolicy never delete a record.
+func TestUpsertOnlyNeverDeletes(t *testing.T) {
+	current := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("gone.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("changed.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("retyped.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("retyped.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+	}
+	desired := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("changed.example.org", endpoint.RecordTypeA, "5.6.7.8"),
+		endpoint.NewEndpoint("retyped.example.org", endpoint.RecordTypeCNAME, "lb.example.org"),
+		endpoint.NewEndpoint("new.example.org", endpoint.RecordTypeA, "1.2.3.4"),
 	}
+
+	changes := calculateWithPolicy(&UpsertOnlyPolicy{}, current, desired)
+	assert.Empty(t, changes.Delete)
+	assert.Empty(t, changes.ReplaceOld)
+	assert.Empty(t, changes.ReplaceNew)
+	validateEntries(t, changes.Create, []*endpoint.Endpoint{desired[2]})
+	validateEntries(t, changes.UpdateNew, []*endpoint.Endpoint{desired[0]})
 }
 
 // TestPolicies tests that policies are correctly registered.
@@ -79,3 +114,31 @@ func validatePolicy(t *testing.T, policy, expected Policy) {
 		t.Errorf("expected %q to match %q", policyType, expectedType)
 	}
 }
+
+// TestCopyPolicy tests that applying a copy of a policy leaves the state of the policy untouched.
+func TestCopyPolicy(t *testing.T) {
+	stateless := &SyncPolicy{}
+	assert.Equal(t, stateless, CopyPolicy(stateless), "stateless policies should be returned as is")
+
+	current := ownedRecords("default", 2)
+	threshold := &DeletionThresholdPolicy{Policy: &SyncPolicy{}, MaxDeletions: 1, OwnerID: "default"}
+	delayed := &DelayedDeletionPolicy{Policy: threshold, GraceCycles: 2}
+
+	changes := calculateWithPolicy(CopyPolicy(delayed), current, []*endpoint.Endpoint{})
+	assert.Equal(t, &Changes{}, changes)
+	assert.Nil(t, threshold.Blocked(), "the copy shouldn't block changes of the policy")
+	assert.Empty(t, delayed.tombstones, "the copy shouldn't add tombstones to the policy")
+
+	// deletions below the threshold are delayed, the copy previews the tombstones
+	current = current[:1]
+	changes = calculateWithPolicy(CopyPolicy(delayed), current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.UpdateNew, 1)
+	assert.Empty(t, delayed.tombstones, "the copy shouldn't add tombstones to the policy")
+
+	changes = calculateWithPolicy(delayed, current, []*endpoint.Endpoint{})
+	assert.Len(t, changes.UpdateNew, 1)
+	assert.Len(t, delayed.tombstones, 1)
+
+	copied := CopyPolicy(delayed).(*DelayedDeletionPolicy)
+	assert.Equal(t, delayed.tombstones, copied.tombstones, "the copy should start from the state of the policy")
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/pvtz"
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -244,7 +245,7 @@ func newTestAlibabaCloudProvider(private bool) *AlibabaCloudProvider {
 
 func TestAlibabaCloudPrivateProvider_Records(t *testing.T) {
 	p := newTestAlibabaCloudProvider(true)
-	endpoints, err := p.Records()
+	endpoints, err := p.Records(context.Background())
 	if err != nil {
 		t.Errorf("Failed to get records: %v", err)
 	} else {
@@ -259,7 +260,7 @@ func TestAlibabaCloudPrivateProvider_Records(t *testing.T) {
 
 func TestAlibabaCloudProvider_Records(t *testing.T) {
 	p := newTestAlibabaCloudProvider(false)
-	endpoints, err := p.Records()
+	endpoints, err := p.Records(context.Background())
 	if err != nil {
 		t.Errorf("Failed to get records: %v", err)
 	} else {
@@ -300,8 +301,8 @@ func TestAlibabaCloudProvider_ApplyChanges(t *testing.T) {
 			},
 		},
 	}
-	p.ApplyChanges(&changes)
-	endpoints, err := p.Records()
+	p.ApplyChanges(context.Background(), &changes)
+	endpoints, err := p.Records(context.Background())
 	if err != nil {
 		t.Errorf("Failed to get records: %v", err)
 	} else {
@@ -316,7 +317,7 @@ func TestAlibabaCloudProvider_ApplyChanges(t *testing.T) {
 
 func TestAlibabaCloudProvider_Records_PrivateZone(t *testing.T) {
 	p := newTestAlibabaCloudProvider(true)
-	endpoints, err := p.Records()
+	endpoints, err := p.Records(context.Background())
 	if err != nil {
 		t.Errorf("Failed to get records: %v", err)
 	} else {
@@ -357,8 +358,8 @@ func TestAlibabaCloudProvider_ApplyChanges_PrivateZone(t *testing.T) {
 			},
 		},
 	}
-	p.ApplyChanges(&changes)
-	endpoints, err := p.Records()
+	p.ApplyChanges(context.Background(), &changes)
+	endpoints, err := p.Records(context.Background())
 	if err != nil {
 		t.Errorf("Failed to get records: %v", err)
 	} else {
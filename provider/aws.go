@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -37,6 +39,9 @@ const (
 	// provider specific key that designates whether an AWS ALIAS record has the EvaluateTargetHealth
 	// field set to true.
 	providerSpecificEvaluateTargetHealth = "aws/evaluate-target-health"
+	// provider specific key that designates whether a CNAME record should be created as an AWS ALIAS record
+	// pointing to another record of the same hosted zone.
+	providerSpecificAlias = "alias"
 )
 
 var (
@@ -81,11 +86,11 @@ var (
 // Route53API is the subset of the AWS Route53 API that we actually use.  Add methods as required. Signatures must match exactly.
 // mostly taken from: https://github.com/kubernetes/kubernetes/blob/853167624edb6bc0cfdcdfb88e746e178f5db36c/federation/pkg/dnsprovider/providers/aws/route53/stubs/route53api.go
 type Route53API interface {
-	ListResourceRecordSetsPages(input *route53.ListResourceRecordSetsInput, fn func(resp *route53.ListResourceRecordSetsOutput, lastPage bool) (shouldContinue bool)) error
-	ChangeResourceRecordSets(*route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error)
-	CreateHostedZone(*route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error)
-	ListHostedZonesPages(input *route53.ListHostedZonesInput, fn func(resp *route53.ListHostedZonesOutput, lastPage bool) (shouldContinue bool)) error
-	ListTagsForResource(input *route53.ListTagsForResourceInput) (*route53.ListTagsForResourceOutput, error)
+	ListResourceRecordSetsPagesWithContext(ctx context.Context, input *route53.ListResourceRecordSetsInput, fn func(resp *route53.ListResourceRecordSetsOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error
+	ChangeResourceRecordSetsWithContext(ctx context.Context, input *route53.ChangeResourceRecordSetsInput, opts ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error)
+	CreateHostedZoneWithContext(ctx context.Context, input *route53.CreateHostedZoneInput, opts ...request.Option) (*route53.CreateHostedZoneOutput, error)
+	ListHostedZonesPagesWithContext(ctx context.Context, input *route53.ListHostedZonesInput, fn func(resp *route53.ListHostedZonesOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error
+	ListTagsForResourceWithContext(ctx context.Context, input *route53.ListTagsForResourceInput, opts ...request.Option) (*route53.ListTagsForResourceOutput, error)
 }
 
 // AWSProvider is an implementation of Provider for AWS Route53.
@@ -160,7 +165,7 @@ func NewAWSProvider(awsConfig AWSConfig) (*AWSProvider, error) {
 }
 
 // Zones returns the list of hosted zones.
-func (p *AWSProvider) Zones() (map[string]*route53.HostedZone, error) {
+func (p *AWSProvider) Zones(ctx context.Context) (map[string]*route53.HostedZone, error) {
 	zones := make(map[string]*route53.HostedZone)
 
 	var tagErr error
@@ -180,7 +185,7 @@ func (p *AWSProvider) Zones() (map[string]*route53.HostedZone, error) {
 
 			// Only fetch tags if a tag filter was specified
 			if !p.zoneTagFilter.IsEmpty() {
-				tags, err := p.tagsForZone(*zone.Id)
+				tags, err := p.tagsForZone(ctx, *zone.Id)
 				if err != nil {
 					tagErr = err
 					return false
@@ -196,7 +201,7 @@ func (p *AWSProvider) Zones() (map[string]*route53.HostedZone, error) {
 		return true
 	}
 
-	err := p.client.ListHostedZonesPages(&route53.ListHostedZonesInput{}, f)
+	err := p.client.ListHostedZonesPagesWithContext(ctx, &route53.ListHostedZonesInput{}, f)
 	if err != nil {
 		return nil, err
 	}
@@ -211,6 +216,19 @@ func (p *AWSProvider) Zones() (map[string]*route53.HostedZone, error) {
 	return zones, nil
 }
 
+// ZoneNames returns the names of the hosted zones, see ZoneNamesLister
+func (p *AWSProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, aws.StringValue(zone.Name))
+	}
+	return names, nil
+}
+
 // wildcardUnescape converts \\052.abc back to *.abc
 // Route53 stores wildcards escaped: http://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DomainNameFormat.html?shortFooter=true#domain-name-format-asterisk
 func wildcardUnescape(s string) string {
@@ -221,18 +239,19 @@ func wildcardUnescape(s string) string {
 }
 
 // Records returns the list of records in a given hosted zone.
-func (p *AWSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
-	zones, err := p.Zones()
+func (p *AWSProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var zoneName string
 	f := func(resp *route53.ListResourceRecordSetsOutput, lastPage bool) (shouldContinue bool) {
 		for _, r := range resp.ResourceRecordSets {
 			// TODO(linki, ownership): Remove once ownership system is in place.
 			// See: https://github.com/kubernetes-incubator/external-dns/pull/122/files/74e2c3d3e237411e619aefc5aab694742001cdec#r109863370
 
-			if !supportedRecordType(aws.StringValue(r.Type)) {
+			if !supportedExtendedRecordType(aws.StringValue(r.Type)) || zoneApexNS(aws.StringValue(r.Type), aws.StringValue(r.Name), zoneName) {
 				continue
 			}
 
@@ -254,6 +273,10 @@ func (p *AWSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 				ep := endpoint.
 					NewEndpointWithTTL(wildcardUnescape(aws.StringValue(r.Name)), endpoint.RecordTypeCNAME, ttl, aws.StringValue(r.AliasTarget.DNSName)).
 					WithProviderSpecific(providerSpecificEvaluateTargetHealth, fmt.Sprintf("%t", aws.BoolValue(r.AliasTarget.EvaluateTargetHealth)))
+				// load balancers are always targeted by ALIAS records, other targets only if requested
+				if canonicalHostedZone(strings.TrimSuffix(aws.StringValue(r.AliasTarget.DNSName), ".")) == "" {
+					ep.WithProviderSpecific(providerSpecificAlias, "true")
+				}
 				endpoints = append(endpoints, ep)
 			}
 		}
@@ -262,11 +285,12 @@ func (p *AWSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 	}
 
 	for _, z := range zones {
+		zoneName = aws.StringValue(z.Name)
 		params := &route53.ListResourceRecordSetsInput{
 			HostedZoneId: z.Id,
 		}
 
-		if err := p.client.ListResourceRecordSetsPages(params, f); err != nil {
+		if err := p.client.ListResourceRecordSetsPagesWithContext(ctx, params, f); err != nil {
 			return nil, err
 		}
 	}
@@ -274,41 +298,78 @@ func (p *AWSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 	return endpoints, nil
 }
 
+// ProviderSpecificKeys returns the provider specific properties which are read back from Route53.
+func (p *AWSProvider) ProviderSpecificKeys() []string {
+	return []string{providerSpecificAlias, providerSpecificEvaluateTargetHealth}
+}
+
+// AdjustEndpoints normalizes the provider specific properties of the endpoints to the ones Records
+// returns: load balancers are always targeted by ALIAS records without the alias property, and only
+// ALIAS records have the evaluate target health property, which defaults to the one of the provider.
+func (p *AWSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	for _, ep := range endpoints {
+		if ep.RecordType != endpoint.RecordTypeCNAME || len(ep.Targets) == 0 {
+			continue
+		}
+		// the properties may be shared with other endpoints of the same resource
+		ep.ProviderSpecific = ep.ProviderSpecific.DeepCopy()
+		alias := ep.ProviderSpecific[providerSpecificAlias] == "true"
+		if isAWSLoadBalancer(ep) || !alias {
+			delete(ep.ProviderSpecific, providerSpecificAlias)
+		}
+		if !isAWSLoadBalancer(ep) && !alias {
+			delete(ep.ProviderSpecific, providerSpecificEvaluateTargetHealth)
+			continue
+		}
+		ep.WithProviderSpecific(providerSpecificEvaluateTargetHealth, fmt.Sprintf("%t", p.evaluateTargetHealthOf(ep)))
+	}
+	return endpoints
+}
+
+// evaluateTargetHealthOf returns whether the ALIAS record of the endpoint evaluates the health of
+// its target, the default of the provider unless the endpoint sets it
+func (p *AWSProvider) evaluateTargetHealthOf(ep *endpoint.Endpoint) bool {
+	if value, ok := ep.ProviderSpecific[providerSpecificEvaluateTargetHealth]; ok {
+		return value == "true"
+	}
+	return p.evaluateTargetHealth
+}
+
 // CreateRecords creates a given set of DNS records in the given hosted zone.
-func (p *AWSProvider) CreateRecords(endpoints []*endpoint.Endpoint) error {
-	return p.submitChanges(p.newChanges(route53.ChangeActionCreate, endpoints))
+func (p *AWSProvider) CreateRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
+	return p.submitChanges(ctx, p.newChanges(ctx, route53.ChangeActionCreate, endpoints))
 }
 
 // UpdateRecords updates a given set of old records to a new set of records in a given hosted zone.
-func (p *AWSProvider) UpdateRecords(endpoints, _ []*endpoint.Endpoint) error {
-	return p.submitChanges(p.newChanges(route53.ChangeActionUpsert, endpoints))
+func (p *AWSProvider) UpdateRecords(ctx context.Context, endpoints, _ []*endpoint.Endpoint) error {
+	return p.submitChanges(ctx, p.newChanges(ctx, route53.ChangeActionUpsert, endpoints))
 }
 
 // DeleteRecords deletes a given set of DNS records in a given zone.
-func (p *AWSProvider) DeleteRecords(endpoints []*endpoint.Endpoint) error {
-	return p.submitChanges(p.newChanges(route53.ChangeActionDelete, endpoints))
+func (p *AWSProvider) DeleteRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
+	return p.submitChanges(ctx, p.newChanges(ctx, route53.ChangeActionDelete, endpoints))
 }
 
 // ApplyChanges applies a given set of changes in a given zone.
-func (p *AWSProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *AWSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	combinedChanges := make([]*route53.Change, 0, len(changes.Create)+len(changes.UpdateNew)+len(changes.Delete))
 
-	combinedChanges = append(combinedChanges, p.newChanges(route53.ChangeActionCreate, changes.Create)...)
-	combinedChanges = append(combinedChanges, p.newChanges(route53.ChangeActionUpsert, changes.UpdateNew)...)
-	combinedChanges = append(combinedChanges, p.newChanges(route53.ChangeActionDelete, changes.Delete)...)
+	combinedChanges = append(combinedChanges, p.newChanges(ctx, route53.ChangeActionCreate, changes.Create)...)
+	combinedChanges = append(combinedChanges, p.newChanges(ctx, route53.ChangeActionUpsert, changes.UpdateNew)...)
+	combinedChanges = append(combinedChanges, p.newChanges(ctx, route53.ChangeActionDelete, changes.Delete)...)
 
-	return p.submitChanges(combinedChanges)
+	return p.submitChanges(ctx, combinedChanges)
 }
 
 // submitChanges takes a zone and a collection of Changes and sends them as a single transaction.
-func (p *AWSProvider) submitChanges(changes []*route53.Change) error {
+func (p *AWSProvider) submitChanges(ctx context.Context, changes []*route53.Change) error {
 	// return early if there is nothing to change
 	if len(changes) == 0 {
 		log.Info("All records are already up to date")
 		return nil
 	}
 
-	zones, err := p.Zones()
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return err
 	}
@@ -338,7 +399,7 @@ func (p *AWSProvider) submitChanges(changes []*route53.Change) error {
 					},
 				}
 
-				if _, err := p.client.ChangeResourceRecordSets(params); err != nil {
+				if _, err := p.client.ChangeResourceRecordSetsWithContext(ctx, params); err != nil {
 					log.Error(err) //TODO(ideahitme): consider changing the interface in cases when this error might be a concern for other components
 					failedUpdate = true
 				} else {
@@ -364,11 +425,11 @@ func (p *AWSProvider) submitChanges(changes []*route53.Change) error {
 }
 
 // newChanges returns a collection of Changes based on the given records and action.
-func (p *AWSProvider) newChanges(action string, endpoints []*endpoint.Endpoint) []*route53.Change {
+func (p *AWSProvider) newChanges(ctx context.Context, action string, endpoints []*endpoint.Endpoint) []*route53.Change {
 	changes := make([]*route53.Change, 0, len(endpoints))
 
 	for _, endpoint := range endpoints {
-		changes = append(changes, p.newChange(action, endpoint))
+		changes = append(changes, p.newChange(ctx, action, endpoint))
 	}
 
 	return changes
@@ -377,7 +438,7 @@ func (p *AWSProvider) newChanges(action string, endpoints []*endpoint.Endpoint)
 // newChange returns a Change of the given record by the given action, e.g.
 // action=ChangeActionCreate returns a change for creation of the record and
 // action=ChangeActionDelete returns a change for deletion of the record.
-func (p *AWSProvider) newChange(action string, endpoint *endpoint.Endpoint) *route53.Change {
+func (p *AWSProvider) newChange(ctx context.Context, action string, endpoint *endpoint.Endpoint) *route53.Change {
 	change := &route53.Change{
 		Action: aws.String(action),
 		ResourceRecordSet: &route53.ResourceRecordSet{
@@ -385,25 +446,20 @@ func (p *AWSProvider) newChange(action string, endpoint *endpoint.Endpoint) *rou
 		},
 	}
 
-	rec, err := p.Records()
+	rec, err := p.Records(ctx)
 	if err != nil {
 		log.Infof("getting records failed: %v", err)
 	}
 
 	if isAWSLoadBalancer(endpoint) {
-		evalTargetHealth := p.evaluateTargetHealth
-		if _, ok := endpoint.ProviderSpecific[providerSpecificEvaluateTargetHealth]; ok {
-			evalTargetHealth = endpoint.ProviderSpecific[providerSpecificEvaluateTargetHealth] == "true"
-		}
-
 		change.ResourceRecordSet.Type = aws.String(route53.RRTypeA)
 		change.ResourceRecordSet.AliasTarget = &route53.AliasTarget{
 			DNSName:              aws.String(endpoint.Targets[0]),
 			HostedZoneId:         aws.String(canonicalHostedZone(endpoint.Targets[0])),
-			EvaluateTargetHealth: aws.Bool(evalTargetHealth),
+			EvaluateTargetHealth: aws.Bool(p.evaluateTargetHealthOf(endpoint)),
 		}
 	} else if hostedZone := isAWSAlias(endpoint, rec); hostedZone != "" {
-		zones, err := p.Zones()
+		zones, err := p.Zones(ctx)
 		if err != nil {
 			log.Errorf("getting zones failed: %v", err)
 		}
@@ -412,7 +468,7 @@ func (p *AWSProvider) newChange(action string, endpoint *endpoint.Endpoint) *rou
 			change.ResourceRecordSet.AliasTarget = &route53.AliasTarget{
 				DNSName:              aws.String(endpoint.Targets[0]),
 				HostedZoneId:         aws.String(cleanZoneID(*zone.Id)),
-				EvaluateTargetHealth: aws.Bool(p.evaluateTargetHealth),
+				EvaluateTargetHealth: aws.Bool(p.evaluateTargetHealthOf(endpoint)),
 			}
 		}
 	} else {
@@ -433,8 +489,8 @@ func (p *AWSProvider) newChange(action string, endpoint *endpoint.Endpoint) *rou
 	return change
 }
 
-func (p *AWSProvider) tagsForZone(zoneID string) (map[string]string, error) {
-	response, err := p.client.ListTagsForResource(&route53.ListTagsForResourceInput{
+func (p *AWSProvider) tagsForZone(ctx context.Context, zoneID string) (map[string]string, error) {
+	response, err := p.client.ListTagsForResourceWithContext(ctx, &route53.ListTagsForResourceInput{
 		ResourceType: aws.String("hostedzone"),
 		ResourceId:   aws.String(zoneID),
 	})
@@ -585,7 +641,7 @@ func isAWSLoadBalancer(ep *endpoint.Endpoint) bool {
 
 // isAWSAlias determines if a given hostname belongs to an AWS Alias record by doing an reverse lookup.
 func isAWSAlias(ep *endpoint.Endpoint, addrs []*endpoint.Endpoint) string {
-	if val, exists := ep.ProviderSpecific["alias"]; ep.RecordType == endpoint.RecordTypeCNAME && exists && val == "true" {
+	if val, exists := ep.ProviderSpecific[providerSpecificAlias]; ep.RecordType == endpoint.RecordTypeCNAME && exists && val == "true" {
 		for _, addr := range addrs {
 			if addr.DNSName == ep.Targets[0] {
 				if hostedZone := canonicalHostedZone(addr.Targets[0]); hostedZone != "" {
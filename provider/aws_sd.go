@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"strings"
 
 	"crypto/sha256"
@@ -49,14 +50,14 @@ const (
 // AWSSDClient is the subset of the AWS Route53 Auto Naming API that we actually use. Add methods as required.
 // Signatures must match exactly. Taken from https://github.com/aws/aws-sdk-go/blob/master/service/servicediscovery/api.go
 type AWSSDClient interface {
-	CreateService(input *sd.CreateServiceInput) (*sd.CreateServiceOutput, error)
-	DeregisterInstance(input *sd.DeregisterInstanceInput) (*sd.DeregisterInstanceOutput, error)
-	GetService(input *sd.GetServiceInput) (*sd.GetServiceOutput, error)
-	ListInstancesPages(input *sd.ListInstancesInput, fn func(*sd.ListInstancesOutput, bool) bool) error
-	ListNamespacesPages(input *sd.ListNamespacesInput, fn func(*sd.ListNamespacesOutput, bool) bool) error
-	ListServicesPages(input *sd.ListServicesInput, fn func(*sd.ListServicesOutput, bool) bool) error
-	RegisterInstance(input *sd.RegisterInstanceInput) (*sd.RegisterInstanceOutput, error)
-	UpdateService(input *sd.UpdateServiceInput) (*sd.UpdateServiceOutput, error)
+	CreateServiceWithContext(ctx context.Context, input *sd.CreateServiceInput, opts ...request.Option) (*sd.CreateServiceOutput, error)
+	DeregisterInstanceWithContext(ctx context.Context, input *sd.DeregisterInstanceInput, opts ...request.Option) (*sd.DeregisterInstanceOutput, error)
+	GetServiceWithContext(ctx context.Context, input *sd.GetServiceInput, opts ...request.Option) (*sd.GetServiceOutput, error)
+	ListInstancesPagesWithContext(ctx context.Context, input *sd.ListInstancesInput, fn func(*sd.ListInstancesOutput, bool) bool, opts ...request.Option) error
+	ListNamespacesPagesWithContext(ctx context.Context, input *sd.ListNamespacesInput, fn func(*sd.ListNamespacesOutput, bool) bool, opts ...request.Option) error
+	ListServicesPagesWithContext(ctx context.Context, input *sd.ListServicesInput, fn func(*sd.ListServicesOutput, bool) bool, opts ...request.Option) error
+	RegisterInstanceWithContext(ctx context.Context, input *sd.RegisterInstanceInput, opts ...request.Option) (*sd.RegisterInstanceOutput, error)
+	UpdateServiceWithContext(ctx context.Context, input *sd.UpdateServiceInput, opts ...request.Option) (*sd.UpdateServiceOutput, error)
 }
 
 // AWSSDProvider is an implementation of Provider for AWS Route53 Auto Naming.
@@ -126,20 +127,20 @@ func newSdNamespaceFilter(namespaceTypeConfig string) *sd.NamespaceFilter {
 }
 
 // Records returns list of all endpoints.
-func (p *AWSSDProvider) Records() (endpoints []*endpoint.Endpoint, err error) {
-	namespaces, err := p.ListNamespaces()
+func (p *AWSSDProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, err error) {
+	namespaces, err := p.ListNamespaces(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, ns := range namespaces {
-		services, err := p.ListServicesByNamespaceID(ns.Id)
+		services, err := p.ListServicesByNamespaceID(ctx, ns.Id)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, srv := range services {
-			instances, err := p.ListInstancesByServiceID(srv.Id)
+			instances, err := p.ListInstancesByServiceID(ctx, srv.Id)
 			if err != nil {
 				return nil, err
 			}
@@ -192,7 +193,7 @@ func (p *AWSSDProvider) instancesToEndpoint(ns *sd.NamespaceSummary, srv *sd.Ser
 }
 
 // ApplyChanges applies Kubernetes changes in endpoints to AWS API
-func (p *AWSSDProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *AWSSDProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	// return early if there is nothing to change
 	if len(changes.Create) == 0 && len(changes.Delete) == 0 && len(changes.UpdateNew) == 0 {
 		log.Info("All records are already up to date")
@@ -204,7 +205,7 @@ func (p *AWSSDProvider) ApplyChanges(changes *plan.Changes) error {
 	changes.Delete = append(changes.Delete, deletes...)
 	changes.Create = append(changes.Create, creates...)
 
-	namespaces, err := p.ListNamespaces()
+	namespaces, err := p.ListNamespaces(ctx)
 	if err != nil {
 		return err
 	}
@@ -216,12 +217,12 @@ func (p *AWSSDProvider) ApplyChanges(changes *plan.Changes) error {
 	// creates = [1.2.3.4, 1.2.3.5]
 	// ```
 	// then when deletes are executed after creates it will miss the `1.2.3.4` instance.
-	err = p.submitDeletes(namespaces, changes.Delete)
+	err = p.submitDeletes(ctx, namespaces, changes.Delete)
 	if err != nil {
 		return err
 	}
 
-	err = p.submitCreates(namespaces, changes.Create)
+	err = p.submitCreates(ctx, namespaces, changes.Create)
 	if err != nil {
 		return err
 	}
@@ -250,11 +251,11 @@ func (p *AWSSDProvider) updatesToCreates(changes *plan.Changes) (creates []*endp
 	return creates, deletes
 }
 
-func (p *AWSSDProvider) submitCreates(namespaces []*sd.NamespaceSummary, changes []*endpoint.Endpoint) error {
+func (p *AWSSDProvider) submitCreates(ctx context.Context, namespaces []*sd.NamespaceSummary, changes []*endpoint.Endpoint) error {
 	changesByNamespaceID := p.changesByNamespaceID(namespaces, changes)
 
 	for nsID, changeList := range changesByNamespaceID {
-		services, err := p.ListServicesByNamespaceID(aws.String(nsID))
+		services, err := p.ListServicesByNamespaceID(ctx, aws.String(nsID))
 		if err != nil {
 			return err
 		}
@@ -265,7 +266,7 @@ func (p *AWSSDProvider) submitCreates(namespaces []*sd.NamespaceSummary, changes
 			srv := services[srvName]
 			if srv == nil {
 				// when service is missing create a new one
-				srv, err = p.CreateService(&nsID, &srvName, ch)
+				srv, err = p.CreateService(ctx, &nsID, &srvName, ch)
 				if err != nil {
 					return err
 				}
@@ -275,14 +276,14 @@ func (p *AWSSDProvider) submitCreates(namespaces []*sd.NamespaceSummary, changes
 				// update service when TTL or Description differ
 				if (ch.RecordTTL.IsConfigured() && *srv.DnsConfig.DnsRecords[0].TTL != int64(ch.RecordTTL)) ||
 					aws.StringValue(srv.Description) != ch.Labels[endpoint.AWSSDDescriptionLabel] {
-					err = p.UpdateService(srv, ch)
+					err = p.UpdateService(ctx, srv, ch)
 					if err != nil {
 						return err
 					}
 				}
 			}
 
-			err = p.RegisterInstance(srv, ch)
+			err = p.RegisterInstance(ctx, srv, ch)
 			if err != nil {
 				return err
 			}
@@ -292,11 +293,11 @@ func (p *AWSSDProvider) submitCreates(namespaces []*sd.NamespaceSummary, changes
 	return nil
 }
 
-func (p *AWSSDProvider) submitDeletes(namespaces []*sd.NamespaceSummary, changes []*endpoint.Endpoint) error {
+func (p *AWSSDProvider) submitDeletes(ctx context.Context, namespaces []*sd.NamespaceSummary, changes []*endpoint.Endpoint) error {
 	changesByNamespaceID := p.changesByNamespaceID(namespaces, changes)
 
 	for nsID, changeList := range changesByNamespaceID {
-		services, err := p.ListServicesByNamespaceID(aws.String(nsID))
+		services, err := p.ListServicesByNamespaceID(ctx, aws.String(nsID))
 		if err != nil {
 			return err
 		}
@@ -310,7 +311,7 @@ func (p *AWSSDProvider) submitDeletes(namespaces []*sd.NamespaceSummary, changes
 				return fmt.Errorf("service \"%s\" is missing when trying to delete \"%v\"", srvName, hostname)
 			}
 
-			err := p.DeregisterInstance(srv, ch)
+			err := p.DeregisterInstance(ctx, srv, ch)
 			if err != nil {
 				return err
 			}
@@ -321,7 +322,7 @@ func (p *AWSSDProvider) submitDeletes(namespaces []*sd.NamespaceSummary, changes
 }
 
 // ListNamespaces returns all namespaces matching defined namespace filter
-func (p *AWSSDProvider) ListNamespaces() ([]*sd.NamespaceSummary, error) {
+func (p *AWSSDProvider) ListNamespaces(ctx context.Context) ([]*sd.NamespaceSummary, error) {
 	namespaces := make([]*sd.NamespaceSummary, 0)
 
 	f := func(resp *sd.ListNamespacesOutput, lastPage bool) bool {
@@ -335,7 +336,7 @@ func (p *AWSSDProvider) ListNamespaces() ([]*sd.NamespaceSummary, error) {
 		return true
 	}
 
-	err := p.client.ListNamespacesPages(&sd.ListNamespacesInput{
+	err := p.client.ListNamespacesPagesWithContext(ctx, &sd.ListNamespacesInput{
 		Filters: []*sd.NamespaceFilter{p.namespaceTypeFilter},
 	}, f)
 	if err != nil {
@@ -346,7 +347,7 @@ func (p *AWSSDProvider) ListNamespaces() ([]*sd.NamespaceSummary, error) {
 }
 
 // ListServicesByNamespaceID returns list of services in given namespace. Returns map[srv_name]*sd.Service
-func (p *AWSSDProvider) ListServicesByNamespaceID(namespaceID *string) (map[string]*sd.Service, error) {
+func (p *AWSSDProvider) ListServicesByNamespaceID(ctx context.Context, namespaceID *string) (map[string]*sd.Service, error) {
 	serviceIds := make([]*string, 0)
 
 	f := func(resp *sd.ListServicesOutput, lastPage bool) bool {
@@ -357,7 +358,7 @@ func (p *AWSSDProvider) ListServicesByNamespaceID(namespaceID *string) (map[stri
 		return true
 	}
 
-	err := p.client.ListServicesPages(&sd.ListServicesInput{
+	err := p.client.ListServicesPagesWithContext(ctx, &sd.ListServicesInput{
 		Filters: []*sd.ServiceFilter{{
 			Name:   aws.String(sd.ServiceFilterNameNamespaceId),
 			Values: []*string{namespaceID},
@@ -370,7 +371,7 @@ func (p *AWSSDProvider) ListServicesByNamespaceID(namespaceID *string) (map[stri
 	// get detail of each listed service
 	services := make(map[string]*sd.Service)
 	for _, serviceID := range serviceIds {
-		service, err := p.GetServiceDetail(serviceID)
+		service, err := p.GetServiceDetail(ctx, serviceID)
 		if err != nil {
 			return nil, err
 		}
@@ -382,8 +383,8 @@ func (p *AWSSDProvider) ListServicesByNamespaceID(namespaceID *string) (map[stri
 }
 
 // GetServiceDetail returns detail of given service
-func (p *AWSSDProvider) GetServiceDetail(serviceID *string) (*sd.Service, error) {
-	output, err := p.client.GetService(&sd.GetServiceInput{
+func (p *AWSSDProvider) GetServiceDetail(ctx context.Context, serviceID *string) (*sd.Service, error) {
+	output, err := p.client.GetServiceWithContext(ctx, &sd.GetServiceInput{
 		Id: serviceID,
 	})
 	if err != nil {
@@ -394,7 +395,7 @@ func (p *AWSSDProvider) GetServiceDetail(serviceID *string) (*sd.Service, error)
 }
 
 // ListInstancesByServiceID returns list of instances registered in given service.
-func (p *AWSSDProvider) ListInstancesByServiceID(serviceID *string) ([]*sd.InstanceSummary, error) {
+func (p *AWSSDProvider) ListInstancesByServiceID(ctx context.Context, serviceID *string) ([]*sd.InstanceSummary, error) {
 	instances := make([]*sd.InstanceSummary, 0)
 
 	f := func(resp *sd.ListInstancesOutput, lastPage bool) bool {
@@ -403,7 +404,7 @@ func (p *AWSSDProvider) ListInstancesByServiceID(serviceID *string) ([]*sd.Insta
 		return true
 	}
 
-	err := p.client.ListInstancesPages(&sd.ListInstancesInput{
+	err := p.client.ListInstancesPagesWithContext(ctx, &sd.ListInstancesInput{
 		ServiceId: serviceID,
 	}, f)
 	if err != nil {
@@ -414,7 +415,7 @@ func (p *AWSSDProvider) ListInstancesByServiceID(serviceID *string) ([]*sd.Insta
 }
 
 // CreateService creates a new service in AWS API. Returns the created service.
-func (p *AWSSDProvider) CreateService(namespaceID *string, srvName *string, ep *endpoint.Endpoint) (*sd.Service, error) {
+func (p *AWSSDProvider) CreateService(ctx context.Context, namespaceID *string, srvName *string, ep *endpoint.Endpoint) (*sd.Service, error) {
 	log.Infof("Creating a new service \"%s\" in \"%s\" namespace", *srvName, *namespaceID)
 
 	srvType := p.serviceTypeFromEndpoint(ep)
@@ -426,7 +427,7 @@ func (p *AWSSDProvider) CreateService(namespaceID *string, srvName *string, ep *
 	}
 
 	if !p.dryRun {
-		out, err := p.client.CreateService(&sd.CreateServiceInput{
+		out, err := p.client.CreateServiceWithContext(ctx, &sd.CreateServiceInput{
 			Name:        srvName,
 			Description: aws.String(ep.Labels[endpoint.AWSSDDescriptionLabel]),
 			DnsConfig: &sd.DnsConfig{
@@ -450,7 +451,7 @@ func (p *AWSSDProvider) CreateService(namespaceID *string, srvName *string, ep *
 }
 
 // UpdateService updates the specified service with information from provided endpoint.
-func (p *AWSSDProvider) UpdateService(service *sd.Service, ep *endpoint.Endpoint) error {
+func (p *AWSSDProvider) UpdateService(ctx context.Context, service *sd.Service, ep *endpoint.Endpoint) error {
 	log.Infof("Updating service \"%s\"", *service.Name)
 
 	srvType := p.serviceTypeFromEndpoint(ep)
@@ -461,7 +462,7 @@ func (p *AWSSDProvider) UpdateService(service *sd.Service, ep *endpoint.Endpoint
 	}
 
 	if !p.dryRun {
-		_, err := p.client.UpdateService(&sd.UpdateServiceInput{
+		_, err := p.client.UpdateServiceWithContext(ctx, &sd.UpdateServiceInput{
 			Id: service.Id,
 			Service: &sd.ServiceChange{
 				Description: aws.String(ep.Labels[endpoint.AWSSDDescriptionLabel]),
@@ -480,7 +481,7 @@ func (p *AWSSDProvider) UpdateService(service *sd.Service, ep *endpoint.Endpoint
 }
 
 // RegisterInstance creates a new instance in given service.
-func (p *AWSSDProvider) RegisterInstance(service *sd.Service, ep *endpoint.Endpoint) error {
+func (p *AWSSDProvider) RegisterInstance(ctx context.Context, service *sd.Service, ep *endpoint.Endpoint) error {
 	for _, target := range ep.Targets {
 		log.Infof("Registering a new instance \"%s\" for service \"%s\" (%s)", target, *service.Name, *service.Id)
 
@@ -499,7 +500,7 @@ func (p *AWSSDProvider) RegisterInstance(service *sd.Service, ep *endpoint.Endpo
 		}
 
 		if !p.dryRun {
-			_, err := p.client.RegisterInstance(&sd.RegisterInstanceInput{
+			_, err := p.client.RegisterInstanceWithContext(ctx, &sd.RegisterInstanceInput{
 				ServiceId:  service.Id,
 				Attributes: attr,
 				InstanceId: aws.String(p.targetToInstanceID(target)),
@@ -514,12 +515,12 @@ func (p *AWSSDProvider) RegisterInstance(service *sd.Service, ep *endpoint.Endpo
 }
 
 // DeregisterInstance removes an instance from given service.
-func (p *AWSSDProvider) DeregisterInstance(service *sd.Service, ep *endpoint.Endpoint) error {
+func (p *AWSSDProvider) DeregisterInstance(ctx context.Context, service *sd.Service, ep *endpoint.Endpoint) error {
 	for _, target := range ep.Targets {
 		log.Infof("De-registering an instance \"%s\" for service \"%s\" (%s)", target, *service.Name, *service.Id)
 
 		if !p.dryRun {
-			_, err := p.client.DeregisterInstance(&sd.DeregisterInstanceInput{
+			_, err := p.client.DeregisterInstanceWithContext(ctx, &sd.DeregisterInstanceInput{
 				InstanceId: aws.String(p.targetToInstanceID(target)),
 				ServiceId:  service.Id,
 			})
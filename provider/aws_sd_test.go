@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"errors"
 	"math/rand"
 	"reflect"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	sd "github.com/aws/aws-sdk-go/service/servicediscovery"
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/internal/testutils"
@@ -46,7 +48,7 @@ type AWSSDClientStub struct {
 	instances map[string]map[string]*sd.Instance
 }
 
-func (s *AWSSDClientStub) CreateService(input *sd.CreateServiceInput) (*sd.CreateServiceOutput, error) {
+func (s *AWSSDClientStub) CreateServiceWithContext(ctx context.Context, input *sd.CreateServiceInput, opts ...request.Option) (*sd.CreateServiceOutput, error) {
 
 	srv := &sd.Service{
 		Id:               aws.String(string(rand.Intn(10000))),
@@ -69,14 +71,14 @@ func (s *AWSSDClientStub) CreateService(input *sd.CreateServiceInput) (*sd.Creat
 	}, nil
 }
 
-func (s *AWSSDClientStub) DeregisterInstance(input *sd.DeregisterInstanceInput) (*sd.DeregisterInstanceOutput, error) {
+func (s *AWSSDClientStub) DeregisterInstanceWithContext(ctx context.Context, input *sd.DeregisterInstanceInput, opts ...request.Option) (*sd.DeregisterInstanceOutput, error) {
 	serviceInstances := s.instances[*input.ServiceId]
 	delete(serviceInstances, *input.InstanceId)
 
 	return &sd.DeregisterInstanceOutput{}, nil
 }
 
-func (s *AWSSDClientStub) GetService(input *sd.GetServiceInput) (*sd.GetServiceOutput, error) {
+func (s *AWSSDClientStub) GetServiceWithContext(ctx context.Context, input *sd.GetServiceInput, opts ...request.Option) (*sd.GetServiceOutput, error) {
 	for _, entry := range s.services {
 		srv, ok := entry[*input.Id]
 		if ok {
@@ -89,7 +91,7 @@ func (s *AWSSDClientStub) GetService(input *sd.GetServiceInput) (*sd.GetServiceO
 	return nil, errors.New("service not found")
 }
 
-func (s *AWSSDClientStub) ListInstancesPages(input *sd.ListInstancesInput, fn func(*sd.ListInstancesOutput, bool) bool) error {
+func (s *AWSSDClientStub) ListInstancesPagesWithContext(ctx context.Context, input *sd.ListInstancesInput, fn func(*sd.ListInstancesOutput, bool) bool, opts ...request.Option) error {
 	instances := make([]*sd.InstanceSummary, 0)
 
 	for _, inst := range s.instances[*input.ServiceId] {
@@ -103,7 +105,7 @@ func (s *AWSSDClientStub) ListInstancesPages(input *sd.ListInstancesInput, fn fu
 	return nil
 }
 
-func (s *AWSSDClientStub) ListNamespacesPages(input *sd.ListNamespacesInput, fn func(*sd.ListNamespacesOutput, bool) bool) error {
+func (s *AWSSDClientStub) ListNamespacesPagesWithContext(ctx context.Context, input *sd.ListNamespacesInput, fn func(*sd.ListNamespacesOutput, bool) bool, opts ...request.Option) error {
 	namespaces := make([]*sd.NamespaceSummary, 0)
 
 	filter := input.Filters[0]
@@ -125,7 +127,7 @@ func (s *AWSSDClientStub) ListNamespacesPages(input *sd.ListNamespacesInput, fn
 	return nil
 }
 
-func (s *AWSSDClientStub) ListServicesPages(input *sd.ListServicesInput, fn func(*sd.ListServicesOutput, bool) bool) error {
+func (s *AWSSDClientStub) ListServicesPagesWithContext(ctx context.Context, input *sd.ListServicesInput, fn func(*sd.ListServicesOutput, bool) bool, opts ...request.Option) error {
 	services := make([]*sd.ServiceSummary, 0)
 
 	// get namespace filter
@@ -146,7 +148,7 @@ func (s *AWSSDClientStub) ListServicesPages(input *sd.ListServicesInput, fn func
 	return nil
 }
 
-func (s *AWSSDClientStub) RegisterInstance(input *sd.RegisterInstanceInput) (*sd.RegisterInstanceOutput, error) {
+func (s *AWSSDClientStub) RegisterInstanceWithContext(ctx context.Context, input *sd.RegisterInstanceInput, opts ...request.Option) (*sd.RegisterInstanceOutput, error) {
 
 	srvInstances, ok := s.instances[*input.ServiceId]
 	if !ok {
@@ -163,7 +165,7 @@ func (s *AWSSDClientStub) RegisterInstance(input *sd.RegisterInstanceInput) (*sd
 	return &sd.RegisterInstanceOutput{}, nil
 }
 
-func (s *AWSSDClientStub) UpdateService(input *sd.UpdateServiceInput) (*sd.UpdateServiceOutput, error) {
+func (s *AWSSDClientStub) UpdateServiceWithContext(ctx context.Context, input *sd.UpdateServiceInput, opts ...request.Option) (*sd.UpdateServiceOutput, error) {
 	out, err := s.GetService(&sd.GetServiceInput{Id: input.Id})
 	if err != nil {
 		return nil, err
@@ -287,7 +289,7 @@ func TestAWSSDProvider_Records(t *testing.T) {
 
 	provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
-	endpoints, _ := provider.Records()
+	endpoints, _ := provider.Records(context.Background())
 
 	assert.True(t, testutils.SameEndpoints(expectedEndpoints, endpoints), "expected and actual endpoints don't match, expected=%v, actual=%v", expectedEndpoints, endpoints)
 }
@@ -316,28 +318,28 @@ func TestAWSSDProvider_ApplyChanges(t *testing.T) {
 	provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
 	// apply creates
-	provider.ApplyChanges(&plan.Changes{
+	provider.ApplyChanges(context.Background(), &plan.Changes{
 		Create: expectedEndpoints,
 	})
 
 	// make sure services were created
 	assert.Len(t, api.services["private"], 3)
-	existingServices, _ := provider.ListServicesByNamespaceID(namespaces["private"].Id)
+	existingServices, _ := provider.ListServicesByNamespaceID(context.Background(), namespaces["private"].Id)
 	assert.NotNil(t, existingServices["service1"])
 	assert.NotNil(t, existingServices["service2"])
 	assert.NotNil(t, existingServices["service3"])
 
 	// make sure instances were registered
-	endpoints, _ := provider.Records()
+	endpoints, _ := provider.Records(context.Background())
 	assert.True(t, testutils.SameEndpoints(expectedEndpoints, endpoints), "expected and actual endpoints don't match, expected=%v, actual=%v", expectedEndpoints, endpoints)
 
 	// apply deletes
-	provider.ApplyChanges(&plan.Changes{
+	provider.ApplyChanges(context.Background(), &plan.Changes{
 		Delete: expectedEndpoints,
 	})
 
 	// make sure all instances are gone
-	endpoints, _ = provider.Records()
+	endpoints, _ = provider.Records(context.Background())
 	assert.Empty(t, endpoints)
 }
 
@@ -372,7 +374,7 @@ func TestAWSSDProvider_ListNamespaces(t *testing.T) {
 	} {
 		provider := newTestAWSSDProvider(api, tc.domainFilter, tc.namespaceTypeFilter)
 
-		result, err := provider.ListNamespaces()
+		result, err := provider.ListNamespaces(context.Background())
 		require.NoError(t, err)
 
 		expectedMap := make(map[string]*sd.NamespaceSummary)
@@ -435,7 +437,7 @@ func TestAWSSDProvider_ListServicesByNamespace(t *testing.T) {
 	} {
 		provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
-		result, err := provider.ListServicesByNamespaceID(namespaces["private"].Id)
+		result, err := provider.ListServicesByNamespaceID(context.Background(), namespaces["private"].Id)
 		require.NoError(t, err)
 
 		if !reflect.DeepEqual(result, tc.expectedServices) {
@@ -491,7 +493,7 @@ func TestAWSSDProvider_ListInstancesByService(t *testing.T) {
 
 	provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
-	result, err := provider.ListInstancesByServiceID(services["private"]["srv1"].Id)
+	result, err := provider.ListInstancesByServiceID(context.Background(), services["private"]["srv1"].Id)
 	require.NoError(t, err)
 
 	expectedInstances := []*sd.InstanceSummary{instanceToInstanceSummary(instances["srv1"]["inst1"]), instanceToInstanceSummary(instances["srv1"]["inst2"])}
@@ -529,7 +531,7 @@ func TestAWSSDProvider_CreateService(t *testing.T) {
 	provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
 	// A type
-	provider.CreateService(aws.String("private"), aws.String("A-srv"), &endpoint.Endpoint{
+	provider.CreateService(context.Background(), aws.String("private"), aws.String("A-srv"), &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeA,
 		RecordTTL:  60,
 		Targets:    endpoint.Targets{"1.2.3.4"},
@@ -547,7 +549,7 @@ func TestAWSSDProvider_CreateService(t *testing.T) {
 	}
 
 	// CNAME type
-	provider.CreateService(aws.String("private"), aws.String("CNAME-srv"), &endpoint.Endpoint{
+	provider.CreateService(context.Background(), aws.String("private"), aws.String("CNAME-srv"), &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeCNAME,
 		RecordTTL:  80,
 		Targets:    endpoint.Targets{"cname.target.com"},
@@ -565,7 +567,7 @@ func TestAWSSDProvider_CreateService(t *testing.T) {
 	}
 
 	// ALIAS type
-	provider.CreateService(aws.String("private"), aws.String("ALIAS-srv"), &endpoint.Endpoint{
+	provider.CreateService(context.Background(), aws.String("private"), aws.String("ALIAS-srv"), &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeCNAME,
 		RecordTTL:  100,
 		Targets:    endpoint.Targets{"load-balancer.us-east-1.elb.amazonaws.com"},
@@ -633,7 +635,7 @@ func TestAWSSDProvider_UpdateService(t *testing.T) {
 	provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
 	// update service with different TTL
-	provider.UpdateService(services["private"]["srv1"], &endpoint.Endpoint{
+	provider.UpdateService(context.Background(), services["private"]["srv1"], &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeA,
 		RecordTTL:  100,
 	})
@@ -702,7 +704,7 @@ func TestAWSSDProvider_RegisterInstance(t *testing.T) {
 	expectedInstances := make(map[string]*sd.Instance)
 
 	// IP-based instance
-	provider.RegisterInstance(services["private"]["a-srv"], &endpoint.Endpoint{
+	provider.RegisterInstance(context.Background(), services["private"]["a-srv"], &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeA,
 		DNSName:    "service1.private.com.",
 		RecordTTL:  300,
@@ -722,7 +724,7 @@ func TestAWSSDProvider_RegisterInstance(t *testing.T) {
 	}
 
 	// ALIAS instance
-	provider.RegisterInstance(services["private"]["alias-srv"], &endpoint.Endpoint{
+	provider.RegisterInstance(context.Background(), services["private"]["alias-srv"], &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeCNAME,
 		DNSName:    "service1.private.com.",
 		RecordTTL:  300,
@@ -742,7 +744,7 @@ func TestAWSSDProvider_RegisterInstance(t *testing.T) {
 	}
 
 	// CNAME instance
-	provider.RegisterInstance(services["private"]["cname-srv"], &endpoint.Endpoint{
+	provider.RegisterInstance(context.Background(), services["private"]["cname-srv"], &endpoint.Endpoint{
 		RecordType: endpoint.RecordTypeCNAME,
 		DNSName:    "service2.private.com.",
 		RecordTTL:  300,
@@ -802,7 +804,7 @@ func TestAWSSDProvider_DeregisterInstance(t *testing.T) {
 
 	provider := newTestAWSSDProvider(api, NewDomainFilter([]string{}), "")
 
-	provider.DeregisterInstance(services["private"]["srv1"], endpoint.NewEndpoint("srv1.private.com.", endpoint.RecordTypeA, "1.2.3.4"))
+	provider.DeregisterInstance(context.Background(), services["private"]["srv1"], endpoint.NewEndpoint("srv1.private.com.", endpoint.RecordTypeA, "1.2.3.4"))
 
 	assert.Len(t, instances["srv1"], 0)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -162,7 +163,7 @@ func getAccessToken(cfg config, environment azure.Environment) (*adal.ServicePri
 // Records gets the current records.
 //
 // Returns the current records or an error if the operation failed.
-func (p *AzureProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
+func (p *AzureProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
 	zones, err := p.zones()
 	if err != nil {
 		return nil, err
@@ -175,10 +176,13 @@ func (p *AzureProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 				return true
 			}
 			recordType := strings.TrimLeft(*recordSet.Type, "Microsoft.Network/dnszones/")
-			if !supportedRecordType(recordType) {
+			if !supportedExtendedRecordType(recordType) {
 				return true
 			}
 			name := formatAzureDNSName(*recordSet.Name, *zone.Name)
+			if zoneApexNS(recordType, name, *zone.Name) {
+				return true
+			}
 			target := extractAzureTarget(&recordSet)
 			if target == "" {
 				log.Errorf("Failed to extract target for '%s' with type '%s'.", name, recordType)
@@ -209,16 +213,17 @@ func (p *AzureProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 // ApplyChanges applies the given changes.
 //
 // Returns nil if the operation was successful or an error if the operation failed.
-func (p *AzureProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *AzureProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	zones, err := p.zones()
 	if err != nil {
 		return err
 	}
 
 	deleted, updated := p.mapChanges(zones, changes)
-	p.deleteRecords(deleted)
-	p.updateRecords(updated)
-	return nil
+	if err := p.deleteRecords(ctx, deleted); err != nil {
+		return err
+	}
+	return p.updateRecords(ctx, updated)
 }
 
 func (p *AzureProvider) zones() ([]dns.Zone, error) {
@@ -256,6 +261,19 @@ func (p *AzureProvider) zones() ([]dns.Zone, error) {
 	return zones, nil
 }
 
+// ZoneNames returns the names of the zones of the resource group, see ZoneNamesLister
+func (p *AzureProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.zones()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, *zone.Name)
+	}
+	return names, nil
+}
+
 func (p *AzureProvider) iterateRecords(zoneName string, callback func(dns.RecordSet) bool) error {
 	log.Debugf("Retrieving Azure DNS records for zone '%s'.", zoneName)
 
@@ -322,10 +340,13 @@ func (p *AzureProvider) mapChanges(zones []dns.Zone, changes *plan.Changes) (azu
 	return deleted, updated
 }
 
-func (p *AzureProvider) deleteRecords(deleted azureChangeMap) {
+func (p *AzureProvider) deleteRecords(ctx context.Context, deleted azureChangeMap) error {
 	// Delete records first
 	for zone, endpoints := range deleted {
 		for _, endpoint := range endpoints {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			name := p.recordSetNameForZone(zone, endpoint)
 			if p.dryRun {
 				log.Infof("Would delete %s record named '%s' for Azure DNS zone '%s'.", endpoint.RecordType, name, zone)
@@ -343,11 +364,15 @@ func (p *AzureProvider) deleteRecords(deleted azureChangeMap) {
 			}
 		}
 	}
+	return nil
 }
 
-func (p *AzureProvider) updateRecords(updated azureChangeMap) {
+func (p *AzureProvider) updateRecords(ctx context.Context, updated azureChangeMap) error {
 	for zone, endpoints := range updated {
 		for _, endpoint := range endpoints {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			name := p.recordSetNameForZone(zone, endpoint)
 			if p.dryRun {
 				log.Infof(
@@ -392,6 +417,7 @@ func (p *AzureProvider) updateRecords(updated azureChangeMap) {
 			}
 		}
 	}
+	return nil
 }
 
 func (p *AzureProvider) recordSetNameForZone(zone string, endpoint *endpoint.Endpoint) string {
@@ -424,6 +450,17 @@ func (p *AzureProvider) newRecordSet(endpoint *endpoint.Endpoint) (dns.RecordSet
 				},
 			},
 		}, nil
+	case dns.AAAA:
+		return dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL: to.Int64Ptr(ttl),
+				AaaaRecords: &[]dns.AaaaRecord{
+					{
+						Ipv6Address: to.StringPtr(endpoint.Targets[0]),
+					},
+				},
+			},
+		}, nil
 	case dns.CNAME:
 		return dns.RecordSet{
 			RecordSetProperties: &dns.RecordSetProperties{
@@ -433,6 +470,54 @@ func (p *AzureProvider) newRecordSet(endpoint *endpoint.Endpoint) (dns.RecordSet
 				},
 			},
 		}, nil
+	case dns.SRV:
+		srvRecord, err := newAzureSRVRecord(endpoint.Targets[0])
+		if err != nil {
+			return dns.RecordSet{}, err
+		}
+		return dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL: to.Int64Ptr(ttl),
+				SrvRecords: &[]dns.SrvRecord{
+					srvRecord,
+				},
+			},
+		}, nil
+	case dns.MX:
+		mxRecord, err := newAzureMXRecord(endpoint.Targets[0])
+		if err != nil {
+			return dns.RecordSet{}, err
+		}
+		return dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL: to.Int64Ptr(ttl),
+				MxRecords: &[]dns.MxRecord{
+					mxRecord,
+				},
+			},
+		}, nil
+	case dns.NS:
+		return dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL: to.Int64Ptr(ttl),
+				NsRecords: &[]dns.NsRecord{
+					{
+						Nsdname: to.StringPtr(endpoint.Targets[0]),
+					},
+				},
+			},
+		}, nil
+	case dns.PTR:
+		return dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL: to.Int64Ptr(ttl),
+				PtrRecords: &[]dns.PtrRecord{
+					{
+						Ptrdname: to.StringPtr(endpoint.Targets[0]),
+					},
+				},
+			},
+		}, nil
 	case dns.TXT:
 		return dns.RecordSet{
 			RecordSetProperties: &dns.RecordSetProperties{
@@ -450,6 +535,31 @@ func (p *AzureProvider) newRecordSet(endpoint *endpoint.Endpoint) (dns.RecordSet
 	return dns.RecordSet{}, fmt.Errorf("unsupported record type '%s'", endpoint.RecordType)
 }
 
+// newAzureSRVRecord maps the textual form of an SRV target to an Azure SRV record
+func newAzureSRVRecord(target string) (dns.SrvRecord, error) {
+	srv, err := endpoint.ParseSRVTarget(target)
+	if err != nil {
+		return dns.SrvRecord{}, err
+	}
+	return dns.SrvRecord{
+		Priority: to.Int32Ptr(int32(srv.Priority)),
+		Weight:   to.Int32Ptr(int32(srv.Weight)),
+		Port:     to.Int32Ptr(int32(srv.Port)),
+		Target:   to.StringPtr(srv.Target),
+	}, nil
+}
+
+func newAzureMXRecord(target string) (dns.MxRecord, error) {
+	mx, err := endpoint.ParseMXTarget(target)
+	if err != nil {
+		return dns.MxRecord{}, err
+	}
+	return dns.MxRecord{
+		Preference: to.Int32Ptr(int32(mx.Preference)),
+		Exchange:   to.StringPtr(mx.Exchange),
+	}, nil
+}
+
 // Helper function (shared with test code)
 func formatAzureDNSName(recordName, zoneName string) string {
 	if recordName == "@" {
@@ -471,12 +581,44 @@ func extractAzureTarget(recordSet *dns.RecordSet) string {
 		return *(*aRecords)[0].Ipv4Address
 	}
 
+	// Check for AAAA records
+	aaaaRecords := properties.AaaaRecords
+	if aaaaRecords != nil && len(*aaaaRecords) > 0 && (*aaaaRecords)[0].Ipv6Address != nil {
+		return *(*aaaaRecords)[0].Ipv6Address
+	}
+
 	// Check for CNAME records
 	cnameRecord := properties.CnameRecord
 	if cnameRecord != nil && cnameRecord.Cname != nil {
 		return *cnameRecord.Cname
 	}
 
+	// Check for SRV records
+	srvRecords := properties.SrvRecords
+	if srvRecords != nil && len(*srvRecords) > 0 && (*srvRecords)[0].Target != nil {
+		srv := (*srvRecords)[0]
+		return endpoint.NewSRVTarget(uint16(to.Int32(srv.Priority)), uint16(to.Int32(srv.Weight)), uint16(to.Int32(srv.Port)), *srv.Target).String()
+	}
+
+	// Check for MX records
+	mxRecords := properties.MxRecords
+	if mxRecords != nil && len(*mxRecords) > 0 && (*mxRecords)[0].Exchange != nil {
+		mx := (*mxRecords)[0]
+		return endpoint.NewMXTarget(uint16(to.Int32(mx.Preference)), *mx.Exchange).String()
+	}
+
+	// Check for NS records
+	nsRecords := properties.NsRecords
+	if nsRecords != nil && len(*nsRecords) > 0 && (*nsRecords)[0].Nsdname != nil {
+		return *(*nsRecords)[0].Nsdname
+	}
+
+	// Check for PTR records
+	ptrRecords := properties.PtrRecords
+	if ptrRecords != nil && len(*ptrRecords) > 0 && (*ptrRecords)[0].Ptrdname != nil {
+		return *(*ptrRecords)[0].Ptrdname
+	}
+
 	// Check for TXT records
 	txtRecords := properties.TxtRecords
 	if txtRecords != nil && len(*txtRecords) > 0 && (*txtRecords)[0].Value != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/arm/dns"
@@ -68,6 +69,17 @@ func aRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetPropertie
 	}
 }
 
+func aaaaRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
+	return &dns.RecordSetProperties{
+		TTL: to.Int64Ptr(ttl),
+		AaaaRecords: &[]dns.AaaaRecord{
+			{
+				Ipv6Address: to.StringPtr(value),
+			},
+		},
+	}
+}
+
 func cNameRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
 	return &dns.RecordSetProperties{
 		TTL: to.Int64Ptr(ttl),
@@ -88,6 +100,48 @@ func txtRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetPropert
 	}
 }
 
+func srvRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
+	srvRecord, _ := newAzureSRVRecord(value)
+	return &dns.RecordSetProperties{
+		TTL: to.Int64Ptr(ttl),
+		SrvRecords: &[]dns.SrvRecord{
+			srvRecord,
+		},
+	}
+}
+
+func mxRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
+	mxRecord, _ := newAzureMXRecord(value)
+	return &dns.RecordSetProperties{
+		TTL: to.Int64Ptr(ttl),
+		MxRecords: &[]dns.MxRecord{
+			mxRecord,
+		},
+	}
+}
+
+func nsRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
+	return &dns.RecordSetProperties{
+		TTL: to.Int64Ptr(ttl),
+		NsRecords: &[]dns.NsRecord{
+			{
+				Nsdname: to.StringPtr(value),
+			},
+		},
+	}
+}
+
+func ptrRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
+	return &dns.RecordSetProperties{
+		TTL: to.Int64Ptr(ttl),
+		PtrRecords: &[]dns.PtrRecord{
+			{
+				Ptrdname: to.StringPtr(value),
+			},
+		},
+	}
+}
+
 func othersRecordSetPropertiesGetter(value string, ttl int64) *dns.RecordSetProperties {
 	return &dns.RecordSetProperties{
 		TTL: to.Int64Ptr(ttl),
@@ -102,10 +156,20 @@ func createMockRecordSetWithTTL(name, recordType, value string, ttl int64) dns.R
 	switch recordType {
 	case endpoint.RecordTypeA:
 		getterFunc = aRecordSetPropertiesGetter
+	case endpoint.RecordTypeAAAA:
+		getterFunc = aaaaRecordSetPropertiesGetter
 	case endpoint.RecordTypeCNAME:
 		getterFunc = cNameRecordSetPropertiesGetter
 	case endpoint.RecordTypeTXT:
 		getterFunc = txtRecordSetPropertiesGetter
+	case endpoint.RecordTypeSRV:
+		getterFunc = srvRecordSetPropertiesGetter
+	case endpoint.RecordTypeMX:
+		getterFunc = mxRecordSetPropertiesGetter
+	case endpoint.RecordTypeNS:
+		getterFunc = nsRecordSetPropertiesGetter
+	case endpoint.RecordTypePTR:
+		getterFunc = ptrRecordSetPropertiesGetter
 	default:
 		getterFunc = othersRecordSetPropertiesGetter
 	}
@@ -185,14 +249,19 @@ func TestAzureRecord(t *testing.T) {
 			createMockRecordSet("@", endpoint.RecordTypeA, "123.123.123.122"),
 			createMockRecordSet("@", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default"),
 			createMockRecordSetWithTTL("nginx", endpoint.RecordTypeA, "123.123.123.123", 3600),
+			createMockRecordSetWithTTL("nginx", endpoint.RecordTypeAAAA, "2001:db8::1", 3600),
 			createMockRecordSetWithTTL("nginx", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default", recordTTL),
 			createMockRecordSetWithTTL("hack", endpoint.RecordTypeCNAME, "hack.azurewebsites.net", 10),
+			createMockRecordSetWithTTL("_sip._tcp", endpoint.RecordTypeSRV, "10 20 5060 sip.example.com", 10),
+			createMockRecordSetWithTTL("@", endpoint.RecordTypeMX, "10 mail.example.com", 10),
+			createMockRecordSetWithTTL("sub", endpoint.RecordTypeNS, "ns1.example.org", 10),
+			createMockRecordSetWithTTL("4.3.2.1", endpoint.RecordTypePTR, "host.example.com", 10),
 		},
 	}
 
 	provider := newAzureProvider(NewDomainFilter([]string{"example.com"}), NewZoneIDFilter([]string{""}), true, "k8s", &zonesClient, &recordsClient)
 
-	actual, err := provider.Records()
+	actual, err := provider.Records(context.Background())
 
 	if err != nil {
 		t.Fatal(err)
@@ -201,8 +270,13 @@ func TestAzureRecord(t *testing.T) {
 		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "123.123.123.122"),
 		endpoint.NewEndpoint("example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default"),
 		endpoint.NewEndpointWithTTL("nginx.example.com", endpoint.RecordTypeA, 3600, "123.123.123.123"),
+		endpoint.NewEndpointWithTTL("nginx.example.com", endpoint.RecordTypeAAAA, 3600, "2001:db8::1"),
 		endpoint.NewEndpointWithTTL("nginx.example.com", endpoint.RecordTypeTXT, recordTTL, "heritage=external-dns,external-dns/owner=default"),
 		endpoint.NewEndpointWithTTL("hack.example.com", endpoint.RecordTypeCNAME, 10, "hack.azurewebsites.net"),
+		endpoint.NewEndpointWithTTL("_sip._tcp.example.com", endpoint.RecordTypeSRV, 10, "10 20 5060 sip.example.com"),
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeMX, 10, "10 mail.example.com"),
+		endpoint.NewEndpointWithTTL("sub.example.com", endpoint.RecordTypeNS, 10, "ns1.example.org"),
+		endpoint.NewEndpointWithTTL("4.3.2.1.example.com", endpoint.RecordTypePTR, 10, "host.example.com"),
 	}
 
 	validateAzureEndpoints(t, actual, expected)
@@ -299,7 +373,7 @@ func testAzureApplyChangesInternal(t *testing.T, dryRun bool, client RecordsClie
 		Delete:    deleteRecords,
 	}
 
-	if err := provider.ApplyChanges(changes); err != nil {
+	if err := provider.ApplyChanges(context.Background(), changes); err != nil {
 		t.Fatal(err)
 	}
 }
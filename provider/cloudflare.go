@@ -17,8 +17,10 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	cloudflare "github.com/cloudflare/cloudflare-go"
@@ -37,6 +39,8 @@ const (
 	cloudFlareUpdate = "UPDATE"
 	// defaultCloudFlareRecordTTL 1 = automatic
 	defaultCloudFlareRecordTTL = 1
+	// provider specific key that designates whether the traffic of a record is proxied by CloudFlare
+	providerSpecificCloudFlareProxied = "cloudflare/proxied"
 )
 
 var cloudFlareTypeNotSupported = map[string]bool{
@@ -147,8 +151,21 @@ func (p *CloudFlareProvider) Zones() ([]cloudflare.Zone, error) {
 	return result, nil
 }
 
+// ZoneNames returns the names of the zones, see ZoneNamesLister
+func (p *CloudFlareProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.Zones()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, zone.Name)
+	}
+	return names, nil
+}
+
 // Records returns the list of records.
-func (p *CloudFlareProvider) Records() ([]*endpoint.Endpoint, error) {
+func (p *CloudFlareProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	zones, err := p.Zones()
 	if err != nil {
 		return nil, err
@@ -163,7 +180,9 @@ func (p *CloudFlareProvider) Records() ([]*endpoint.Endpoint, error) {
 
 		for _, r := range records {
 			if supportedRecordType(r.Type) {
-				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.TTL), r.Content))
+				endpoints = append(endpoints, endpoint.
+					NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.TTL), r.Content).
+					WithProviderSpecific(providerSpecificCloudFlareProxied, strconv.FormatBool(r.Proxied)))
 			}
 		}
 	}
@@ -171,19 +190,47 @@ func (p *CloudFlareProvider) Records() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
+// ProviderSpecificKeys returns the provider specific properties which are read back from CloudFlare.
+func (p *CloudFlareProvider) ProviderSpecificKeys() []string {
+	return []string{providerSpecificCloudFlareProxied}
+}
+
+// AdjustEndpoints sets whether the records of the endpoints are proxied, the default of the provider
+// unless the endpoint sets it, and never for records which CloudFlare can't proxy.
+func (p *CloudFlareProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	for _, ep := range endpoints {
+		// the properties may be shared with other endpoints of the same resource
+		ep.ProviderSpecific = ep.ProviderSpecific.DeepCopy()
+		ep.WithProviderSpecific(providerSpecificCloudFlareProxied, strconv.FormatBool(proxiedOf(ep, p.proxied)))
+	}
+	return endpoints
+}
+
+// proxiedOf returns whether the record of the endpoint is proxied, the given default unless the
+// endpoint sets it, and never for records which CloudFlare can't proxy
+func proxiedOf(ep *endpoint.Endpoint, proxied bool) bool {
+	if value, ok := ep.ProviderSpecific[providerSpecificCloudFlareProxied]; ok {
+		proxied = value == "true"
+	}
+	if cloudFlareTypeNotSupported[ep.RecordType] || strings.Contains(ep.DNSName, "*") {
+		return false
+	}
+	return proxied
+}
+
 // ApplyChanges applies a given set of changes in a given zone.
-func (p *CloudFlareProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *CloudFlareProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	combinedChanges := make([]*cloudFlareChange, 0, len(changes.Create)+len(changes.UpdateNew)+len(changes.Delete))
 
 	combinedChanges = append(combinedChanges, newCloudFlareChanges(cloudFlareCreate, changes.Create, p.proxied)...)
 	combinedChanges = append(combinedChanges, newCloudFlareChanges(cloudFlareUpdate, changes.UpdateNew, p.proxied)...)
 	combinedChanges = append(combinedChanges, newCloudFlareChanges(cloudFlareDelete, changes.Delete, p.proxied)...)
 
-	return p.submitChanges(combinedChanges)
+	return p.submitChanges(ctx, combinedChanges)
 }
 
 // submitChanges takes a zone and a collection of Changes and sends them as a single transaction.
-func (p *CloudFlareProvider) submitChanges(changes []*cloudFlareChange) error {
+func (p *CloudFlareProvider) submitChanges(ctx context.Context, changes []*cloudFlareChange) error {
 	// return early if there is nothing to change
 	if len(changes) == 0 {
 		return nil
@@ -197,11 +244,17 @@ func (p *CloudFlareProvider) submitChanges(changes []*cloudFlareChange) error {
 	changesByZone := p.changesByZone(zones, changes)
 
 	for zoneID, changes := range changesByZone {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		records, err := p.Client.DNSRecords(zoneID, cloudflare.DNSRecord{})
 		if err != nil {
 			return fmt.Errorf("could not fetch records from zone, %v", err)
 		}
 		for _, change := range changes {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			logFields := log.Fields{
 				"record": change.ResourceRecordSet.Name,
 				"type":   change.ResourceRecordSet.Type,
@@ -282,9 +335,7 @@ func newCloudFlareChanges(action string, endpoints []*endpoint.Endpoint, proxied
 
 func newCloudFlareChange(action string, endpoint *endpoint.Endpoint, proxied bool) *cloudFlareChange {
 	ttl := defaultCloudFlareRecordTTL
-	if proxied && (cloudFlareTypeNotSupported[endpoint.RecordType] || strings.Contains(endpoint.DNSName, "*")) {
-		proxied = false
-	}
+	proxied = proxiedOf(endpoint, proxied)
 	if endpoint.RecordTTL.IsConfigured() {
 		ttl = int(endpoint.RecordTTL)
 	}
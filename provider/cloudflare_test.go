@@ -17,8 +17,10 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -66,6 +68,21 @@ func (m *mockCloudFlareClient) ListZones(zoneID ...string) ([]cloudflare.Zone, e
 	return []cloudflare.Zone{{ID: "1234567890", Name: "ext-dns-test.zalando.to."}, {ID: "1234567891", Name: "foo.com."}}, nil
 }
 
+// mockCloudFlareRecordsClient keeps the records it creates and returns them
+type mockCloudFlareRecordsClient struct {
+	mockCloudFlareClient
+	records map[string][]cloudflare.DNSRecord
+}
+
+func (m *mockCloudFlareRecordsClient) CreateDNSRecord(zoneID string, rr cloudflare.DNSRecord) (*cloudflare.DNSRecordResponse, error) {
+	m.records[zoneID] = append(m.records[zoneID], rr)
+	return &cloudflare.DNSRecordResponse{Result: rr}, nil
+}
+
+func (m *mockCloudFlareRecordsClient) DNSRecords(zoneID string, rr cloudflare.DNSRecord) ([]cloudflare.DNSRecord, error) {
+	return m.records[zoneID], nil
+}
+
 type mockCloudFlareUserDetailsFail struct{}
 
 func (m *mockCloudFlareUserDetailsFail) CreateDNSRecord(zoneID string, rr cloudflare.DNSRecord) (*cloudflare.DNSRecordResponse, error) {
@@ -395,6 +412,46 @@ func TestNewCloudFlareChangeProxiable(t *testing.T) {
 
 	change := newCloudFlareChange(cloudFlareCreate, &endpoint.Endpoint{DNSName: "*.foo", RecordType: "A", Targets: endpoint.Targets{"target"}}, true)
 	assert.False(t, change.ResourceRecordSet.Proxied)
+
+	change = newCloudFlareChange(cloudFlareCreate, endpoint.NewEndpoint("new", "A", "target").WithProviderSpecific(providerSpecificCloudFlareProxied, "false"), true)
+	assert.False(t, change.ResourceRecordSet.Proxied)
+	change = newCloudFlareChange(cloudFlareCreate, endpoint.NewEndpoint("new", "A", "target").WithProviderSpecific(providerSpecificCloudFlareProxied, "true"), false)
+	assert.True(t, change.ResourceRecordSet.Proxied)
+}
+
+func TestCloudFlareAdjustEndpoints(t *testing.T) {
+	provider := &CloudFlareProvider{proxied: true}
+
+	endpoints := provider.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("proxied.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("direct.example.org", endpoint.RecordTypeA, "1.2.3.4").WithProviderSpecific(providerSpecificCloudFlareProxied, "false"),
+		endpoint.NewEndpoint("*.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("txt.example.org", endpoint.RecordTypeTXT, "text"),
+	})
+
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("proxied.example.org", endpoint.RecordTypeA, "1.2.3.4").WithProviderSpecific(providerSpecificCloudFlareProxied, "true"),
+		endpoint.NewEndpoint("direct.example.org", endpoint.RecordTypeA, "1.2.3.4").WithProviderSpecific(providerSpecificCloudFlareProxied, "false"),
+		endpoint.NewEndpoint("*.example.org", endpoint.RecordTypeA, "1.2.3.4").WithProviderSpecific(providerSpecificCloudFlareProxied, "false"),
+		endpoint.NewEndpoint("txt.example.org", endpoint.RecordTypeTXT, "text").WithProviderSpecific(providerSpecificCloudFlareProxied, "false"),
+	})
+	assert.Equal(t, []string{providerSpecificCloudFlareProxied}, provider.ProviderSpecificKeys())
+}
+
+func TestCloudFlareAdjustEndpointsSharedProperties(t *testing.T) {
+	provider := &CloudFlareProvider{proxied: true}
+
+	// the endpoints of the hosts of one ingress share their properties
+	shared := endpoint.ProviderSpecific{}
+	wildcard := endpoint.NewEndpoint("*.example.org", endpoint.RecordTypeA, "1.2.3.4")
+	wildcard.ProviderSpecific = shared
+	host := endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "1.2.3.4")
+	host.ProviderSpecific = shared
+
+	provider.AdjustEndpoints([]*endpoint.Endpoint{wildcard, host})
+	assert.Equal(t, "false", wildcard.ProviderSpecific[providerSpecificCloudFlareProxied])
+	assert.Equal(t, "true", host.ProviderSpecific[providerSpecificCloudFlareProxied])
+	assert.Empty(t, shared)
 }
 
 func TestCloudFlareZones(t *testing.T) {
@@ -418,24 +475,50 @@ func TestRecords(t *testing.T) {
 	provider := &CloudFlareProvider{
 		Client: &mockCloudFlareClient{},
 	}
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	if err != nil {
 		t.Errorf("should not fail, %s", err)
 	}
 
 	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "false", records[0].ProviderSpecific[providerSpecificCloudFlareProxied])
 	provider.Client = &mockCloudFlareDNSRecordsFail{}
-	_, err = provider.Records()
+	_, err = provider.Records(context.Background())
 	if err == nil {
 		t.Errorf("expected to fail")
 	}
 	provider.Client = &mockCloudFlareListZonesFail{}
-	_, err = provider.Records()
+	_, err = provider.Records(context.Background())
 	if err == nil {
 		t.Errorf("expected to fail")
 	}
 }
 
+func TestCloudFlareTXTRecordsWithSeveralStrings(t *testing.T) {
+	labels := endpoint.NewLabels()
+	labels[endpoint.OwnerLabelKey] = "default"
+	labels[endpoint.ResourceLabelKey] = "ingress/default/" + strings.Repeat("x", 300)
+
+	client := &mockCloudFlareRecordsClient{records: map[string][]cloudflare.DNSRecord{}}
+	provider := &CloudFlareProvider{Client: client}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			{DNSName: "txt.ext-dns-test.zalando.to.", RecordType: endpoint.RecordTypeTXT, Targets: endpoint.Targets{labels.SerializeTXT()}},
+		},
+	}
+	require.NoError(t, provider.ApplyChanges(context.Background(), changes))
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Len(t, records[0].Targets, 1)
+
+	read, err := endpoint.NewLabelsFromString(records[0].Targets[0])
+	require.NoError(t, err)
+	assert.Equal(t, labels, read)
+}
+
 func TestNewCloudFlareProvider(t *testing.T) {
 	_ = os.Setenv("CF_API_KEY", "xxxxxxxxxxxxxxxxx")
 	_ = os.Setenv("CF_API_EMAIL", "test@test.com")
@@ -460,7 +543,7 @@ func TestApplyChanges(t *testing.T) {
 	changes.Delete = []*endpoint.Endpoint{{DNSName: "foobar.ext-dns-test.zalando.to.", Targets: endpoint.Targets{"target"}}}
 	changes.UpdateOld = []*endpoint.Endpoint{{DNSName: "foobar.ext-dns-test.zalando.to.", Targets: endpoint.Targets{"target-old"}}}
 	changes.UpdateNew = []*endpoint.Endpoint{{DNSName: "foobar.ext-dns-test.zalando.to.", Targets: endpoint.Targets{"target-new"}}}
-	err := provider.ApplyChanges(changes)
+	err := provider.ApplyChanges(context.Background(), changes)
 	if err != nil {
 		t.Errorf("should not fail, %s", err)
 	}
@@ -471,10 +554,19 @@ func TestApplyChanges(t *testing.T) {
 	changes.UpdateOld = []*endpoint.Endpoint{}
 	changes.UpdateNew = []*endpoint.Endpoint{}
 
-	err = provider.ApplyChanges(changes)
+	err = provider.ApplyChanges(context.Background(), changes)
 	if err != nil {
 		t.Errorf("should not fail, %s", err)
 	}
+
+	// cancelled synchronization
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	changes.Create = []*endpoint.Endpoint{{DNSName: "new.ext-dns-test.zalando.to.", Targets: endpoint.Targets{"target"}}}
+	err = provider.ApplyChanges(ctx, changes)
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
 }
 
 func TestCloudFlareGetRecordID(t *testing.T) {
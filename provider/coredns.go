@@ -52,9 +52,9 @@ const (
 
 // coreDNSClient is an interface to work with CoreDNS service records in etcd
 type coreDNSClient interface {
-	GetServices(prefix string) ([]*Service, error)
-	SaveService(value *Service) error
-	DeleteService(key string) error
+	GetServices(ctx context.Context, prefix string) ([]*Service, error)
+	SaveService(ctx context.Context, value *Service) error
+	DeleteService(ctx context.Context, key string) error
 }
 
 type coreDNSProvider struct {
@@ -91,14 +91,13 @@ type Service struct {
 
 type etcdClient struct {
 	client *etcdcv3.Client
-	ctx    context.Context
 }
 
 var _ coreDNSClient = etcdClient{}
 
 // GetService return all Service records stored in etcd stored anywhere under the given key (recursively)
-func (c etcdClient) GetServices(prefix string) ([]*Service, error) {
-	ctx, cancel := context.WithTimeout(c.ctx, etcdTimeout)
+func (c etcdClient) GetServices(ctx context.Context, prefix string) ([]*Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, etcdTimeout)
 	defer cancel()
 
 	path := prefix
@@ -133,8 +132,8 @@ func (c etcdClient) GetServices(prefix string) ([]*Service, error) {
 }
 
 // SaveService persists service data into etcd
-func (c etcdClient) SaveService(service *Service) error {
-	ctx, cancel := context.WithTimeout(c.ctx, etcdTimeout)
+func (c etcdClient) SaveService(ctx context.Context, service *Service) error {
+	ctx, cancel := context.WithTimeout(ctx, etcdTimeout)
 	defer cancel()
 
 	value, err := json.Marshal(&service)
@@ -149,8 +148,8 @@ func (c etcdClient) SaveService(service *Service) error {
 }
 
 // DeleteService deletes service record from etcd
-func (c etcdClient) DeleteService(key string) error {
-	ctx, cancel := context.WithTimeout(c.ctx, etcdTimeout)
+func (c etcdClient) DeleteService(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, etcdTimeout)
 	defer cancel()
 
 	_, err := c.client.Delete(ctx, key, etcdcv3.WithPrefix())
@@ -241,7 +240,7 @@ func newETCDClient() (coreDNSClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	return etcdClient{c, context.Background()}, nil
+	return etcdClient{c}, nil
 }
 
 // NewCoreDNSProvider is a CoreDNS provider constructor
@@ -259,9 +258,9 @@ func NewCoreDNSProvider(domainFilter DomainFilter, dryRun bool) (Provider, error
 
 // Records returns all DNS records found in CoreDNS etcd backend. Depending on the record fields
 // it may be mapped to one or two records of type A, CNAME, TXT, A+TXT, CNAME+TXT
-func (p coreDNSProvider) Records() ([]*endpoint.Endpoint, error) {
+func (p coreDNSProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	var result []*endpoint.Endpoint
-	services, err := p.client.GetServices(coreDNSPrefix)
+	services, err := p.client.GetServices(ctx, coreDNSPrefix)
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +297,7 @@ func (p coreDNSProvider) Records() ([]*endpoint.Endpoint, error) {
 }
 
 // ApplyChanges stores changes back to etcd converting them to CoreDNS format and aggregating A/CNAME and TXT records
-func (p coreDNSProvider) ApplyChanges(changes *plan.Changes) error {
+func (p coreDNSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	grouped := map[string][]*endpoint.Endpoint{}
 	for _, ep := range changes.Create {
 		grouped[ep.DNSName] = append(grouped[ep.DNSName], ep)
@@ -361,7 +360,7 @@ func (p coreDNSProvider) ApplyChanges(changes *plan.Changes) error {
 		for _, service := range services {
 			log.Infof("Add/set key %s to Host=%s, Text=%s, TTL=%d", service.Key, service.Host, service.Text, service.TTL)
 			if !p.dryRun {
-				err := p.client.SaveService(&service)
+				err := p.client.SaveService(ctx, &service)
 				if err != nil {
 					return err
 				}
@@ -377,7 +376,7 @@ func (p coreDNSProvider) ApplyChanges(changes *plan.Changes) error {
 		key := etcdKeyFor(dnsName)
 		log.Infof("Delete key %s", key)
 		if !p.dryRun {
-			err := p.client.DeleteService(key)
+			err := p.client.DeleteService(ctx, key)
 			if err != nil {
 				return err
 			}
@@ -388,10 +387,14 @@ func (p coreDNSProvider) ApplyChanges(changes *plan.Changes) error {
 }
 
 func guessRecordType(target string) string {
-	if net.ParseIP(target) != nil {
-		return endpoint.RecordTypeA
+	ip := net.ParseIP(target)
+	if ip == nil {
+		return endpoint.RecordTypeCNAME
 	}
-	return endpoint.RecordTypeCNAME
+	if ip.To4() == nil {
+		return endpoint.RecordTypeAAAA
+	}
+	return endpoint.RecordTypeA
 }
 
 func etcdKeyFor(dnsName string) string {
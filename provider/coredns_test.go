@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -28,7 +29,7 @@ type fakeETCDClient struct {
 	services map[string]*Service
 }
 
-func (c fakeETCDClient) GetServices(prefix string) ([]*Service, error) {
+func (c fakeETCDClient) GetServices(ctx context.Context, prefix string) ([]*Service, error) {
 	var result []*Service
 	for key, value := range c.services {
 		if strings.HasPrefix(key, prefix) {
@@ -39,12 +40,12 @@ func (c fakeETCDClient) GetServices(prefix string) ([]*Service, error) {
 	return result, nil
 }
 
-func (c fakeETCDClient) SaveService(service *Service) error {
+func (c fakeETCDClient) SaveService(ctx context.Context, service *Service) error {
 	c.services[service.Key] = service
 	return nil
 }
 
-func (c fakeETCDClient) DeleteService(key string) error {
+func (c fakeETCDClient) DeleteService(ctx context.Context, key string) error {
 	delete(c.services, key)
 	return nil
 }
@@ -60,7 +61,36 @@ func TestAServiceTranslation(t *testing.T) {
 		},
 	}
 	provider := coreDNSProvider{client: client}
-	endpoints, err := provider.Records()
+	endpoints, err := provider.Records(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("got unexpected number of endpoints: %d", len(endpoints))
+	}
+	if endpoints[0].DNSName != expectedDNSName {
+		t.Errorf("got unexpected DNS name: %s != %s", endpoints[0].DNSName, expectedDNSName)
+	}
+	if endpoints[0].Targets[0] != expectedTarget {
+		t.Errorf("got unexpected DNS target: %s != %s", endpoints[0].Targets[0], expectedTarget)
+	}
+	if endpoints[0].RecordType != expectedRecordType {
+		t.Errorf("got unexpected DNS record type: %s != %s", endpoints[0].RecordType, expectedRecordType)
+	}
+}
+
+func TestAAAAServiceTranslation(t *testing.T) {
+	expectedTarget := "2001:db8::1"
+	expectedDNSName := "example.com"
+	expectedRecordType := endpoint.RecordTypeAAAA
+
+	client := fakeETCDClient{
+		map[string]*Service{
+			"/skydns/com/example": {Host: expectedTarget},
+		},
+	}
+	provider := coreDNSProvider{client: client}
+	endpoints, err := provider.Records(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -89,7 +119,7 @@ func TestCNAMEServiceTranslation(t *testing.T) {
 		},
 	}
 	provider := coreDNSProvider{client: client}
-	endpoints, err := provider.Records()
+	endpoints, err := provider.Records(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -118,7 +148,7 @@ func TestTXTServiceTranslation(t *testing.T) {
 		},
 	}
 	provider := coreDNSProvider{client: client}
-	endpoints, err := provider.Records()
+	endpoints, err := provider.Records(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -149,7 +179,7 @@ func TestAWithTXTServiceTranslation(t *testing.T) {
 		},
 	}
 	provider := coreDNSProvider{client: client}
-	endpoints, err := provider.Records()
+	endpoints, err := provider.Records(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -188,7 +218,7 @@ func TestCNAMEWithTXTServiceTranslation(t *testing.T) {
 		},
 	}
 	provider := coreDNSProvider{client: client}
-	endpoints, err := provider.Records()
+	endpoints, err := provider.Records(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -227,7 +257,7 @@ func TestCoreDNSApplyChanges(t *testing.T) {
 			endpoint.NewEndpoint("domain2.local", endpoint.RecordTypeCNAME, "site.local"),
 		},
 	}
-	coredns.ApplyChanges(changes1)
+	coredns.ApplyChanges(context.Background(), changes1)
 
 	expectedServices1 := map[string]*Service{
 		"/skydns/local/domain1": {Host: "5.5.5.5", Text: "string1"},
@@ -243,7 +273,7 @@ func TestCoreDNSApplyChanges(t *testing.T) {
 			endpoint.NewEndpoint("domain1.local", "A", "6.6.6.6"),
 		},
 	}
-	records, _ := coredns.Records()
+	records, _ := coredns.Records(context.Background())
 	for _, ep := range records {
 		if ep.DNSName == "domain1.local" {
 			changes2.UpdateOld = append(changes2.UpdateOld, ep)
@@ -275,7 +305,7 @@ func TestCoreDNSApplyChanges(t *testing.T) {
 }
 
 func applyServiceChanges(provider coreDNSProvider, changes *plan.Changes) {
-	records, _ := provider.Records()
+	records, _ := provider.Records(context.Background())
 	for _, col := range [][]*endpoint.Endpoint{changes.Create, changes.UpdateNew, changes.Delete} {
 		for _, record := range col {
 			for _, existingRecord := range records {
@@ -285,7 +315,7 @@ func applyServiceChanges(provider coreDNSProvider, changes *plan.Changes) {
 			}
 		}
 	}
-	provider.ApplyChanges(changes)
+	provider.ApplyChanges(context.Background(), changes)
 }
 
 func validateServices(services, expectedServices map[string]*Service, t *testing.T, step int) {
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -307,7 +308,7 @@ func (p designateProvider) getHostZoneID(hostname string, managedZones map[strin
 }
 
 // Records returns the list of records.
-func (p designateProvider) Records() ([]*endpoint.Endpoint, error) {
+func (p designateProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	var result []*endpoint.Endpoint
 	managedZones, err := p.getZones()
 	if err != nil {
@@ -379,7 +380,7 @@ func addEndpoint(ep *endpoint.Endpoint, recordSets map[string]*recordSet, delete
 }
 
 // ApplyChanges applies a given set of changes in a given zone.
-func (p designateProvider) ApplyChanges(changes *plan.Changes) error {
+func (p designateProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	managedZones, err := p.getZones()
 	if err != nil {
 		return err
@@ -398,6 +399,9 @@ func (p designateProvider) ApplyChanges(changes *plan.Changes) error {
 		addEndpoint(ep, recordSets, true)
 	}
 	for _, rs := range recordSets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err2 := p.upsertRecordSet(rs, managedZones); err == nil {
 			err = err2
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -25,7 +26,6 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/digitalocean/godo"
-	"github.com/digitalocean/godo/context"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/plan"
@@ -77,10 +77,10 @@ func NewDigitalOceanProvider(domainFilter DomainFilter, dryRun bool) (*DigitalOc
 }
 
 // Zones returns the list of hosted zones.
-func (p *DigitalOceanProvider) Zones() ([]godo.Domain, error) {
+func (p *DigitalOceanProvider) Zones(ctx context.Context) ([]godo.Domain, error) {
 	result := []godo.Domain{}
 
-	zones, err := p.fetchZones()
+	zones, err := p.fetchZones(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -94,15 +94,28 @@ func (p *DigitalOceanProvider) Zones() ([]godo.Domain, error) {
 	return result, nil
 }
 
+// ZoneNames returns the names of the domains, see ZoneNamesLister
+func (p *DigitalOceanProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, zone.Name)
+	}
+	return names, nil
+}
+
 // Records returns the list of records in a given zone.
-func (p *DigitalOceanProvider) Records() ([]*endpoint.Endpoint, error) {
-	zones, err := p.Zones()
+func (p *DigitalOceanProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return nil, err
 	}
 	endpoints := []*endpoint.Endpoint{}
 	for _, zone := range zones {
-		records, err := p.fetchRecords(zone.Name)
+		records, err := p.fetchRecords(ctx, zone.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -117,7 +130,7 @@ func (p *DigitalOceanProvider) Records() ([]*endpoint.Endpoint, error) {
 					name = zone.Name
 				}
 
-				endpoints = append(endpoints, endpoint.NewEndpoint(name, r.Type, r.Data))
+				endpoints = append(endpoints, endpoint.NewEndpoint(name, r.Type, digitalOceanRecordTarget(r)))
 			}
 		}
 	}
@@ -125,11 +138,11 @@ func (p *DigitalOceanProvider) Records() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
-func (p *DigitalOceanProvider) fetchRecords(zoneName string) ([]godo.DomainRecord, error) {
+func (p *DigitalOceanProvider) fetchRecords(ctx context.Context, zoneName string) ([]godo.DomainRecord, error) {
 	allRecords := []godo.DomainRecord{}
 	listOptions := &godo.ListOptions{}
 	for {
-		records, resp, err := p.Client.Records(context.TODO(), zoneName, listOptions)
+		records, resp, err := p.Client.Records(ctx, zoneName, listOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -150,11 +163,11 @@ func (p *DigitalOceanProvider) fetchRecords(zoneName string) ([]godo.DomainRecor
 	return allRecords, nil
 }
 
-func (p *DigitalOceanProvider) fetchZones() ([]godo.Domain, error) {
+func (p *DigitalOceanProvider) fetchZones(ctx context.Context) ([]godo.Domain, error) {
 	allZones := []godo.Domain{}
 	listOptions := &godo.ListOptions{}
 	for {
-		zones, resp, err := p.Client.List(context.TODO(), listOptions)
+		zones, resp, err := p.Client.List(ctx, listOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -176,13 +189,13 @@ func (p *DigitalOceanProvider) fetchZones() ([]godo.Domain, error) {
 }
 
 // submitChanges takes a zone and a collection of Changes and sends them as a single transaction.
-func (p *DigitalOceanProvider) submitChanges(changes []*DigitalOceanChange) error {
+func (p *DigitalOceanProvider) submitChanges(ctx context.Context, changes []*DigitalOceanChange) error {
 	// return early if there is nothing to change
 	if len(changes) == 0 {
 		return nil
 	}
 
-	zones, err := p.Zones()
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return err
 	}
@@ -190,7 +203,7 @@ func (p *DigitalOceanProvider) submitChanges(changes []*DigitalOceanChange) erro
 	// separate into per-zone change sets to be passed to the API.
 	changesByZone := digitalOceanChangesByZone(zones, changes)
 	for zoneName, changes := range changesByZone {
-		records, err := p.fetchRecords(zoneName)
+		records, err := p.fetchRecords(ctx, zoneName)
 		if err != nil {
 			log.Errorf("Failed to list records in the zone: %s", zoneName)
 			continue
@@ -218,39 +231,29 @@ func (p *DigitalOceanProvider) submitChanges(changes []*DigitalOceanChange) erro
 				change.ResourceRecordSet.Name = "@"
 			}
 
-			// for some reason the DO API requires the '.' at the end of "data" in case of CNAME request
+			// for some reason the DO API requires the '.' at the end of "data" in case of CNAME and SRV requests
 			// Example: {"type":"CNAME","name":"hello","data":"www.example.com."}
-			if change.ResourceRecordSet.Type == endpoint.RecordTypeCNAME {
+			if change.ResourceRecordSet.Type == endpoint.RecordTypeCNAME || change.ResourceRecordSet.Type == endpoint.RecordTypeSRV {
 				change.ResourceRecordSet.Data += "."
 			}
 
 			switch change.Action {
 			case DigitalOceanCreate:
-				_, _, err = p.Client.CreateRecord(context.TODO(), zoneName,
-					&godo.DomainRecordEditRequest{
-						Data: change.ResourceRecordSet.Data,
-						Name: change.ResourceRecordSet.Name,
-						Type: change.ResourceRecordSet.Type,
-						TTL:  change.ResourceRecordSet.TTL,
-					})
+				_, _, err = p.Client.CreateRecord(ctx, zoneName,
+					newDigitalOceanEditRequest(change.ResourceRecordSet))
 				if err != nil {
 					return err
 				}
 			case DigitalOceanDelete:
 				recordID := p.getRecordID(records, change.ResourceRecordSet)
-				_, err = p.Client.DeleteRecord(context.TODO(), zoneName, recordID)
+				_, err = p.Client.DeleteRecord(ctx, zoneName, recordID)
 				if err != nil {
 					return err
 				}
 			case DigitalOceanUpdate:
 				recordID := p.getRecordID(records, change.ResourceRecordSet)
-				_, _, err = p.Client.EditRecord(context.TODO(), zoneName, recordID,
-					&godo.DomainRecordEditRequest{
-						Data: change.ResourceRecordSet.Data,
-						Name: change.ResourceRecordSet.Name,
-						Type: change.ResourceRecordSet.Type,
-						TTL:  change.ResourceRecordSet.TTL,
-					})
+				_, _, err = p.Client.EditRecord(ctx, zoneName, recordID,
+					newDigitalOceanEditRequest(change.ResourceRecordSet))
 				if err != nil {
 					return err
 				}
@@ -261,14 +264,14 @@ func (p *DigitalOceanProvider) submitChanges(changes []*DigitalOceanChange) erro
 }
 
 // ApplyChanges applies a given set of changes in a given zone.
-func (p *DigitalOceanProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *DigitalOceanProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	combinedChanges := make([]*DigitalOceanChange, 0, len(changes.Create)+len(changes.UpdateNew)+len(changes.Delete))
 
 	combinedChanges = append(combinedChanges, newDigitalOceanChanges(DigitalOceanCreate, changes.Create)...)
 	combinedChanges = append(combinedChanges, newDigitalOceanChanges(DigitalOceanUpdate, changes.UpdateNew)...)
 	combinedChanges = append(combinedChanges, newDigitalOceanChanges(DigitalOceanDelete, changes.Delete)...)
 
-	return p.submitChanges(combinedChanges)
+	return p.submitChanges(ctx, combinedChanges)
 }
 
 // newDigitalOceanChanges returns a collection of Changes based on the given records and action.
@@ -282,25 +285,59 @@ func newDigitalOceanChanges(action string, endpoints []*endpoint.Endpoint) []*Di
 	return changes
 }
 
-func newDigitalOceanChange(action string, endpoint *endpoint.Endpoint) *DigitalOceanChange {
+func newDigitalOceanChange(action string, ep *endpoint.Endpoint) *DigitalOceanChange {
 	// no annotation results in a TTL of 0, default to 300 for consistency with other providers
 	var ttl = digitalOceanRecordTTL
-	if endpoint.RecordTTL.IsConfigured() {
-		ttl = int(endpoint.RecordTTL)
+	if ep.RecordTTL.IsConfigured() {
+		ttl = int(ep.RecordTTL)
 	}
 
 	change := &DigitalOceanChange{
 		Action: action,
 		ResourceRecordSet: godo.DomainRecord{
-			Name: endpoint.DNSName,
-			Type: endpoint.RecordType,
-			Data: endpoint.Targets[0],
+			Name: ep.DNSName,
+			Type: ep.RecordType,
+			Data: ep.Targets[0],
 			TTL:  ttl,
 		},
 	}
+	// SRV targets are split into the data and the priority, weight and port fields
+	if ep.RecordType == endpoint.RecordTypeSRV {
+		srv, err := endpoint.ParseSRVTarget(ep.Targets[0])
+		if err == nil {
+			change.ResourceRecordSet.Data = strings.TrimSuffix(srv.Target, ".")
+			change.ResourceRecordSet.Priority = int(srv.Priority)
+			change.ResourceRecordSet.Weight = int(srv.Weight)
+			change.ResourceRecordSet.Port = int(srv.Port)
+		} else {
+			log.Warnf("Using the SRV target as data: %v", err)
+		}
+	}
 	return change
 }
 
+// newDigitalOceanEditRequest returns the request to create or edit the given record
+func newDigitalOceanEditRequest(record godo.DomainRecord) *godo.DomainRecordEditRequest {
+	return &godo.DomainRecordEditRequest{
+		Data:     record.Data,
+		Name:     record.Name,
+		Type:     record.Type,
+		TTL:      record.TTL,
+		Priority: record.Priority,
+		Weight:   record.Weight,
+		Port:     record.Port,
+	}
+}
+
+// digitalOceanRecordTarget returns the target of an endpoint for the given record, SRV records
+// keep their priority, weight and port in separate fields
+func digitalOceanRecordTarget(record godo.DomainRecord) string {
+	if record.Type == endpoint.RecordTypeSRV {
+		return endpoint.NewSRVTarget(uint16(record.Priority), uint16(record.Weight), uint16(record.Port), strings.TrimSuffix(record.Data, ".")).String()
+	}
+	return record.Data
+}
+
 // getRecordID returns the ID from a record.
 // the ID is mandatory to update and delete records
 func (p *DigitalOceanProvider) getRecordID(records []godo.DomainRecord, record godo.DomainRecord) int {
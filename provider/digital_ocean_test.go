@@ -17,12 +17,13 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/digitalocean/godo"
-	"github.com/digitalocean/godo/context"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/plan"
@@ -114,6 +115,28 @@ func (m *mockDigitalOceanClient) Records(ctx context.Context, domain string, opt
 	}
 }
 
+// mockDigitalOceanRecordsClient keeps the records it creates and returns them
+type mockDigitalOceanRecordsClient struct {
+	mockDigitalOceanClient
+	records map[string][]godo.DomainRecord
+}
+
+func (m *mockDigitalOceanRecordsClient) CreateRecord(ctx context.Context, domain string, createRequest *godo.DomainRecordEditRequest) (*godo.DomainRecord, *godo.Response, error) {
+	record := godo.DomainRecord{
+		ID:   len(m.records[domain]) + 1,
+		Type: createRequest.Type,
+		Name: createRequest.Name,
+		Data: createRequest.Data,
+		TTL:  createRequest.TTL,
+	}
+	m.records[domain] = append(m.records[domain], record)
+	return &record, nil, nil
+}
+
+func (m *mockDigitalOceanRecordsClient) Records(ctx context.Context, domain string, opt *godo.ListOptions) ([]godo.DomainRecord, *godo.Response, error) {
+	return m.records[domain], nil, nil
+}
+
 type mockDigitalOceanListFail struct{}
 
 func (m *mockDigitalOceanListFail) List(context.Context, *godo.ListOptions) ([]godo.Domain, *godo.Response, error) {
@@ -408,13 +431,56 @@ func TestNewDigitalOceanChanges(t *testing.T) {
 	_ = newDigitalOceanChanges(action, endpoints)
 }
 
+func TestDigitalOceanSRVRecords(t *testing.T) {
+	ep := endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 20 5060 sip.example.com")
+	change := newDigitalOceanChange(DigitalOceanCreate, ep)
+
+	request := newDigitalOceanEditRequest(change.ResourceRecordSet)
+	assert.Equal(t, "sip.example.com", request.Data)
+	assert.Equal(t, 10, request.Priority)
+	assert.Equal(t, 20, request.Weight)
+	assert.Equal(t, 5060, request.Port)
+
+	record := godo.DomainRecord{Type: endpoint.RecordTypeSRV, Data: "sip.example.com.", Priority: 10, Weight: 20, Port: 5060}
+	assert.Equal(t, "10 20 5060 sip.example.com", digitalOceanRecordTarget(record))
+
+	a := godo.DomainRecord{Type: endpoint.RecordTypeA, Data: "1.2.3.4"}
+	assert.Equal(t, "1.2.3.4", digitalOceanRecordTarget(a))
+}
+
+func TestDigitalOceanTXTRecordsWithSeveralStrings(t *testing.T) {
+	labels := endpoint.NewLabels()
+	labels[endpoint.OwnerLabelKey] = "default"
+	labels[endpoint.ResourceLabelKey] = "ingress/default/" + strings.Repeat("x", 300)
+
+	client := &mockDigitalOceanRecordsClient{records: map[string][]godo.DomainRecord{}}
+	provider := &DigitalOceanProvider{Client: client}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("txt.bar.com", endpoint.RecordTypeTXT, labels.SerializeTXT()),
+		},
+	}
+	require.NoError(t, provider.ApplyChanges(context.Background(), changes))
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "txt.bar.com", records[0].DNSName)
+	require.Len(t, records[0].Targets, 1)
+
+	read, err := endpoint.NewLabelsFromString(records[0].Targets[0])
+	require.NoError(t, err)
+	assert.Equal(t, labels, read)
+}
+
 func TestDigitalOceanZones(t *testing.T) {
 	provider := &DigitalOceanProvider{
 		Client:       &mockDigitalOceanClient{},
 		domainFilter: NewDomainFilter([]string{"com"}),
 	}
 
-	zones, err := provider.Zones()
+	zones, err := provider.Zones(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -438,7 +504,7 @@ func TestDigitalOceanApplyChanges(t *testing.T) {
 	changes.Delete = []*endpoint.Endpoint{{DNSName: "foobar.ext-dns-test.bar.com", Targets: endpoint.Targets{"target"}}}
 	changes.UpdateOld = []*endpoint.Endpoint{{DNSName: "foobar.ext-dns-test.bar.de", Targets: endpoint.Targets{"target-old"}}}
 	changes.UpdateNew = []*endpoint.Endpoint{{DNSName: "foobar.ext-dns-test.foo.com", Targets: endpoint.Targets{"target-new"}, RecordType: "CNAME", RecordTTL: 100}}
-	err := provider.ApplyChanges(changes)
+	err := provider.ApplyChanges(context.Background(), changes)
 	if err != nil {
 		t.Errorf("should not fail, %s", err)
 	}
@@ -495,7 +561,7 @@ func TestDigitalOceanRecord(t *testing.T) {
 		Client: &mockDigitalOceanClient{},
 	}
 
-	records, err := provider.fetchRecords("example.com")
+	records, err := provider.fetchRecords(context.Background(), "example.com")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -511,14 +577,14 @@ func TestDigitalOceanAllRecords(t *testing.T) {
 		Client: &mockDigitalOceanClient{},
 	}
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	if err != nil {
 		t.Errorf("should not fail, %s", err)
 	}
 	require.Equal(t, 5, len(records))
 
 	provider.Client = &mockDigitalOceanRecordsFail{}
-	_, err = provider.Records()
+	_, err = provider.Records(context.Background())
 	if err == nil {
 		t.Errorf("expected to fail, %s", err)
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -154,8 +155,21 @@ func (p *dnsimpleProvider) Zones() (map[string]dnsimple.Zone, error) {
 	return zones, nil
 }
 
+// ZoneNames returns the names of the zones, see ZoneNamesLister
+func (p *dnsimpleProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.Zones()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, zone.Name)
+	}
+	return names, nil
+}
+
 // Records retuns a list of endpoints in a given zone
-func (p *dnsimpleProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
+func (p *dnsimpleProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
 	zones, err := p.Zones()
 	if err != nil {
 		return nil, err
@@ -171,12 +185,12 @@ func (p *dnsimpleProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 			}
 			for _, record := range records.Data {
 				switch record.Type {
-				case "A", "CNAME", "TXT":
+				case "A", "CNAME", "TXT", "SRV":
 					break
 				default:
 					continue
 				}
-				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(record.Name+"."+record.ZoneID, record.Type, endpoint.TTL(record.TTL), record.Content))
+				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(record.Name+"."+record.ZoneID, record.Type, endpoint.TTL(record.TTL), dnsimpleRecordTarget(record)))
 			}
 			page++
 			if page > records.Pagination.TotalPages {
@@ -203,9 +217,31 @@ func newDnsimpleChange(action string, e *endpoint.Endpoint) *dnsimpleChange {
 			TTL:     ttl,
 		},
 	}
+	// the priority of SRV targets is a field of its own, the content holds the weight, port and target
+	if e.RecordType == endpoint.RecordTypeSRV {
+		srv, err := endpoint.ParseSRVTarget(e.Targets[0])
+		if err == nil {
+			change.ResourceRecordSet.Content = fmt.Sprintf("%d %d %s", srv.Weight, srv.Port, strings.TrimSuffix(srv.Target, "."))
+			change.ResourceRecordSet.Priority = int(srv.Priority)
+		} else {
+			log.Warnf("Using the SRV target as content: %v", err)
+		}
+	}
 	return change
 }
 
+// dnsimpleRecordTarget returns the target of an endpoint for the given record
+func dnsimpleRecordTarget(record dnsimple.ZoneRecord) string {
+	if record.Type == endpoint.RecordTypeSRV {
+		srv, err := endpoint.ParseSRVTarget(fmt.Sprintf("%d %s", record.Priority, record.Content))
+		if err == nil {
+			return strings.TrimSuffix(srv.String(), ".")
+		}
+		log.Warnf("Using the content of SRV record %s as target: %v", record.Name, err)
+	}
+	return record.Content
+}
+
 // newDnsimpleChanges returns a slice of changes based on given action and record
 func newDnsimpleChanges(action string, endpoints []*endpoint.Endpoint) []*dnsimpleChange {
 	changes := make([]*dnsimpleChange, 0, len(endpoints))
@@ -216,7 +252,7 @@ func newDnsimpleChanges(action string, endpoints []*endpoint.Endpoint) []*dnsimp
 }
 
 // submitChanges takes a zone and a collection of changes and makes all changes from the collection
-func (p *dnsimpleProvider) submitChanges(changes []*dnsimpleChange) error {
+func (p *dnsimpleProvider) submitChanges(ctx context.Context, changes []*dnsimpleChange) error {
 	if len(changes) == 0 {
 		log.Infof("All records are already up to date")
 		return nil
@@ -226,6 +262,9 @@ func (p *dnsimpleProvider) submitChanges(changes []*dnsimpleChange) error {
 		return err
 	}
 	for _, change := range changes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		zone := dnsimpleSuitableZone(change.ResourceRecordSet.Name, zones)
 		if zone == nil {
 			log.Debugf("Skipping record %s because no hosted zone matching record DNS Name was detected ", change.ResourceRecordSet.Name)
@@ -306,27 +345,27 @@ func dnsimpleSuitableZone(hostname string, zones map[string]dnsimple.Zone) *dnsi
 }
 
 // CreateRecords creates records for a given slice of endpoints
-func (p *dnsimpleProvider) CreateRecords(endpoints []*endpoint.Endpoint) error {
-	return p.submitChanges(newDnsimpleChanges(dnsimpleCreate, endpoints))
+func (p *dnsimpleProvider) CreateRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
+	return p.submitChanges(ctx, newDnsimpleChanges(dnsimpleCreate, endpoints))
 }
 
 // DeleteRecords deletes records for a given slice of endpoints
-func (p *dnsimpleProvider) DeleteRecords(endpoints []*endpoint.Endpoint) error {
-	return p.submitChanges(newDnsimpleChanges(dnsimpleDelete, endpoints))
+func (p *dnsimpleProvider) DeleteRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
+	return p.submitChanges(ctx, newDnsimpleChanges(dnsimpleDelete, endpoints))
 }
 
 // UpdateRecords updates records for a given slice of endpoints
-func (p *dnsimpleProvider) UpdateRecords(endpoints []*endpoint.Endpoint) error {
-	return p.submitChanges(newDnsimpleChanges(dnsimpleUpdate, endpoints))
+func (p *dnsimpleProvider) UpdateRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
+	return p.submitChanges(ctx, newDnsimpleChanges(dnsimpleUpdate, endpoints))
 }
 
 // ApplyChanges applies a given set of changes
-func (p *dnsimpleProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *dnsimpleProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	combinedChanges := make([]*dnsimpleChange, 0, len(changes.Create)+len(changes.UpdateNew)+len(changes.Delete))
 
 	combinedChanges = append(combinedChanges, newDnsimpleChanges(dnsimpleCreate, changes.Create)...)
 	combinedChanges = append(combinedChanges, newDnsimpleChanges(dnsimpleUpdate, changes.UpdateNew)...)
 	combinedChanges = append(combinedChanges, newDnsimpleChanges(dnsimpleDelete, changes.Delete)...)
 
-	return p.submitChanges(combinedChanges)
+	return p.submitChanges(ctx, combinedChanges)
 }
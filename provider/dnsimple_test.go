@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -142,12 +143,12 @@ func testDnsimpleProviderZones(t *testing.T) {
 
 func testDnsimpleProviderRecords(t *testing.T) {
 	mockProvider.accountID = "1"
-	result, err := mockProvider.Records()
+	result, err := mockProvider.Records(context.Background())
 	assert.Nil(t, err)
 	assert.Equal(t, len(dnsimpleListRecordsResponse.Data), len(result))
 
 	mockProvider.accountID = "2"
-	result, err = mockProvider.Records()
+	result, err = mockProvider.Records(context.Background())
 	assert.NotNil(t, err)
 }
 func testDnsimpleProviderApplyChanges(t *testing.T) {
@@ -160,7 +161,7 @@ func testDnsimpleProviderApplyChanges(t *testing.T) {
 	changes.UpdateNew = []*endpoint.Endpoint{{DNSName: "example.example.com", Targets: endpoint.Targets{"target"}, RecordType: endpoint.RecordTypeCNAME}}
 
 	mockProvider.accountID = "1"
-	err := mockProvider.ApplyChanges(changes)
+	err := mockProvider.ApplyChanges(context.Background(), changes)
 	if err != nil {
 		t.Errorf("Failed to apply changes: %v", err)
 	}
@@ -173,7 +174,7 @@ func testDnsimpleProviderApplyChangesSkipsUnknown(t *testing.T) {
 	}
 
 	mockProvider.accountID = "1"
-	err := mockProvider.ApplyChanges(changes)
+	err := mockProvider.ApplyChanges(context.Background(), changes)
 	if err != nil {
 		t.Errorf("Failed to ignore unknown zones: %v", err)
 	}
@@ -200,6 +201,16 @@ func TestNewDnsimpleProvider(t *testing.T) {
 	}
 }
 
+func TestDnsimpleSRVRecords(t *testing.T) {
+	ep := endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 20 5060 sip.example.com")
+	change := newDnsimpleChange(dnsimpleCreate, ep)
+	assert.Equal(t, "20 5060 sip.example.com", change.ResourceRecordSet.Content)
+	assert.Equal(t, 10, change.ResourceRecordSet.Priority)
+
+	assert.Equal(t, "10 20 5060 sip.example.com", dnsimpleRecordTarget(change.ResourceRecordSet))
+	assert.Equal(t, "127.0.0.1", dnsimpleRecordTarget(dnsimple.ZoneRecord{Type: "A", Content: "127.0.0.1"}))
+}
+
 func testDnsimpleGetRecordID(t *testing.T) {
 	mockProvider.accountID = "1"
 	result, err := mockProvider.GetRecordID("example.com", "example")
@@ -18,6 +18,8 @@ package provider
 
 import (
 	"strings"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
 )
 
 // DomainFilter holds a lists of valid domain names
@@ -32,7 +34,7 @@ func NewDomainFilter(domainFilters []string) DomainFilter {
 	// user can define filter domains either with trailing dot or without, we remove all trailing periods from
 	// the internal representation
 	for i, domain := range domainFilters {
-		filters[i] = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+		filters[i] = endpoint.NormalizeDNSName(strings.TrimSuffix(strings.TrimSpace(domain), "."))
 	}
 
 	return DomainFilter{filters}
@@ -46,7 +48,7 @@ func (df DomainFilter) Match(domain string) bool {
 	}
 
 	for _, filter := range df.filters {
-		strippedDomain := strings.TrimSuffix(domain, ".")
+		strippedDomain := endpoint.NormalizeDNSName(strings.TrimSuffix(domain, "."))
 
 		if filter == "" {
 			return true
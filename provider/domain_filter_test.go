@@ -129,6 +129,21 @@ var domainFilterTests = []domainFilterTest{
 		[]string{"example.org", "test.example.org", "foo.test.example.org"},
 		true,
 	},
+	{
+		[]string{"bücher.example.org"},
+		[]string{"xn--bcher-kva.example.org", "test.xn--bcher-kva.example.org", "bücher.example.org", "test.bücher.example.org"},
+		true,
+	},
+	{
+		[]string{"xn--bcher-kva.example.org"},
+		[]string{"bücher.example.org", "test.bücher.example.org"},
+		true,
+	},
+	{
+		[]string{".bücher.example.org"},
+		[]string{"test.xn--bcher-kva.example.org"},
+		true,
+	},
 }
 
 func TestDomainFilterMatch(t *testing.T) {
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -580,7 +581,7 @@ func (d *dynProviderState) commit(client *dynect.Client) error {
 // Records makes on average C + 2*Z  requests (Z = number of zones): 1 login + 1 fetchAllRecords
 // A cache is used to avoid querying for every single record found. C is proportional to the number
 // of expired/changed records
-func (d *dynProviderState) Records() ([]*endpoint.Endpoint, error) {
+func (d *dynProviderState) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	client, err := d.login()
 	if err != nil {
 		return nil, err
@@ -638,7 +639,7 @@ func (d *dynProviderState) Records() ([]*endpoint.Endpoint, error) {
 
 // this method does C + 2*Z requests: C=total number of changes, Z = number of
 // affected zones (1 login + 1 commit)
-func (d *dynProviderState) ApplyChanges(changes *plan.Changes) error {
+func (d *dynProviderState) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	log.Debugf("Processing chages: %+v", changes)
 
 	if d.DryRun {
@@ -659,6 +660,9 @@ func (d *dynProviderState) ApplyChanges(changes *plan.Changes) error {
 	needsCommit := false
 
 	for _, ep := range changes.Delete {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err := d.deleteRecord(client, ep)
 		if err != nil {
 			errs = append(errs, err)
@@ -668,6 +672,9 @@ func (d *dynProviderState) ApplyChanges(changes *plan.Changes) error {
 	}
 
 	for _, ep := range changes.Create {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err := d.createRecord(client, ep)
 		if err != nil {
 			errs = append(errs, err)
@@ -679,6 +686,9 @@ func (d *dynProviderState) ApplyChanges(changes *plan.Changes) error {
 	updates := merge(changes.UpdateOld, changes.UpdateNew)
 	log.Debugf("Updates after merging: %+v", updates)
 	for _, ep := range updates {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err := d.replaceRecord(client, ep)
 		if err != nil {
 			errs = append(errs, err)
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"strings"
 
 	"github.com/exoscale/egoscale"
@@ -81,7 +82,7 @@ func (ep *ExoscaleProvider) getZones() (map[int64]string, error) {
 }
 
 // ApplyChanges simply modifies DNS via exoscale API
-func (ep *ExoscaleProvider) ApplyChanges(changes *plan.Changes) error {
+func (ep *ExoscaleProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	ep.OnApplyChanges(changes)
 
 	if ep.dryRun {
@@ -97,6 +98,9 @@ func (ep *ExoscaleProvider) ApplyChanges(changes *plan.Changes) error {
 	}
 
 	for _, epoint := range changes.Create {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if ep.domain.Match(epoint.DNSName) {
 			if zoneID, name := ep.filter.EndpointZoneID(epoint, zones); zoneID != 0 {
 				rec := egoscale.DNSRecord{
@@ -113,6 +117,9 @@ func (ep *ExoscaleProvider) ApplyChanges(changes *plan.Changes) error {
 		}
 	}
 	for _, epoint := range changes.UpdateNew {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if ep.domain.Match(epoint.DNSName) {
 			if zoneID, name := ep.filter.EndpointZoneID(epoint, zones); zoneID != 0 {
 				records, err := ep.client.GetRecords(zones[zoneID])
@@ -147,6 +154,9 @@ func (ep *ExoscaleProvider) ApplyChanges(changes *plan.Changes) error {
 	}
 
 	for _, epoint := range changes.Delete {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if ep.domain.Match(epoint.DNSName) {
 			if zoneID, name := ep.filter.EndpointZoneID(epoint, zones); zoneID != 0 {
 				records, err := ep.client.GetRecords(zones[zoneID])
@@ -170,7 +180,7 @@ func (ep *ExoscaleProvider) ApplyChanges(changes *plan.Changes) error {
 }
 
 // Records returns the list of endpoints
-func (ep *ExoscaleProvider) Records() ([]*endpoint.Endpoint, error) {
+func (ep *ExoscaleProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	endpoints := make([]*endpoint.Endpoint, 0)
 
 	domains, err := ep.client.GetDomains()
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -105,7 +106,7 @@ func contains(arr []*endpoint.Endpoint, name string) bool {
 func TestExoscaleGetRecords(t *testing.T) {
 	provider := NewExoscaleProviderWithClient("", "", "", NewExoscaleClientStub(), false)
 
-	if recs, err := provider.Records(); err == nil {
+	if recs, err := provider.Records(context.Background()); err == nil {
 		assert.Equal(t, 3, len(recs))
 		assert.True(t, contains(recs, "v1.foo.com"))
 		assert.True(t, contains(recs, "v2.bar.com"))
@@ -173,7 +174,7 @@ func TestExoscaleApplyChanges(t *testing.T) {
 	createExoscale = make([]createRecordExoscale, 0)
 	deleteExoscale = make([]deleteRecordExoscale, 0)
 
-	provider.ApplyChanges(plan)
+	provider.ApplyChanges(context.Background(), plan)
 
 	assert.Equal(t, 1, len(createExoscale))
 	assert.Equal(t, "foo.com", createExoscale[0].name)
@@ -155,7 +155,7 @@ func NewGoogleProvider(project string, domainFilter DomainFilter, zoneIDFilter Z
 }
 
 // Zones returns the list of hosted zones.
-func (p *GoogleProvider) Zones() (map[string]*dns.ManagedZone, error) {
+func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone, error) {
 	zones := make(map[string]*dns.ManagedZone)
 
 	f := func(resp *dns.ManagedZonesListResponse) error {
@@ -172,7 +172,7 @@ func (p *GoogleProvider) Zones() (map[string]*dns.ManagedZone, error) {
 	}
 
 	log.Debugf("Matching zones against domain filters: %v", p.domainFilter.filters)
-	if err := p.managedZonesClient.List(p.project).Pages(context.TODO(), f); err != nil {
+	if err := p.managedZonesClient.List(p.project).Pages(ctx, f); err != nil {
 		return nil, err
 	}
 
@@ -191,16 +191,30 @@ func (p *GoogleProvider) Zones() (map[string]*dns.ManagedZone, error) {
 	return zones, nil
 }
 
+// ZoneNames returns the names of the managed zones, see ZoneNamesLister
+func (p *GoogleProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, zone.DnsName)
+	}
+	return names, nil
+}
+
 // Records returns the list of records in all relevant zones.
-func (p *GoogleProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
-	zones, err := p.Zones()
+func (p *GoogleProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var zoneName string
 	f := func(resp *dns.ResourceRecordSetsListResponse) error {
 		for _, r := range resp.Rrsets {
-			if !supportedRecordType(r.Type) {
+			if !supportedExtendedRecordType(r.Type) || zoneApexNS(r.Type, r.Name, zoneName) {
 				continue
 			}
 			endpoints = append(endpoints, endpoint.NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.Ttl), r.Rrdatas...))
@@ -210,7 +224,8 @@ func (p *GoogleProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 	}
 
 	for _, z := range zones {
-		if err := p.resourceRecordSetsClient.List(p.project, z.Name).Pages(context.TODO(), f); err != nil {
+		zoneName = z.DnsName
+		if err := p.resourceRecordSetsClient.List(p.project, z.Name).Pages(ctx, f); err != nil {
 			return nil, err
 		}
 	}
@@ -219,35 +234,35 @@ func (p *GoogleProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 }
 
 // CreateRecords creates a given set of DNS records in the given hosted zone.
-func (p *GoogleProvider) CreateRecords(endpoints []*endpoint.Endpoint) error {
+func (p *GoogleProvider) CreateRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
 	change := &dns.Change{}
 
 	change.Additions = append(change.Additions, p.newFilteredRecords(endpoints)...)
 
-	return p.submitChange(change)
+	return p.submitChange(ctx, change)
 }
 
 // UpdateRecords updates a given set of old records to a new set of records in a given hosted zone.
-func (p *GoogleProvider) UpdateRecords(records, oldRecords []*endpoint.Endpoint) error {
+func (p *GoogleProvider) UpdateRecords(ctx context.Context, records, oldRecords []*endpoint.Endpoint) error {
 	change := &dns.Change{}
 
 	change.Additions = append(change.Additions, p.newFilteredRecords(records)...)
 	change.Deletions = append(change.Deletions, p.newFilteredRecords(oldRecords)...)
 
-	return p.submitChange(change)
+	return p.submitChange(ctx, change)
 }
 
 // DeleteRecords deletes a given set of DNS records in a given zone.
-func (p *GoogleProvider) DeleteRecords(endpoints []*endpoint.Endpoint) error {
+func (p *GoogleProvider) DeleteRecords(ctx context.Context, endpoints []*endpoint.Endpoint) error {
 	change := &dns.Change{}
 
 	change.Deletions = append(change.Deletions, p.newFilteredRecords(endpoints)...)
 
-	return p.submitChange(change)
+	return p.submitChange(ctx, change)
 }
 
 // ApplyChanges applies a given set of changes in a given zone.
-func (p *GoogleProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *GoogleProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	change := &dns.Change{}
 
 	change.Additions = append(change.Additions, p.newFilteredRecords(changes.Create)...)
@@ -257,7 +272,7 @@ func (p *GoogleProvider) ApplyChanges(changes *plan.Changes) error {
 
 	change.Deletions = append(change.Deletions, p.newFilteredRecords(changes.Delete)...)
 
-	return p.submitChange(change)
+	return p.submitChange(ctx, change)
 }
 
 // newFilteredRecords returns a collection of RecordSets based on the given endpoints and domainFilter.
@@ -274,13 +289,13 @@ func (p *GoogleProvider) newFilteredRecords(endpoints []*endpoint.Endpoint) []*d
 }
 
 // submitChange takes a zone and a Change and sends it to Google.
-func (p *GoogleProvider) submitChange(change *dns.Change) error {
+func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) error {
 	if len(change.Additions) == 0 && len(change.Deletions) == 0 {
 		log.Info("All records are already up to date")
 		return nil
 	}
 
-	zones, err := p.Zones()
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return err
 	}
@@ -355,8 +370,20 @@ func newRecord(ep *endpoint.Endpoint) *dns.ResourceRecordSet {
 	// way we can use it has is here and trim it off if it exists when necessary.
 	targets := make([]string, len(ep.Targets))
 	copy(targets, []string(ep.Targets))
-	if ep.RecordType == endpoint.RecordTypeCNAME {
+	switch ep.RecordType {
+	case endpoint.RecordTypeCNAME:
 		targets[0] = ensureTrailingDot(targets[0])
+	case endpoint.RecordTypeNS, endpoint.RecordTypePTR:
+		for i := range targets {
+			targets[i] = ensureTrailingDot(targets[i])
+		}
+	case endpoint.RecordTypeMX:
+		for i, target := range targets {
+			if mx, err := endpoint.ParseMXTarget(target); err == nil {
+				mx.Exchange = ensureTrailingDot(mx.Exchange)
+				targets[i] = mx.String()
+			}
+		}
 	}
 
 	// no annotation results in a Ttl of 0, default to 300 for backwards-compatability
@@ -195,7 +195,7 @@ func hasTrailingDot(target string) bool {
 func TestGoogleZones(t *testing.T) {
 	provider := newGoogleProvider(t, NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{})
 
-	zones, err := provider.Zones()
+	zones, err := provider.Zones(context.Background())
 	require.NoError(t, err)
 
 	validateZones(t, zones, map[string]*dns.ManagedZone{
@@ -214,7 +214,7 @@ func TestGoogleRecords(t *testing.T) {
 
 	provider := newGoogleProvider(t, NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), NewZoneIDFilter([]string{""}), false, originalEndpoints)
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, originalEndpoints)
@@ -255,9 +255,9 @@ func TestGoogleRecordsFilter(t *testing.T) {
 		endpoint.NewEndpoint("filter-delete-test.zone-3.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "4.2.2.2"),
 	}
 
-	require.NoError(t, provider.CreateRecords(ignoredEndpoints))
+	require.NoError(t, provider.CreateRecords(context.Background(), ignoredEndpoints))
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	// assert that due to filtering no changes were made.
@@ -273,9 +273,9 @@ func TestGoogleCreateRecords(t *testing.T) {
 		endpoint.NewEndpoint("create-test-cname.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, "foo.elb.amazonaws.com"),
 	}
 
-	require.NoError(t, provider.CreateRecords(records))
+	require.NoError(t, provider.CreateRecords(context.Background(), records))
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, []*endpoint.Endpoint{
@@ -298,9 +298,9 @@ func TestGoogleUpdateRecords(t *testing.T) {
 		endpoint.NewEndpoint("update-test-cname.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, "bar.elb.amazonaws.com"),
 	}
 
-	require.NoError(t, provider.UpdateRecords(updatedRecords, currentRecords))
+	require.NoError(t, provider.UpdateRecords(context.Background(), updatedRecords, currentRecords))
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, []*endpoint.Endpoint{
@@ -319,9 +319,9 @@ func TestGoogleDeleteRecords(t *testing.T) {
 
 	provider := newGoogleProvider(t, NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), NewZoneIDFilter([]string{""}), false, originalEndpoints)
 
-	require.NoError(t, provider.DeleteRecords(originalEndpoints))
+	require.NoError(t, provider.DeleteRecords(context.Background(), originalEndpoints))
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, []*endpoint.Endpoint{})
@@ -387,9 +387,9 @@ func TestGoogleApplyChanges(t *testing.T) {
 		Delete:    deleteRecords,
 	}
 
-	require.NoError(t, provider.ApplyChanges(changes))
+	require.NoError(t, provider.ApplyChanges(context.Background(), changes))
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, []*endpoint.Endpoint{
@@ -444,9 +444,9 @@ func TestGoogleApplyChangesDryRun(t *testing.T) {
 		Delete:    deleteRecords,
 	}
 
-	require.NoError(t, provider.ApplyChanges(changes))
+	require.NoError(t, provider.ApplyChanges(context.Background(), changes))
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, originalEndpoints)
@@ -454,7 +454,7 @@ func TestGoogleApplyChangesDryRun(t *testing.T) {
 
 func TestGoogleApplyChangesEmpty(t *testing.T) {
 	provider := newGoogleProvider(t, NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{})
-	assert.NoError(t, provider.ApplyChanges(&plan.Changes{}))
+	assert.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{}))
 }
 
 func TestNewFilteredRecords(t *testing.T) {
@@ -480,6 +480,24 @@ func TestNewFilteredRecords(t *testing.T) {
 	})
 }
 
+func TestNewFilteredRecordsExtendedTypes(t *testing.T) {
+	provider := newGoogleProvider(t, NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{})
+
+	records := provider.newFilteredRecords([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeMX, 300, "10 mail.zone-1.ext-dns-test-2.gcp.zalan.do", "20 backup.zone-1.ext-dns-test-2.gcp.zalan.do."),
+		endpoint.NewEndpointWithTTL("sub.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeNS, 300, "ns1.example.org", "ns2.example.org"),
+		endpoint.NewEndpointWithTTL("4.3.2.1.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypePTR, 300, "host.example.org"),
+		endpoint.NewEndpointWithTTL("zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCAA, 300, "0 issue \"letsencrypt.org\""),
+	})
+
+	validateChangeRecords(t, records, []*dns.ResourceRecordSet{
+		{Name: "zone-1.ext-dns-test-2.gcp.zalan.do.", Rrdatas: []string{"10 mail.zone-1.ext-dns-test-2.gcp.zalan.do.", "20 backup.zone-1.ext-dns-test-2.gcp.zalan.do."}, Type: "MX", Ttl: 300},
+		{Name: "sub.zone-1.ext-dns-test-2.gcp.zalan.do.", Rrdatas: []string{"ns1.example.org.", "ns2.example.org."}, Type: "NS", Ttl: 300},
+		{Name: "4.3.2.1.zone-1.ext-dns-test-2.gcp.zalan.do.", Rrdatas: []string{"host.example.org."}, Type: "PTR", Ttl: 300},
+		{Name: "zone-1.ext-dns-test-2.gcp.zalan.do.", Rrdatas: []string{"0 issue \"letsencrypt.org\""}, Type: "CAA", Ttl: 300},
+	})
+}
+
 func TestSeparateChanges(t *testing.T) {
 	change := &dns.Change{
 		Additions: []*dns.ResourceRecordSet{
@@ -616,14 +634,14 @@ func setupGoogleRecords(t *testing.T, provider *GoogleProvider, endpoints []*end
 	clearGoogleRecords(t, provider, "zone-2-ext-dns-test-2-gcp-zalan-do")
 	clearGoogleRecords(t, provider, "zone-3-ext-dns-test-2-gcp-zalan-do")
 
-	records, err := provider.Records()
+	records, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, []*endpoint.Endpoint{})
 
-	require.NoError(t, provider.CreateRecords(endpoints))
+	require.NoError(t, provider.CreateRecords(context.Background(), endpoints))
 
-	records, err = provider.Records()
+	records, err = provider.Records(context.Background())
 	require.NoError(t, err)
 
 	validateEndpoints(t, records, endpoints)
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -93,7 +94,7 @@ func NewInfobloxProvider(infobloxConfig InfobloxConfig) (*InfobloxProvider, erro
 }
 
 // Records gets the current records.
-func (p *InfobloxProvider) Records() (endpoints []*endpoint.Endpoint, err error) {
+func (p *InfobloxProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, err error) {
 	zones, err := p.zones()
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch zones: %s", err)
@@ -170,16 +171,17 @@ func (p *InfobloxProvider) Records() (endpoints []*endpoint.Endpoint, err error)
 }
 
 // ApplyChanges applies the given changes.
-func (p *InfobloxProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *InfobloxProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	zones, err := p.zones()
 	if err != nil {
 		return err
 	}
 
 	created, deleted := p.mapChanges(zones, changes)
-	p.deleteRecords(deleted)
-	p.createRecords(created)
-	return nil
+	if err := p.deleteRecords(ctx, deleted); err != nil {
+		return err
+	}
+	return p.createRecords(ctx, created)
 }
 
 func (p *InfobloxProvider) zones() ([]ibclient.ZoneAuth, error) {
@@ -206,6 +208,19 @@ func (p *InfobloxProvider) zones() ([]ibclient.ZoneAuth, error) {
 	return result, nil
 }
 
+// ZoneNames returns the names of the authoritative zones, see ZoneNamesLister
+func (p *InfobloxProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.zones()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, zone.Fqdn)
+	}
+	return names, nil
+}
+
 type infobloxChangeMap map[string][]*endpoint.Endpoint
 
 func (p *InfobloxProvider) mapChanges(zones []ibclient.ZoneAuth, changes *plan.Changes) (infobloxChangeMap, infobloxChangeMap) {
@@ -318,9 +333,12 @@ func (p *InfobloxProvider) recordSet(ep *endpoint.Endpoint, getObject bool) (rec
 	return
 }
 
-func (p *InfobloxProvider) createRecords(created infobloxChangeMap) {
+func (p *InfobloxProvider) createRecords(ctx context.Context, created infobloxChangeMap) error {
 	for zone, endpoints := range created {
 		for _, ep := range endpoints {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if p.dryRun {
 				logrus.Infof(
 					"Would create %s record named '%s' to '%s' for Infoblox DNS zone '%s'.",
@@ -365,12 +383,16 @@ func (p *InfobloxProvider) createRecords(created infobloxChangeMap) {
 			}
 		}
 	}
+	return nil
 }
 
-func (p *InfobloxProvider) deleteRecords(deleted infobloxChangeMap) {
+func (p *InfobloxProvider) deleteRecords(ctx context.Context, deleted infobloxChangeMap) error {
 	// Delete records first
 	for zone, endpoints := range deleted {
 		for _, ep := range endpoints {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if p.dryRun {
 				logrus.Infof("Would delete %s record named '%s' for Infoblox DNS zone '%s'.", ep.RecordType, ep.DNSName, zone)
 			} else {
@@ -413,6 +435,7 @@ func (p *InfobloxProvider) deleteRecords(deleted infobloxChangeMap) {
 			}
 		}
 	}
+	return nil
 }
 
 func lookupEnvAtoi(key string, fallback int) (i int) {
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -117,8 +118,17 @@ func (im *InMemoryProvider) Zones() map[string]string {
 	return im.filter.Zones(im.client.Zones())
 }
 
+// ZoneNames returns the names of the filtered zones, see ZoneNamesLister
+func (im *InMemoryProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	names := []string{}
+	for zone := range im.Zones() {
+		names = append(names, zone)
+	}
+	return names, nil
+}
+
 // Records returns the list of endpoints
-func (im *InMemoryProvider) Records() ([]*endpoint.Endpoint, error) {
+func (im *InMemoryProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	defer im.OnRecords()
 
 	endpoints := make([]*endpoint.Endpoint, 0)
@@ -142,7 +152,7 @@ func (im *InMemoryProvider) Records() ([]*endpoint.Endpoint, error) {
 // create record - record should not exist
 // update/delete record - record should exist
 // create/update/delete lists should not have overlapping records
-func (im *InMemoryProvider) ApplyChanges(changes *plan.Changes) error {
+func (im *InMemoryProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	defer im.OnApplyChanges(changes)
 
 	perZoneChanges := map[string]*plan.Changes{}
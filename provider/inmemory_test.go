@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"testing"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -209,7 +210,7 @@ func testInMemoryRecords(t *testing.T) {
 			im.client = c
 			f := filter{domain: ti.zone}
 			im.filter = &f
-			records, err := im.Records()
+			records, err := im.Records(context.Background())
 			if ti.expectError {
 				assert.Nil(t, records)
 				assert.EqualError(t, err, ErrZoneNotFound.Error())
@@ -773,7 +774,7 @@ func testInMemoryApplyChanges(t *testing.T) {
 			c.zones = getInitData()
 			im.client = c
 
-			err := im.ApplyChanges(ti.changes)
+			err := im.ApplyChanges(context.Background(), ti.changes)
 			if ti.expectError {
 				assert.Error(t, err)
 			} else {
@@ -102,8 +102,8 @@ func NewLinodeProvider(domainFilter DomainFilter, dryRun bool, appVersion string
 }
 
 // Zones returns the list of hosted zones.
-func (p *LinodeProvider) Zones() ([]*linodego.Domain, error) {
-	zones, err := p.fetchZones()
+func (p *LinodeProvider) Zones(ctx context.Context) ([]*linodego.Domain, error) {
+	zones, err := p.fetchZones(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -111,9 +111,22 @@ func (p *LinodeProvider) Zones() ([]*linodego.Domain, error) {
 	return zones, nil
 }
 
+// ZoneNames returns the names of the domains, see ZoneNamesLister
+func (p *LinodeProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, zone.Domain)
+	}
+	return names, nil
+}
+
 // Records returns the list of records in a given zone.
-func (p *LinodeProvider) Records() ([]*endpoint.Endpoint, error) {
-	zones, err := p.Zones()
+func (p *LinodeProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	zones, err := p.Zones(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +134,7 @@ func (p *LinodeProvider) Records() ([]*endpoint.Endpoint, error) {
 	var endpoints []*endpoint.Endpoint
 
 	for _, zone := range zones {
-		records, err := p.fetchRecords(zone.ID)
+		records, err := p.fetchRecords(ctx, zone.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -136,7 +149,7 @@ func (p *LinodeProvider) Records() ([]*endpoint.Endpoint, error) {
 					name = zone.Domain
 				}
 
-				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(name, string(r.Type), endpoint.TTL(r.TTLSec), r.Target))
+				endpoints = append(endpoints, endpoint.NewEndpointWithTTL(name, string(r.Type), endpoint.TTL(r.TTLSec), getRecordTarget(r)))
 			}
 		}
 	}
@@ -144,8 +157,8 @@ func (p *LinodeProvider) Records() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
-func (p *LinodeProvider) fetchRecords(domainID int) ([]*linodego.DomainRecord, error) {
-	records, err := p.Client.ListDomainRecords(context.TODO(), domainID, nil)
+func (p *LinodeProvider) fetchRecords(ctx context.Context, domainID int) ([]*linodego.DomainRecord, error) {
+	records, err := p.Client.ListDomainRecords(ctx, domainID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -153,10 +166,10 @@ func (p *LinodeProvider) fetchRecords(domainID int) ([]*linodego.DomainRecord, e
 	return records, nil
 }
 
-func (p *LinodeProvider) fetchZones() ([]*linodego.Domain, error) {
+func (p *LinodeProvider) fetchZones(ctx context.Context) ([]*linodego.Domain, error) {
 	var zones []*linodego.Domain
 
-	allZones, err := p.Client.ListDomains(context.TODO(), linodego.NewListOptions(0, ""))
+	allZones, err := p.Client.ListDomains(ctx, linodego.NewListOptions(0, ""))
 
 	if err != nil {
 		return nil, err
@@ -174,7 +187,7 @@ func (p *LinodeProvider) fetchZones() ([]*linodego.Domain, error) {
 }
 
 // submitChanges takes a zone and a collection of Changes and sends them as a single transaction.
-func (p *LinodeProvider) submitChanges(changes LinodeChanges) error {
+func (p *LinodeProvider) submitChanges(ctx context.Context, changes LinodeChanges) error {
 	for _, change := range changes.Creates {
 		logFields := log.Fields{
 			"record":   change.Options.Name,
@@ -189,7 +202,7 @@ func (p *LinodeProvider) submitChanges(changes LinodeChanges) error {
 		if p.DryRun {
 			log.WithFields(logFields).Info("Would create record.")
 		} else {
-			if _, err := p.Client.CreateDomainRecord(context.TODO(), change.Domain.ID, change.Options); err != nil {
+			if _, err := p.Client.CreateDomainRecord(ctx, change.Domain.ID, change.Options); err != nil {
 				log.WithFields(logFields).Errorf(
 					"Failed to Create record: %v",
 					err,
@@ -212,7 +225,7 @@ func (p *LinodeProvider) submitChanges(changes LinodeChanges) error {
 		if p.DryRun {
 			log.WithFields(logFields).Info("Would delete record.")
 		} else {
-			if err := p.Client.DeleteDomainRecord(context.TODO(), change.Domain.ID, change.DomainRecord.ID); err != nil {
+			if err := p.Client.DeleteDomainRecord(ctx, change.Domain.ID, change.DomainRecord.ID); err != nil {
 				log.WithFields(logFields).Errorf(
 					"Failed to Delete record: %v",
 					err,
@@ -235,7 +248,7 @@ func (p *LinodeProvider) submitChanges(changes LinodeChanges) error {
 		if p.DryRun {
 			log.WithFields(logFields).Info("Would update record.")
 		} else {
-			if _, err := p.Client.UpdateDomainRecord(context.TODO(), change.Domain.ID, change.DomainRecord.ID, change.Options); err != nil {
+			if _, err := p.Client.UpdateDomainRecord(ctx, change.Domain.ID, change.DomainRecord.ID, change.Options); err != nil {
 				log.WithFields(logFields).Errorf(
 					"Failed to Update record: %v",
 					err,
@@ -262,11 +275,59 @@ func getPriority() *int {
 	return &priority
 }
 
+// getTargetOptions returns the target, weight, port and priority of a Linode domain record for the given target.
+// SRV targets are split into their fields, all other targets use the default weight, port and priority.
+func getTargetOptions(recordType linodego.DomainRecordType, target string) (string, *int, *int, *int) {
+	if recordType == linodego.RecordTypeSRV {
+		srv, err := endpoint.ParseSRVTarget(target)
+		if err == nil {
+			weight, port, priority := int(srv.Weight), int(srv.Port), int(srv.Priority)
+			return srv.Target, &weight, &port, &priority
+		}
+		log.Warnf("Using default weight, port and priority: %v", err)
+	}
+	return target, getWeight(), getPort(), getPriority()
+}
+
+// getRecordTarget returns the target of an endpoint for the given Linode domain record
+func getRecordTarget(record *linodego.DomainRecord) string {
+	if record.Type == linodego.RecordTypeSRV {
+		return endpoint.NewSRVTarget(uint16(record.Priority), uint16(record.Weight), uint16(record.Port), record.Target).String()
+	}
+	return record.Target
+}
+
+func newDomainRecordCreateOptions(zone *linodego.Domain, ep *endpoint.Endpoint, recordType linodego.DomainRecordType, target string) linodego.DomainRecordCreateOptions {
+	target, weight, port, priority := getTargetOptions(recordType, target)
+	return linodego.DomainRecordCreateOptions{
+		Target:   target,
+		Name:     getStrippedRecordName(zone, ep),
+		Type:     recordType,
+		Weight:   weight,
+		Port:     port,
+		Priority: priority,
+		TTLSec:   int(ep.RecordTTL),
+	}
+}
+
+func newDomainRecordUpdateOptions(zone *linodego.Domain, ep *endpoint.Endpoint, recordType linodego.DomainRecordType, target string) linodego.DomainRecordUpdateOptions {
+	target, weight, port, priority := getTargetOptions(recordType, target)
+	return linodego.DomainRecordUpdateOptions{
+		Target:   target,
+		Name:     getStrippedRecordName(zone, ep),
+		Type:     recordType,
+		Weight:   weight,
+		Port:     port,
+		Priority: priority,
+		TTLSec:   int(ep.RecordTTL),
+	}
+}
+
 // ApplyChanges applies a given set of changes in a given zone.
-func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *LinodeProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	recordsByZoneID := make(map[string][]*linodego.DomainRecord)
 
-	zones, err := p.fetchZones()
+	zones, err := p.fetchZones(ctx)
 
 	if err != nil {
 		return err
@@ -283,7 +344,7 @@ func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
 
 	// Fetch records for each zone
 	for _, zone := range zones {
-		records, err := p.fetchRecords(zone.ID)
+		records, err := p.fetchRecords(ctx, zone.ID)
 
 		if err != nil {
 			return err
@@ -334,16 +395,8 @@ func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
 
 			for _, target := range ep.Targets {
 				linodeCreates = append(linodeCreates, &LinodeChangeCreate{
-					Domain: zone,
-					Options: linodego.DomainRecordCreateOptions{
-						Target:   target,
-						Name:     getStrippedRecordName(zone, ep),
-						Type:     recordType,
-						Weight:   getWeight(),
-						Port:     getPort(),
-						Priority: getPriority(),
-						TTLSec:   int(ep.RecordTTL),
-					},
+					Domain:  zone,
+					Options: newDomainRecordCreateOptions(zone, ep, recordType, target),
 				})
 			}
 		}
@@ -384,7 +437,7 @@ func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
 			matchedRecordsByTarget := make(map[string]*linodego.DomainRecord)
 
 			for _, record := range matchedRecords {
-				matchedRecordsByTarget[record.Target] = record
+				matchedRecordsByTarget[getRecordTarget(record)] = record
 			}
 
 			for _, target := range ep.Targets {
@@ -400,15 +453,7 @@ func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
 					linodeUpdates = append(linodeUpdates, &LinodeChangeUpdate{
 						Domain:       zone,
 						DomainRecord: record,
-						Options: linodego.DomainRecordUpdateOptions{
-							Target:   target,
-							Name:     getStrippedRecordName(zone, ep),
-							Type:     recordType,
-							Weight:   getWeight(),
-							Port:     getPort(),
-							Priority: getPriority(),
-							TTLSec:   int(ep.RecordTTL),
-						},
+						Options:      newDomainRecordUpdateOptions(zone, ep, recordType, target),
 					})
 
 					delete(matchedRecordsByTarget, target)
@@ -423,16 +468,8 @@ func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
 					}).Warn("Creating New Target")
 
 					linodeCreates = append(linodeCreates, &LinodeChangeCreate{
-						Domain: zone,
-						Options: linodego.DomainRecordCreateOptions{
-							Target:   target,
-							Name:     getStrippedRecordName(zone, ep),
-							Type:     recordType,
-							Weight:   getWeight(),
-							Port:     getPort(),
-							Priority: getPriority(),
-							TTLSec:   int(ep.RecordTTL),
-						},
+						Domain:  zone,
+						Options: newDomainRecordCreateOptions(zone, ep, recordType, target),
 					})
 				}
 			}
@@ -491,7 +528,7 @@ func (p *LinodeProvider) ApplyChanges(changes *plan.Changes) error {
 		}
 	}
 
-	return p.submitChanges(LinodeChanges{
+	return p.submitChanges(ctx, LinodeChanges{
 		Creates: linodeCreates,
 		Deletes: linodeDeletes,
 		Updates: linodeUpdates,
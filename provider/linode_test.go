@@ -135,6 +135,29 @@ func TestLinodeConvertRecordType(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestLinodeGetTargetOptions(t *testing.T) {
+	target, weight, port, priority := getTargetOptions(linodego.RecordTypeSRV, "10 20 5060 sip.example.com")
+	assert.Equal(t, "sip.example.com", target)
+	assert.Equal(t, 20, *weight)
+	assert.Equal(t, 5060, *port)
+	assert.Equal(t, 10, *priority)
+
+	target, weight, port, priority = getTargetOptions(linodego.RecordTypeA, "1.2.3.4")
+	assert.Equal(t, "1.2.3.4", target)
+	assert.Equal(t, getWeight(), weight)
+	assert.Equal(t, getPort(), port)
+	assert.Equal(t, getPriority(), priority)
+}
+
+func TestLinodeGetRecordTarget(t *testing.T) {
+	assert.Equal(t, "10 20 5060 sip.example.com", getRecordTarget(&linodego.DomainRecord{
+		Type: linodego.RecordTypeSRV, Target: "sip.example.com", Priority: 10, Weight: 20, Port: 5060,
+	}))
+	assert.Equal(t, "1.2.3.4", getRecordTarget(&linodego.DomainRecord{
+		Type: linodego.RecordTypeA, Target: "1.2.3.4", Priority: 10, Weight: 20, Port: 5060,
+	}))
+}
+
 func TestNewLinodeProvider(t *testing.T) {
 	_ = os.Setenv("LINODE_TOKEN", "xxxxxxxxxxxxxxxxx")
 	_, err := NewLinodeProvider(NewDomainFilter([]string{"ext-dns-test.zalando.to."}), true, "1.0")
@@ -175,7 +198,7 @@ func TestLinodeFetchZonesNoFiilters(t *testing.T) {
 	).Return(createZones(), nil).Once()
 
 	expected := createZones()
-	actual, err := provider.fetchZones()
+	actual, err := provider.fetchZones(context.Background())
 	require.NoError(t, err)
 
 	mockDomainClient.AssertExpectations(t)
@@ -201,7 +224,7 @@ func TestLinodeFetchZonesWithFilter(t *testing.T) {
 		{ID: 1, Domain: "foo.com"},
 		{ID: 3, Domain: "baz.com"},
 	}
-	actual, err := provider.fetchZones()
+	actual, err := provider.fetchZones(context.Background())
 	require.NoError(t, err)
 
 	mockDomainClient.AssertExpectations(t)
@@ -256,7 +279,7 @@ func TestLinodeRecords(t *testing.T) {
 		mock.Anything,
 	).Return(createBazRecords(), nil).Once()
 
-	actual, err := provider.Records()
+	actual, err := provider.Records(context.Background())
 	require.NoError(t, err)
 
 	expected := []*endpoint.Endpoint{
@@ -353,7 +376,7 @@ func TestLinodeApplyChanges(t *testing.T) {
 		},
 	).Return(&linodego.DomainRecord{}, nil).Once()
 
-	err := provider.ApplyChanges(&plan.Changes{
+	err := provider.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{{
 			DNSName:    "create.bar.io",
 			RecordType: "A",
@@ -428,7 +451,7 @@ func TestLinodeApplyChangesTargetAdded(t *testing.T) {
 		},
 	).Return(&linodego.DomainRecord{}, nil).Once()
 
-	err := provider.ApplyChanges(&plan.Changes{
+	err := provider.ApplyChanges(context.Background(), &plan.Changes{
 		// From 1 target to 2
 		UpdateNew: []*endpoint.Endpoint{{
 			DNSName:    "example.com",
@@ -484,7 +507,7 @@ func TestLinodeApplyChangesTargetRemoved(t *testing.T) {
 		11,
 	).Return(nil).Once()
 
-	err := provider.ApplyChanges(&plan.Changes{
+	err := provider.ApplyChanges(context.Background(), &plan.Changes{
 		// From 2 targets to 1
 		UpdateNew: []*endpoint.Endpoint{{
 			DNSName:    "example.com",
@@ -521,7 +544,7 @@ func TestLinodeApplyChangesNoChanges(t *testing.T) {
 		mock.Anything,
 	).Return([]*linodego.DomainRecord{{ID: 11, Name: "", Type: "A", Target: "targetA"}}, nil).Once()
 
-	err := provider.ApplyChanges(&plan.Changes{})
+	err := provider.ApplyChanges(context.Background(), &plan.Changes{})
 	require.NoError(t, err)
 
 	mockDomainClient.AssertExpectations(t)
@@ -146,6 +146,19 @@ func (p *OCIProvider) zones(ctx context.Context) (map[string]*dns.ZoneSummary, e
 	return zones, nil
 }
 
+// ZoneNames returns the names of the zones of the compartment, see ZoneNamesLister
+func (p *OCIProvider) ZoneNames(ctx context.Context) ([]string, error) {
+	zones, err := p.zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		names = append(names, *zone.Name)
+	}
+	return names, nil
+}
+
 func (p *OCIProvider) newFilteredRecordOperations(endpoints []*endpoint.Endpoint, opType dns.RecordOperationOperationEnum) []dns.RecordOperation {
 	ops := []dns.RecordOperation{}
 	for _, endpoint := range endpoints {
@@ -157,8 +170,7 @@ func (p *OCIProvider) newFilteredRecordOperations(endpoints []*endpoint.Endpoint
 }
 
 // Records returns the list of records in a given hosted zone.
-func (p *OCIProvider) Records() ([]*endpoint.Endpoint, error) {
-	ctx := context.Background()
+func (p *OCIProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	zones, err := p.zones(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting zones")
@@ -201,7 +213,7 @@ func (p *OCIProvider) Records() ([]*endpoint.Endpoint, error) {
 }
 
 // ApplyChanges applies a given set of changes to a given zone.
-func (p *OCIProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *OCIProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	log.Debugf("Processing chages: %+v", changes)
 
 	ops := []dns.RecordOperation{}
@@ -217,7 +229,6 @@ func (p *OCIProvider) ApplyChanges(changes *plan.Changes) error {
 		return nil
 	}
 
-	ctx := context.Background()
 	zones, err := p.zones(ctx)
 	if err != nil {
 		return errors.Wrap(err, "fetching zones")
@@ -237,6 +248,9 @@ func (p *OCIProvider) ApplyChanges(changes *plan.Changes) error {
 	}
 
 	for zoneID, ops := range opsByZone {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if _, err := p.client.PatchZoneRecords(ctx, dns.PatchZoneRecordsRequest{
 			CompartmentId:           &p.cfg.CompartmentID,
 			ZoneNameOrId:            &zoneID,
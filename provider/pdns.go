@@ -131,27 +131,35 @@ func stringifyHTTPResponseBody(r *http.Response) (body string) {
 // PDNSAPIProvider : Interface used and extended by the PDNSAPIClient struct as
 // well as mock APIClients used in testing
 type PDNSAPIProvider interface {
-	ListZones() ([]pgo.Zone, *http.Response, error)
+	ListZones(ctx context.Context) ([]pgo.Zone, *http.Response, error)
 	PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone)
-	ListZone(zoneID string) (pgo.Zone, *http.Response, error)
-	PatchZone(zoneID string, zoneStruct pgo.Zone) (*http.Response, error)
+	ListZone(ctx context.Context, zoneID string) (pgo.Zone, *http.Response, error)
+	PatchZone(ctx context.Context, zoneID string, zoneStruct pgo.Zone) (*http.Response, error)
 }
 
 // PDNSAPIClient : Struct that encapsulates all the PowerDNS specific implementation details
 type PDNSAPIClient struct {
 	dryRun       bool
-	authCtx      context.Context
+	apiKey       string
 	client       *pgo.APIClient
 	domainFilter DomainFilter
 }
 
+// authCtx returns ctx authenticated with the API key
+func (c *PDNSAPIClient) authCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pgo.ContextAPIKey, pgo.APIKey{Key: c.apiKey})
+}
+
 // ListZones : Method returns all enabled zones from PowerDNS
 // ref: https://doc.powerdns.com/authoritative/http-api/zone.html#get--servers-server_id-zones
-func (c *PDNSAPIClient) ListZones() (zones []pgo.Zone, resp *http.Response, err error) {
+func (c *PDNSAPIClient) ListZones(ctx context.Context) (zones []pgo.Zone, resp *http.Response, err error) {
 	for i := 0; i < retryLimit; i++ {
-		zones, resp, err = c.client.ZonesApi.ListZones(c.authCtx, defaultServerID)
+		zones, resp, err = c.client.ZonesApi.ListZones(c.authCtx(ctx), defaultServerID)
 		if err != nil {
 			log.Debugf("Unable to fetch zones %v", err)
+			if ctx.Err() != nil {
+				break
+			}
 			log.Debugf("Retrying ListZones() ... %d", i)
 			time.Sleep(retryAfterTime * (1 << uint(i)))
 			continue
@@ -182,11 +190,14 @@ func (c *PDNSAPIClient) PartitionZones(zones []pgo.Zone) (filteredZones []pgo.Zo
 
 // ListZone : Method returns the details of a specific zone from PowerDNS
 // ref: https://doc.powerdns.com/authoritative/http-api/zone.html#get--servers-server_id-zones-zone_id
-func (c *PDNSAPIClient) ListZone(zoneID string) (zone pgo.Zone, resp *http.Response, err error) {
+func (c *PDNSAPIClient) ListZone(ctx context.Context, zoneID string) (zone pgo.Zone, resp *http.Response, err error) {
 	for i := 0; i < retryLimit; i++ {
-		zone, resp, err = c.client.ZonesApi.ListZone(c.authCtx, defaultServerID, zoneID)
+		zone, resp, err = c.client.ZonesApi.ListZone(c.authCtx(ctx), defaultServerID, zoneID)
 		if err != nil {
 			log.Debugf("Unable to fetch zone %v", err)
+			if ctx.Err() != nil {
+				break
+			}
 			log.Debugf("Retrying ListZone() ... %d", i)
 			time.Sleep(retryAfterTime * (1 << uint(i)))
 			continue
@@ -202,11 +213,14 @@ func (c *PDNSAPIClient) ListZone(zoneID string) (zone pgo.Zone, resp *http.Respo
 
 // PatchZone : Method used to update the contents of a particular zone from PowerDNS
 // ref: https://doc.powerdns.com/authoritative/http-api/zone.html#patch--servers-server_id-zones-zone_id
-func (c *PDNSAPIClient) PatchZone(zoneID string, zoneStruct pgo.Zone) (resp *http.Response, err error) {
+func (c *PDNSAPIClient) PatchZone(ctx context.Context, zoneID string, zoneStruct pgo.Zone) (resp *http.Response, err error) {
 	for i := 0; i < retryLimit; i++ {
-		resp, err = c.client.ZonesApi.PatchZone(c.authCtx, defaultServerID, zoneID, zoneStruct)
+		resp, err = c.client.ZonesApi.PatchZone(c.authCtx(ctx), defaultServerID, zoneID, zoneStruct)
 		if err != nil {
 			log.Debugf("Unable to patch zone %v", err)
+			if ctx.Err() != nil {
+				break
+			}
 			log.Debugf("Retrying PatchZone() ... %d", i)
 			time.Sleep(retryAfterTime * (1 << uint(i)))
 			continue
@@ -252,7 +266,7 @@ func NewPDNSProvider(config PDNSConfig) (*PDNSProvider, error) {
 	provider := &PDNSProvider{
 		client: &PDNSAPIClient{
 			dryRun:       config.DryRun,
-			authCtx:      context.WithValue(context.TODO(), pgo.ContextAPIKey, pgo.APIKey{Key: config.APIKey}),
+			apiKey:       config.APIKey,
 			client:       pgo.NewAPIClient(pdnsClientConfig),
 			domainFilter: config.DomainFilter,
 		},
@@ -275,7 +289,7 @@ func (p *PDNSProvider) convertRRSetToEndpoints(rr pgo.RrSet) (endpoints []*endpo
 }
 
 // ConvertEndpointsToZones marshals endpoints into pdns compatible Zone structs
-func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changetype pdnsChangeType) (zonelist []pgo.Zone, _ error) {
+func (p *PDNSProvider) ConvertEndpointsToZones(ctx context.Context, eps []*endpoint.Endpoint, changetype pdnsChangeType) (zonelist []pgo.Zone, _ error) {
 
 	zonelist = []pgo.Zone{}
 	endpoints := make([]*endpoint.Endpoint, len(eps))
@@ -291,7 +305,7 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 			return endpoints[i].DNSName < endpoints[j].DNSName
 		})
 
-	zones, _, err := p.client.ListZones()
+	zones, _, err := p.client.ListZones(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -388,8 +402,8 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 }
 
 // mutateRecords takes a list of endpoints and creates, replaces or deletes them based on the changetype
-func (p *PDNSProvider) mutateRecords(endpoints []*endpoint.Endpoint, changetype pdnsChangeType) error {
-	zonelist, err := p.ConvertEndpointsToZones(endpoints, changetype)
+func (p *PDNSProvider) mutateRecords(ctx context.Context, endpoints []*endpoint.Endpoint, changetype pdnsChangeType) error {
+	zonelist, err := p.ConvertEndpointsToZones(ctx, endpoints, changetype)
 	if err != nil {
 		return err
 	}
@@ -401,7 +415,7 @@ func (p *PDNSProvider) mutateRecords(endpoints []*endpoint.Endpoint, changetype
 			log.Debugf("Struct for PatchZone:\n%s", string(jso))
 		}
 
-		resp, err := p.client.PatchZone(zone.Id, zone)
+		resp, err := p.client.PatchZone(ctx, zone.Id, zone)
 		if err != nil {
 			log.Debugf("PDNS API response: %s", stringifyHTTPResponseBody(resp))
 			return err
@@ -412,16 +426,16 @@ func (p *PDNSProvider) mutateRecords(endpoints []*endpoint.Endpoint, changetype
 }
 
 // Records returns all DNS records controlled by the configured PDNS server (for all zones)
-func (p *PDNSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
+func (p *PDNSProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
 
-	zones, _, err := p.client.ListZones()
+	zones, _, err := p.client.ListZones(ctx)
 	if err != nil {
 		return nil, err
 	}
 	filteredZones, _ := p.client.PartitionZones(zones)
 
 	for _, zone := range filteredZones {
-		z, _, err := p.client.ListZone(zone.Id)
+		z, _, err := p.client.ListZone(ctx, zone.Id)
 		if err != nil {
 			log.Warnf("Unable to fetch Records")
 			return nil, err
@@ -442,7 +456,7 @@ func (p *PDNSProvider) Records() (endpoints []*endpoint.Endpoint, _ error) {
 
 // ApplyChanges takes a list of changes (endpoints) and updates the PDNS server
 // by sending the correct HTTP PATCH requests to a matching zone
-func (p *PDNSProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *PDNSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 
 	startTime := time.Now()
 
@@ -456,7 +470,7 @@ func (p *PDNSProvider) ApplyChanges(changes *plan.Changes) error {
 	// prevent unnecessary logging
 	if len(changes.Create) > 0 {
 		// "Replacing" non-existant records creates them
-		err := p.mutateRecords(changes.Create, PdnsReplace)
+		err := p.mutateRecords(ctx, changes.Create, PdnsReplace)
 		if err != nil {
 			return err
 		}
@@ -475,7 +489,7 @@ func (p *PDNSProvider) ApplyChanges(changes *plan.Changes) error {
 		log.Debugf("UPDATE-NEW: %+v", change)
 	}
 	if len(changes.UpdateNew) > 0 {
-		err := p.mutateRecords(changes.UpdateNew, PdnsReplace)
+		err := p.mutateRecords(ctx, changes.UpdateNew, PdnsReplace)
 		if err != nil {
 			return err
 		}
@@ -486,7 +500,7 @@ func (p *PDNSProvider) ApplyChanges(changes *plan.Changes) error {
 		log.Debugf("DELETE: %+v", change)
 	}
 	if len(changes.Delete) > 0 {
-		err := p.mutateRecords(changes.Delete, PdnsDelete)
+		err := p.mutateRecords(ctx, changes.Delete, PdnsDelete)
 		if err != nil {
 			return err
 		}
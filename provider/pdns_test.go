@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"errors"
 	//"fmt"
 	"net/http"
@@ -482,16 +483,16 @@ var (
 type PDNSAPIClientStub struct {
 }
 
-func (c *PDNSAPIClientStub) ListZones() ([]pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStub) ListZones(ctx context.Context) ([]pgo.Zone, *http.Response, error) {
 	return []pgo.Zone{ZoneMixed}, nil, nil
 }
 func (c *PDNSAPIClientStub) PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone) {
 	return zones, nil
 }
-func (c *PDNSAPIClientStub) ListZone(zoneID string) (pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStub) ListZone(ctx context.Context, zoneID string) (pgo.Zone, *http.Response, error) {
 	return ZoneMixed, nil, nil
 }
-func (c *PDNSAPIClientStub) PatchZone(zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
+func (c *PDNSAPIClientStub) PatchZone(ctx context.Context, zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
 	return nil, nil
 }
 
@@ -502,13 +503,13 @@ type PDNSAPIClientStubEmptyZones struct {
 	patchedZones []pgo.Zone
 }
 
-func (c *PDNSAPIClientStubEmptyZones) ListZones() ([]pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStubEmptyZones) ListZones(ctx context.Context) ([]pgo.Zone, *http.Response, error) {
 	return []pgo.Zone{ZoneEmpty, ZoneEmptyLong, ZoneEmpty2}, nil, nil
 }
 func (c *PDNSAPIClientStubEmptyZones) PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone) {
 	return zones, nil
 }
-func (c *PDNSAPIClientStubEmptyZones) ListZone(zoneID string) (pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStubEmptyZones) ListZone(ctx context.Context, zoneID string) (pgo.Zone, *http.Response, error) {
 
 	if strings.Contains(zoneID, "example.com") {
 		return ZoneEmpty, nil, nil
@@ -520,7 +521,7 @@ func (c *PDNSAPIClientStubEmptyZones) ListZone(zoneID string) (pgo.Zone, *http.R
 	return pgo.Zone{}, nil, nil
 
 }
-func (c *PDNSAPIClientStubEmptyZones) PatchZone(zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
+func (c *PDNSAPIClientStubEmptyZones) PatchZone(ctx context.Context, zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
 	c.patchedZones = append(c.patchedZones, zoneStruct)
 	return nil, nil
 }
@@ -533,7 +534,7 @@ type PDNSAPIClientStubPatchZoneFailure struct {
 }
 
 // Just overwrite the PatchZone method to introduce a failure
-func (c *PDNSAPIClientStubPatchZoneFailure) PatchZone(zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
+func (c *PDNSAPIClientStubPatchZoneFailure) PatchZone(ctx context.Context, zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
 	return nil, errors.New("Generic PDNS Error")
 }
 
@@ -545,7 +546,7 @@ type PDNSAPIClientStubListZoneFailure struct {
 }
 
 // Just overwrite the ListZone method to introduce a failure
-func (c *PDNSAPIClientStubListZoneFailure) ListZone(zoneID string) (pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStubListZoneFailure) ListZone(ctx context.Context, zoneID string) (pgo.Zone, *http.Response, error) {
 	return pgo.Zone{}, nil, errors.New("Generic PDNS Error")
 
 }
@@ -558,7 +559,7 @@ type PDNSAPIClientStubListZonesFailure struct {
 }
 
 // Just overwrite the ListZones method to introduce a failure
-func (c *PDNSAPIClientStubListZonesFailure) ListZones() ([]pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStubListZonesFailure) ListZones(ctx context.Context) ([]pgo.Zone, *http.Response, error) {
 	return []pgo.Zone{}, nil, errors.New("Generic PDNS Error")
 }
 
@@ -569,11 +570,11 @@ type PDNSAPIClientStubPartitionZones struct {
 	PDNSAPIClientStubEmptyZones
 }
 
-func (c *PDNSAPIClientStubPartitionZones) ListZones() ([]pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStubPartitionZones) ListZones(ctx context.Context) ([]pgo.Zone, *http.Response, error) {
 	return []pgo.Zone{ZoneEmpty, ZoneEmptyLong, ZoneEmpty2, ZoneEmptySimilar}, nil, nil
 }
 
-func (c *PDNSAPIClientStubPartitionZones) ListZone(zoneID string) (pgo.Zone, *http.Response, error) {
+func (c *PDNSAPIClientStubPartitionZones) ListZone(ctx context.Context, zoneID string) (pgo.Zone, *http.Response, error) {
 
 	if strings.Contains(zoneID, "example.com") {
 		return ZoneEmpty, nil, nil
@@ -757,7 +758,7 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSRecords() {
 
 	/* We test that endpoints are returned correctly for a Zone when Records() is called
 	 */
-	eps, err := p.Records()
+	eps, err := p.Records(context.Background())
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), endpointsMixedRecords, eps)
 
@@ -766,19 +767,19 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSRecords() {
 	p = &PDNSProvider{
 		client: &PDNSAPIClientStubListZoneFailure{},
 	}
-	eps, err = p.Records()
+	eps, err = p.Records(context.Background())
 	assert.NotNil(suite.T(), err)
 
 	p = &PDNSProvider{
 		client: &PDNSAPIClientStubListZonesFailure{},
 	}
-	eps, err = p.Records()
+	eps, err = p.Records(context.Background())
 	assert.NotNil(suite.T(), err)
 
 }
 
 func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZones() {
-	// Function definition: ConvertEndpointsToZones(endpoints []*endpoint.Endpoint, changetype pdnsChangeType) (zonelist []pgo.Zone, _ error)
+	// Function definition: ConvertEndpointsToZones(ctx context.Context, endpoints []*endpoint.Endpoint, changetype pdnsChangeType) (zonelist []pgo.Zone, _ error)
 
 	// Create a new provider to run tests against
 	p := &PDNSProvider{
@@ -786,42 +787,42 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZones() {
 	}
 
 	// Check inserting endpoints from a single zone
-	zlist, err := p.ConvertEndpointsToZones(endpointsSimpleRecord, PdnsReplace)
+	zlist, err := p.ConvertEndpointsToZones(context.Background(), endpointsSimpleRecord, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
 
 	// Check deleting endpoints from a single zone
-	zlist, err = p.ConvertEndpointsToZones(endpointsSimpleRecord, PdnsDelete)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsSimpleRecord, PdnsDelete)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimpleDelete}, zlist)
 
 	// Check endpoints from multiple zones #1
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZones, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZones, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch, ZoneEmptyToSimplePatch2}, zlist)
 
 	// Check endpoints from multiple zones #2
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZones2, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZones2, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch, ZoneEmptyToSimplePatch3}, zlist)
 
 	// Check endpoints from multiple zones where some endpoints which don't exist
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZonesWithNoExist, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZonesWithNoExist, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
 
 	// Check endpoints from a zone that does not exist
-	zlist, err = p.ConvertEndpointsToZones(endpointsNonexistantZone, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsNonexistantZone, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{}, zlist)
 
 	// Check endpoints that match multiple zones (one longer than other), is assigned to the right zone
-	zlist, err = p.ConvertEndpointsToZones(endpointsLongRecord, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsLongRecord, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToLongPatch}, zlist)
 
 	// Check endpoints of type CNAME always have their target records end with a dot.
-	zlist, err = p.ConvertEndpointsToZones(endpointsMixedRecords, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMixedRecords, PdnsReplace)
 	assert.Nil(suite.T(), err)
 
 	for _, z := range zlist {
@@ -842,46 +843,46 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZonesPartitionZ
 	}
 
 	// Check inserting endpoints from a single zone which is specified in DomainFilter
-	zlist, err := p.ConvertEndpointsToZones(endpointsSimpleRecord, PdnsReplace)
+	zlist, err := p.ConvertEndpointsToZones(context.Background(), endpointsSimpleRecord, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
 
 	// Check deleting endpoints from a single zone which is specified in DomainFilter
-	zlist, err = p.ConvertEndpointsToZones(endpointsSimpleRecord, PdnsDelete)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsSimpleRecord, PdnsDelete)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimpleDelete}, zlist)
 
 	// Check endpoints from multiple zones # which one is specified in DomainFilter and one is not
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZones, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZones, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
 
 	// Check endpoints from multiple zones where some endpoints which don't exist and one that does
 	// and is part of DomainFilter
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZonesWithNoExist, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZonesWithNoExist, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
 
 	// Check endpoints from a zone that does not exist
-	zlist, err = p.ConvertEndpointsToZones(endpointsNonexistantZone, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsNonexistantZone, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{}, zlist)
 
 	// Check endpoints that match multiple zones (one longer than other), is assigned to the right zone when the longer
 	// zone is not part of the DomainFilter
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZonesWithLongRecordNotInDomainFilter, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZonesWithLongRecordNotInDomainFilter, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatchLongRecordIgnoredInDomainFilter}, zlist)
 
 	// Check endpoints that match multiple zones (one longer than other and one is very similar)
 	// is assigned to the right zone when the similar zone is not part of the DomainFilter
-	zlist, err = p.ConvertEndpointsToZones(endpointsMultipleZonesWithSimilarRecordNotInDomainFilter, PdnsReplace)
+	zlist, err = p.ConvertEndpointsToZones(context.Background(), endpointsMultipleZonesWithSimilarRecordNotInDomainFilter, PdnsReplace)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
 }
 
 func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecords() {
-	// Function definition: mutateRecords(endpoints []*endpoint.Endpoint, changetype pdnsChangeType) error
+	// Function definition: mutateRecords(ctx context.Context, endpoints []*endpoint.Endpoint, changetype pdnsChangeType) error
 
 	// Create a new provider to run tests against
 	c := &PDNSAPIClientStubEmptyZones{}
@@ -890,7 +891,7 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecords() {
 	}
 
 	// Check inserting endpoints from a single zone
-	err := p.mutateRecords(endpointsSimpleRecord, pdnsChangeType("REPLACE"))
+	err := p.mutateRecords(context.Background(), endpointsSimpleRecord, pdnsChangeType("REPLACE"))
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimplePatch}, c.patchedZones)
 
@@ -898,7 +899,7 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecords() {
 	c.patchedZones = []pgo.Zone{}
 
 	// Check deleting endpoints from a single zone
-	err = p.mutateRecords(endpointsSimpleRecord, pdnsChangeType("DELETE"))
+	err = p.mutateRecords(context.Background(), endpointsSimpleRecord, pdnsChangeType("DELETE"))
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), []pgo.Zone{ZoneEmptyToSimpleDelete}, c.patchedZones)
 
@@ -907,7 +908,7 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecords() {
 		client: &PDNSAPIClientStubPatchZoneFailure{},
 	}
 	// Check inserting endpoints from a single zone
-	err = p.mutateRecords(endpointsSimpleRecord, pdnsChangeType("REPLACE"))
+	err = p.mutateRecords(context.Background(), endpointsSimpleRecord, pdnsChangeType("REPLACE"))
 	assert.NotNil(suite.T(), err)
 
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"net"
 	"strings"
 
@@ -26,8 +27,52 @@ import (
 
 // Provider defines the interface DNS providers should implement.
 type Provider interface {
-	Records() ([]*endpoint.Endpoint, error)
-	ApplyChanges(changes *plan.Changes) error
+	Records(ctx context.Context) ([]*endpoint.Endpoint, error)
+	ApplyChanges(ctx context.Context, changes *plan.Changes) error
+}
+
+// ProviderSpecificKeysLister is implemented by providers which persist provider specific properties
+// of endpoints and return them from Records(). Only these properties are compared by the planner.
+type ProviderSpecificKeysLister interface {
+	ProviderSpecificKeys() []string
+}
+
+// ProviderSpecificKeys returns the keys of the provider specific properties persisted by the given provider.
+func ProviderSpecificKeys(p Provider) []string {
+	if lister, ok := p.(ProviderSpecificKeysLister); ok {
+		return lister.ProviderSpecificKeys()
+	}
+	return nil
+}
+
+// EndpointsAdjuster is implemented by providers which fill in the provider specific properties of
+// desired endpoints the way Records() returns them, e.g. with the defaults of the provider, so that
+// the planner doesn't see a difference where there is none.
+type EndpointsAdjuster interface {
+	AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint
+}
+
+// AdjustEndpoints returns the desired endpoints adjusted by the given provider, if it adjusts them.
+func AdjustEndpoints(p Provider, endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	if adjuster, ok := p.(EndpointsAdjuster); ok {
+		return adjuster.AdjustEndpoints(endpoints)
+	}
+	return endpoints
+}
+
+// ZoneNamesLister is implemented by providers which list the zones they manage, e.g. to attribute
+// records and changes to their zones.
+type ZoneNamesLister interface {
+	ZoneNames(ctx context.Context) ([]string, error)
+}
+
+// ZoneNames returns the names of the zones managed by the given provider, or the fallback if it
+// doesn't list its zones.
+func ZoneNames(ctx context.Context, p Provider, fallback []string) ([]string, error) {
+	if lister, ok := p.(ZoneNamesLister); ok {
+		return lister.ZoneNames(ctx)
+	}
+	return fallback, nil
 }
 
 // ensureTrailingDot ensures that the hostname receives a trailing dot if it hasn't already.
@@ -17,9 +17,52 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"testing"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/plan"
 )
 
+type providerSpecificKeysProvider struct {
+	keys []string
+}
+
+func (p providerSpecificKeysProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, nil
+}
+
+func (p providerSpecificKeysProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	return nil
+}
+
+func (p providerSpecificKeysProvider) ProviderSpecificKeys() []string { return p.keys }
+
+func TestProviderSpecificKeys(t *testing.T) {
+	if keys := ProviderSpecificKeys(NewInMemoryProvider()); keys != nil {
+		t.Errorf("expected no keys, got %v", keys)
+	}
+
+	keys := ProviderSpecificKeys(providerSpecificKeysProvider{keys: []string{"foo"}})
+	if len(keys) != 1 || keys[0] != "foo" {
+		t.Errorf("expected [foo], got %v", keys)
+	}
+}
+
+func TestZoneNames(t *testing.T) {
+	fallback := []string{"fallback.org"}
+	zones, err := ZoneNames(context.Background(), providerSpecificKeysProvider{}, fallback)
+	if err != nil || len(zones) != 1 || zones[0] != "fallback.org" {
+		t.Errorf("expected [fallback.org], got %v, %v", zones, err)
+	}
+
+	p := NewInMemoryProvider(InMemoryInitZones([]string{"example.org"}))
+	zones, err = ZoneNames(context.Background(), p, fallback)
+	if err != nil || len(zones) != 1 || zones[0] != "example.org" {
+		t.Errorf("expected [example.org], got %v, %v", zones, err)
+	}
+}
+
 func TestEnsureTrailingDot(t *testing.T) {
 	for _, tc := range []struct {
 		input, expected string
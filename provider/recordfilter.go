@@ -16,13 +16,32 @@ limitations under the License.
 
 package provider
 
+import "strings"
+
 // supportedRecordType returns true only for supported record types.
-// Currently A, CNAME, SRV, and TXT record types are supported.
+// Currently A, AAAA, CNAME, SRV, and TXT record types are supported.
 func supportedRecordType(recordType string) bool {
 	switch recordType {
-	case "A", "CNAME", "SRV", "TXT":
+	case "A", "AAAA", "CNAME", "SRV", "TXT":
 		return true
 	default:
 		return false
 	}
 }
+
+// supportedExtendedRecordType returns true for the record types supported by
+// providers which additionally manage MX, NS, CAA and PTR records.
+func supportedExtendedRecordType(recordType string) bool {
+	switch recordType {
+	case "MX", "NS", "CAA", "PTR":
+		return true
+	default:
+		return supportedRecordType(recordType)
+	}
+}
+
+// zoneApexNS returns true for the NS records at the apex of a zone. These are
+// maintained by the provider itself and must never be touched by ExternalDNS.
+func zoneApexNS(recordType, dnsName, zoneName string) bool {
+	return recordType == "NS" && strings.TrimSuffix(strings.ToLower(dnsName), ".") == strings.TrimSuffix(strings.ToLower(zoneName), ".")
+}
@@ -27,6 +27,10 @@ func TestRecordTypeFilter(t *testing.T) {
 			"A",
 			true,
 		},
+		{
+			"AAAA",
+			true,
+		},
 		{
 			"CNAME",
 			true,
@@ -48,3 +52,80 @@ func TestRecordTypeFilter(t *testing.T) {
 
 	}
 }
+
+func TestExtendedRecordTypeFilter(t *testing.T) {
+	var records = []struct {
+		rtype  string
+		expect bool
+	}{
+		{
+			"A",
+			true,
+		},
+		{
+			"SRV",
+			true,
+		},
+		{
+			"MX",
+			true,
+		},
+		{
+			"NS",
+			true,
+		},
+		{
+			"CAA",
+			true,
+		},
+		{
+			"PTR",
+			true,
+		},
+		{
+			"SOA",
+			false,
+		},
+	}
+	for _, r := range records {
+		got := supportedExtendedRecordType(r.rtype)
+		if r.expect != got {
+			t.Errorf("wrong record type %s: expect %v, but got %v", r.rtype, r.expect, got)
+		}
+	}
+}
+
+func TestZoneApexNS(t *testing.T) {
+	var records = []struct {
+		rtype   string
+		dnsName string
+		expect  bool
+	}{
+		{
+			"NS",
+			"example.org.",
+			true,
+		},
+		{
+			"NS",
+			"Example.org",
+			true,
+		},
+		{
+			"NS",
+			"sub.example.org.",
+			false,
+		},
+		{
+			"A",
+			"example.org.",
+			false,
+		},
+	}
+	for _, r := range records {
+		got := zoneApexNS(r.rtype, r.dnsName, "example.org.")
+		if r.expect != got {
+			t.Errorf("wrong result for %s %s: expect %v, but got %v", r.rtype, r.dnsName, r.expect, got)
+		}
+	}
+}
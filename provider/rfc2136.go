@@ -17,9 +17,11 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/miekg/dns"
@@ -93,7 +95,7 @@ func NewRfc2136Provider(host string, port int, zoneName string, insecure bool, k
 }
 
 // Records returns the list of records.
-func (r rfc2136Provider) Records() ([]*endpoint.Endpoint, error) {
+func (r rfc2136Provider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	rrs, err := r.List()
 	if err != nil {
 		return nil, err
@@ -124,8 +126,25 @@ OuterLoop:
 			rrValues = []string{rr.(*dns.AAAA).AAAA.String()}
 			rrType = "AAAA"
 		case dns.TypeTXT:
-			rrValues = (rr.(*dns.TXT).Txt)
+			rrValues = []string{rfc2136TXTTarget(rr.(*dns.TXT).Txt)}
 			rrType = "TXT"
+		case dns.TypeMX:
+			mx := rr.(*dns.MX)
+			rrValues = []string{endpoint.NewMXTarget(mx.Preference, mx.Mx).String()}
+			rrType = "MX"
+		case dns.TypeNS:
+			if zoneApexNS("NS", rrFqdn, r.zoneName) {
+				continue
+			}
+			rrValues = []string{rr.(*dns.NS).Ns}
+			rrType = "NS"
+		case dns.TypeCAA:
+			caa := rr.(*dns.CAA)
+			rrValues = []string{fmt.Sprintf("%d %s %q", caa.Flag, caa.Tag, caa.Value)}
+			rrType = "CAA"
+		case dns.TypePTR:
+			rrValues = []string{rr.(*dns.PTR).Ptr}
+			rrType = "PTR"
 		default:
 			continue // Unhandled record type
 		}
@@ -150,6 +169,24 @@ OuterLoop:
 	return eps, nil
 }
 
+// rfc2136TXTTarget returns the target of a TXT record with the given strings. The strings of a record
+// with several of them are kept quoted, the way they are written by AddRecord.
+func rfc2136TXTTarget(txt []string) string {
+	if len(txt) == 1 {
+		return txt[0]
+	}
+	quoted := make([]string, 0, len(txt))
+	for _, s := range txt {
+		quoted = append(quoted, "\""+s+"\"")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ZoneNames returns the configured zone, see ZoneNamesLister
+func (r rfc2136Provider) ZoneNames(ctx context.Context) ([]string, error) {
+	return []string{r.zoneName}, nil
+}
+
 func (r rfc2136Provider) IncomeTransfer(m *dns.Msg, a string) (env chan *dns.Envelope, err error) {
 	t := new(dns.Transfer)
 	if !r.insecure {
@@ -195,7 +232,7 @@ func (r rfc2136Provider) List() ([]dns.RR, error) {
 }
 
 // ApplyChanges applies a given set of changes in a given zone.
-func (r rfc2136Provider) ApplyChanges(changes *plan.Changes) error {
+func (r rfc2136Provider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	log.Debugf("ApplyChanges")
 
 	for _, ep := range changes.Create {
@@ -204,6 +241,9 @@ func (r rfc2136Provider) ApplyChanges(changes *plan.Changes) error {
 			log.Debugf("Skipping record %s because it was filtered out by the specified --domain-filter", ep.DNSName)
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		r.AddRecord(ep)
 	}
@@ -213,6 +253,9 @@ func (r rfc2136Provider) ApplyChanges(changes *plan.Changes) error {
 			log.Debugf("Skipping record %s because it was filtered out by the specified --domain-filter", ep.DNSName)
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		r.UpdateRecord(ep)
 	}
@@ -222,6 +265,9 @@ func (r rfc2136Provider) ApplyChanges(changes *plan.Changes) error {
 			log.Debugf("Skipping record %s because it was filtered out by the specified --domain-filter", ep.DNSName)
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		r.RemoveRecord(ep)
 	}
@@ -267,13 +313,14 @@ func (r rfc2136Provider) AddRecord(ep *endpoint.Endpoint) error {
 func (r rfc2136Provider) RemoveRecord(ep *endpoint.Endpoint) error {
 	log.Debugf("RemoveRecord.ep=%s", ep)
 
-	newRR := fmt.Sprintf("%s 0 %s 0.0.0.0", ep.DNSName, ep.RecordType)
-	log.Debugf("Adding RR: %s", newRR)
-
-	rr, err := dns.NewRR(newRR)
-	if err != nil {
-		return fmt.Errorf("failed to build RR: %v", err)
+	// Only the header is relevant when removing a whole RRset, this avoids
+	// having to build valid record data for each record type.
+	rrType, ok := dns.StringToType[ep.RecordType]
+	if !ok {
+		return fmt.Errorf("failed to build RR: unknown record type %s", ep.RecordType)
 	}
+	rr := &dns.ANY{Hdr: dns.RR_Header{Name: dns.Fqdn(ep.DNSName), Rrtype: rrType, Class: dns.ClassINET}}
+	log.Debugf("Removing RR: %s", rr)
 
 	rrs := make([]dns.RR, 1)
 	rrs[0] = rr
@@ -282,7 +329,7 @@ func (r rfc2136Provider) RemoveRecord(ep *endpoint.Endpoint) error {
 	m.SetUpdate(r.zoneName)
 	m.RemoveRRset(rrs)
 
-	err = r.actions.SendMessage(m)
+	err := r.actions.SendMessage(m)
 	if err != nil {
 		return fmt.Errorf("RFC2136 query failed: %v", err)
 	}
@@ -17,10 +17,12 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/internal/testutils"
 	"github.com/kubernetes-incubator/external-dns/plan"
 	"github.com/miekg/dns"
 	log "github.com/sirupsen/logrus"
@@ -108,7 +110,7 @@ func TestRfc1236GetRecords(t *testing.T) {
 	provider, err := createRfc2136StubProvider(stub)
 	assert.NoError(t, err)
 
-	recs, err := provider.Records()
+	recs, err := provider.Records(context.Background())
 	assert.NoError(t, err)
 
 	assert.Equal(t, 6, len(recs))
@@ -149,7 +151,7 @@ func TestRfc2136ApplyChanges(t *testing.T) {
 		},
 	}
 
-	err = provider.ApplyChanges(p)
+	err = provider.ApplyChanges(context.Background(), p)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 2, len(stub.createMsgs))
@@ -164,3 +166,106 @@ func TestRfc2136ApplyChanges(t *testing.T) {
 	assert.True(t, strings.Contains(stub.updateMsgs[1].String(), "v2.foobar.com"))
 
 }
+
+func TestRfc2136ApplyChangesCancelled(t *testing.T) {
+	stub := newStub()
+	provider, err := createRfc2136StubProvider(stub)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = provider.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			{
+				DNSName:    "v1.foo.com",
+				RecordType: "A",
+				Targets:    []string{"1.2.3.4"},
+			},
+		},
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, stub.createMsgs)
+}
+
+func TestRfc2136GetExtendedRecords(t *testing.T) {
+	stub := newStub()
+	err := stub.setOutput([]string{
+		"foo.com. 3600 NS ns1.foo.com.",
+		"sub.foo.com. 3600 NS ns1.example.org.",
+		"foo.com. 3600 MX 10 mail.foo.com.",
+		"foo.com. 3600 CAA 0 issue \"letsencrypt.org\"",
+		"4.3.2.1.in-addr.arpa. 3600 PTR v1.foo.com.",
+	})
+	assert.NoError(t, err)
+
+	provider, err := NewRfc2136Provider("", 0, "foo.com", false, "key", "secret", "hmac-sha512", true, DomainFilter{}, false, stub)
+	assert.NoError(t, err)
+
+	recs, err := provider.Records(context.Background())
+	assert.NoError(t, err)
+
+	assert.True(t, testutils.SameEndpoints(recs, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("sub.foo.com.", endpoint.RecordTypeNS, 3600, "ns1.example.org."),
+		endpoint.NewEndpointWithTTL("foo.com.", endpoint.RecordTypeMX, 3600, "10 mail.foo.com."),
+		endpoint.NewEndpointWithTTL("foo.com.", endpoint.RecordTypeCAA, 3600, "0 issue \"letsencrypt.org\""),
+		endpoint.NewEndpointWithTTL("4.3.2.1.in-addr.arpa.", endpoint.RecordTypePTR, 3600, "v1.foo.com."),
+	}))
+}
+
+func TestRfc2136ApplyChangesExtendedTypes(t *testing.T) {
+	stub := newStub()
+	provider, err := createRfc2136StubProvider(stub)
+	assert.NoError(t, err)
+
+	p := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("v1.foo.com", endpoint.RecordTypeMX, "10 mail.foo.com"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("v2.foo.com", endpoint.RecordTypeMX, "10 mail.foo.com"),
+			endpoint.NewEndpoint("sub.foo.com", endpoint.RecordTypeNS, "ns1.example.org"),
+		},
+	}
+
+	err = provider.ApplyChanges(context.Background(), p)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(stub.createMsgs))
+	assert.True(t, strings.Contains(stub.createMsgs[0].String(), "mail.foo.com"))
+
+	assert.Equal(t, 2, len(stub.updateMsgs))
+	assert.True(t, strings.Contains(stub.updateMsgs[0].String(), "v2.foo.com"))
+	assert.True(t, strings.Contains(stub.updateMsgs[1].String(), "sub.foo.com"))
+}
+
+func TestRfc2136TXTRecordsWithSeveralStrings(t *testing.T) {
+	labels := endpoint.NewLabels()
+	labels[endpoint.OwnerLabelKey] = "default"
+	labels[endpoint.ResourceLabelKey] = "ingress/default/" + strings.Repeat("x", 300)
+
+	stub := newStub()
+	provider, err := createRfc2136StubProvider(stub)
+	assert.NoError(t, err)
+
+	p := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("v1.foo.com", endpoint.RecordTypeTXT, labels.SerializeTXT()),
+		},
+	}
+	assert.NoError(t, provider.ApplyChanges(context.Background(), p))
+
+	assert.Equal(t, 1, len(stub.createMsgs))
+	txt, ok := stub.createMsgs[0].Ns[0].(*dns.TXT)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(txt.Txt))
+
+	stub.output = []*dns.Envelope{{RR: []dns.RR{txt}}}
+	recs, err := provider.Records(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(recs))
+	assert.Equal(t, 1, len(recs[0].Targets))
+
+	read, err := endpoint.NewLabelsFromString(recs[0].Targets[0])
+	assert.NoError(t, err)
+	assert.Equal(t, labels, read)
+}
@@ -16,15 +16,20 @@ limitations under the License.
 
 package provider
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
 
 type zoneIDName map[string]string
 
 func (z zoneIDName) Add(zoneID, zoneName string) {
-	z[zoneID] = zoneName
+	z[zoneID] = endpoint.NormalizeDNSName(zoneName)
 }
 
 func (z zoneIDName) FindZone(hostname string) (suitableZoneID, suitableZoneName string) {
+	hostname = endpoint.NormalizeDNSName(hostname)
 	for zoneID, zoneName := range z {
 		if hostname == zoneName || strings.HasSuffix(hostname, "."+zoneName) {
 			if suitableZoneName == "" || len(zoneName) > len(suitableZoneName) {
@@ -57,4 +57,10 @@ func TestZoneIDName(t *testing.T) {
 	zoneID, zoneName = z.FindZone("foo.qux.baz")
 	assert.Equal(t, "foo.qux.baz", zoneName)
 	assert.Equal(t, "654321", zoneID)
+
+	// internationalized entries are matched against the punycode form of the zone
+	z.Add("987654", "xn--bcher-kva.example.org")
+	zoneID, zoneName = z.FindZone("name.bücher.example.org")
+	assert.Equal(t, "xn--bcher-kva.example.org", zoneName)
+	assert.Equal(t, "987654", zoneID)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package registry
 
 import (
+	"context"
 	"errors"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -43,8 +44,8 @@ func NewAWSSDRegistry(provider provider.Provider, ownerID string) (*AWSSDRegistr
 
 // Records calls AWS SD API and expects AWS SD provider to provider Owner/Resource information as a serialized
 // value in the AWSSDDescriptionLabel value in the Labels map
-func (sdr *AWSSDRegistry) Records() ([]*endpoint.Endpoint, error) {
-	records, err := sdr.provider.Records()
+func (sdr *AWSSDRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	records, err := sdr.provider.Records(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -62,22 +63,44 @@ func (sdr *AWSSDRegistry) Records() ([]*endpoint.Endpoint, error) {
 	return records, nil
 }
 
+// ProviderSpecificKeys returns the provider specific properties persisted by the AWS SD provider
+func (sdr *AWSSDRegistry) ProviderSpecificKeys() []string {
+	return provider.ProviderSpecificKeys(sdr.provider)
+}
+
+// AdjustEndpoints fills in the provider specific properties of the endpoints like the AWS SD provider reads them back
+func (sdr *AWSSDRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	return provider.AdjustEndpoints(sdr.provider, endpoints)
+}
+
+// LabelKeys returns nothing as changes of labels don't trigger updates of AWS SD services
+func (sdr *AWSSDRegistry) LabelKeys() []string {
+	return nil
+}
+
+// FilterChanges returns the creations and the changes of the owned records, the ones ApplyChanges applies
+func (sdr *AWSSDRegistry) FilterChanges(changes *plan.Changes) *plan.Changes {
+	return filterOwnedChanges(sdr.ownerID, changes)
+}
+
 // ApplyChanges filters out records not owned the External-DNS, additionally it adds the required label
 // inserted in the AWS SD instance as a CreateID field
-func (sdr *AWSSDRegistry) ApplyChanges(changes *plan.Changes) error {
-	filteredChanges := &plan.Changes{
-		Create:    changes.Create,
-		UpdateNew: filterOwnedRecords(sdr.ownerID, changes.UpdateNew),
-		UpdateOld: filterOwnedRecords(sdr.ownerID, changes.UpdateOld),
-		Delete:    filterOwnedRecords(sdr.ownerID, changes.Delete),
-	}
+func (sdr *AWSSDRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	filteredChanges := filterOwnedChanges(sdr.ownerID, changes)
 
 	sdr.updateLabels(filteredChanges.Create)
 	sdr.updateLabels(filteredChanges.UpdateNew)
 	sdr.updateLabels(filteredChanges.UpdateOld)
 	sdr.updateLabels(filteredChanges.Delete)
+	sdr.updateLabels(filteredChanges.ReplaceOld)
+	sdr.updateLabels(filteredChanges.ReplaceNew)
 
-	return sdr.provider.ApplyChanges(filteredChanges)
+	for _, stage := range filteredChanges.Stages() {
+		if err := sdr.provider.ApplyChanges(ctx, stage); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (sdr *AWSSDRegistry) updateLabels(endpoints []*endpoint.Endpoint) {
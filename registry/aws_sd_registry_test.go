@@ -17,6 +17,7 @@ limitations under the License.
 package registry
 
 import (
+	"context"
 	"testing"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -31,11 +32,11 @@ type inMemoryProvider struct {
 	onApplyChanges func(changes *plan.Changes)
 }
 
-func (p *inMemoryProvider) Records() ([]*endpoint.Endpoint, error) {
+func (p *inMemoryProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	return p.endpoints, nil
 }
 
-func (p *inMemoryProvider) ApplyChanges(changes *plan.Changes) error {
+func (p *inMemoryProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	p.onApplyChanges(changes)
 	return nil
 }
@@ -99,7 +100,7 @@ func TestAWSSDRegistryTest_Records(t *testing.T) {
 	}
 
 	r, _ := NewAWSSDRegistry(p, "owner")
-	records, _ := r.Records()
+	records, _ := r.Records(context.Background())
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 }
@@ -151,7 +152,7 @@ func TestAWSSDRegistry_Records_ApplyChanges(t *testing.T) {
 	r, err := NewAWSSDRegistry(p, "owner")
 	require.NoError(t, err)
 
-	err = r.ApplyChanges(changes)
+	err = r.ApplyChanges(context.Background(), changes)
 	require.NoError(t, err)
 }
 
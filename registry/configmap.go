@@ -0,0 +1,342 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/plan"
+	"github.com/kubernetes-incubator/external-dns/provider"
+)
+
+const (
+	// The label of the ConfigMaps of a ConfigMapRegistry, holding its name
+	configMapRegistryLabelKey = "external-dns.alpha.kubernetes.io/registry"
+	// The number of times an update of a shard is retried after concurrent changes
+	maxShardUpdateRetries = 5
+)
+
+// ConfigMapRegistry implements registry interface with ownership stored in ConfigMaps instead of
+// the DNS zone, for providers which can't hold ownership records. The labels of the records of a
+// name and type are stored as JSON in one of several ConfigMaps, the shards, which are shared by
+// all instances using the same namespace and name. Changes to a shard are made with its resource
+// version, so that concurrent changes of other instances are detected and the change is retried.
+type ConfigMapRegistry struct {
+	provider  provider.Provider
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	shards    int
+	ownerID   string //refers to the owner id of the current instance
+	dryRun    bool
+}
+
+// NewConfigMapRegistry returns a new ConfigMapRegistry storing the ownership in the given number of
+// ConfigMaps named after name in the namespace
+func NewConfigMapRegistry(provider provider.Provider, client kubernetes.Interface, namespace, name string, shards int, ownerID string, dryRun bool) (*ConfigMapRegistry, error) {
+	if ownerID == "" {
+		return nil, errors.New("owner id cannot be empty")
+	}
+	if shards < 1 {
+		return nil, errors.New("the number of shards must be positive")
+	}
+
+	return &ConfigMapRegistry{
+		provider:  provider,
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		shards:    shards,
+		ownerID:   ownerID,
+		dryRun:    dryRun,
+	}, nil
+}
+
+// Records returns the current records from the provider with the labels stored in the ConfigMaps
+func (im *ConfigMapRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	records, err := im.provider.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labelMap := map[ownedKey]endpoint.Labels{}
+	for shard := 0; shard < im.shards; shard++ {
+		cm, err := im.client.CoreV1().ConfigMaps(im.namespace).Get(im.shardName(shard), metav1.GetOptions{})
+		if kubeerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ownership ConfigMap %s/%s: %v", im.namespace, im.shardName(shard), err)
+		}
+		for key, value := range cm.Data {
+			owned, ok := parseOwnershipKey(key)
+			if !ok {
+				continue
+			}
+			labels := endpoint.NewLabels()
+			if err := json.Unmarshal([]byte(value), &labels); err != nil {
+				log.Warnf("Ignoring invalid labels of %s in ownership ConfigMap %s/%s: %v", key, im.namespace, cm.Name, err)
+				continue
+			}
+			labelMap[owned] = labels
+		}
+	}
+
+	for _, record := range records {
+		record.Labels = endpoint.NewLabels()
+		for k, v := range labelMap[ownedKey{record.DNSName, record.RecordType}] {
+			record.Labels[k] = v
+		}
+	}
+
+	return records, nil
+}
+
+// ProviderSpecificKeys returns the provider specific properties persisted by the dns provider
+func (im *ConfigMapRegistry) ProviderSpecificKeys() []string {
+	return provider.ProviderSpecificKeys(im.provider)
+}
+
+// AdjustEndpoints fills in the provider specific properties of the endpoints like the dns provider reads them back
+func (im *ConfigMapRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	return provider.AdjustEndpoints(im.provider, endpoints)
+}
+
+// LabelKeys returns the labels stored in the ConfigMaps, besides the owner
+func (im *ConfigMapRegistry) LabelKeys() []string {
+	return []string{endpoint.ResourceLabelKey, endpoint.CreationTimestampLabelKey, endpoint.ConflictPriorityLabelKey, endpoint.TombstoneLabelKey}
+}
+
+// FilterChanges returns the creations and the changes of the owned records, the ones ApplyChanges applies
+func (im *ConfigMapRegistry) FilterChanges(changes *plan.Changes) *plan.Changes {
+	return filterOwnedChanges(im.ownerID, changes)
+}
+
+// ApplyChanges claims the ownership of the created and updated records, applies the changes to the
+// dns provider and releases the ownership of the deleted records. Records are never left without
+// owner this way: if applying fails, the next synchronization finds the records it claimed.
+func (im *ConfigMapRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	filteredChanges := filterOwnedChanges(im.ownerID, changes)
+
+	for _, records := range [][]*endpoint.Endpoint{filteredChanges.Create, filteredChanges.ReplaceNew} {
+		for _, r := range records {
+			if r.Labels == nil {
+				r.Labels = make(map[string]string)
+			}
+			r.Labels[endpoint.OwnerLabelKey] = im.ownerID
+		}
+	}
+
+	var claims []ownershipChange
+	for _, records := range [][]*endpoint.Endpoint{filteredChanges.Create, filteredChanges.UpdateNew, filteredChanges.ReplaceNew} {
+		for _, r := range records {
+			claims = append(claims, ownershipChange{key: ownedKey{r.DNSName, r.RecordType}, labels: r.Labels})
+		}
+	}
+	rejected, err := im.updateOwnership(claims)
+	if err != nil {
+		return err
+	}
+	if len(rejected) > 0 {
+		// another instance claimed the records since they were read
+		filteredChanges = withoutRejected(filteredChanges, rejected)
+	}
+
+	for _, stage := range filteredChanges.Stages() {
+		if err := im.provider.ApplyChanges(ctx, stage); err != nil {
+			return err
+		}
+	}
+
+	var releases []ownershipChange
+	for _, records := range [][]*endpoint.Endpoint{filteredChanges.Delete, filteredChanges.ReplaceOld} {
+		for _, r := range records {
+			releases = append(releases, ownershipChange{key: ownedKey{r.DNSName, r.RecordType}})
+		}
+	}
+	_, err = im.updateOwnership(releases)
+	return err
+}
+
+// ownershipChange sets the labels of the records of a name and type, or removes them if nil
+type ownershipChange struct {
+	key    ownedKey
+	labels endpoint.Labels
+}
+
+// updateOwnership applies the changes to the shards. Changes of the records of other owners are
+// rejected: their keys are returned.
+func (im *ConfigMapRegistry) updateOwnership(changes []ownershipChange) (map[ownedKey]bool, error) {
+	byShard := map[int][]ownershipChange{}
+	for _, change := range changes {
+		shard := im.shardOf(change.key)
+		byShard[shard] = append(byShard[shard], change)
+	}
+
+	rejected := map[ownedKey]bool{}
+	for shard, shardChanges := range byShard {
+		var shardRejected []ownedKey
+		err := im.updateShard(im.shardName(shard), func(data map[string]string) error {
+			shardRejected = nil
+			for _, change := range shardChanges {
+				key := ownershipKey(change.key)
+				if value, ok := data[key]; ok {
+					labels := endpoint.NewLabels()
+					if err := json.Unmarshal([]byte(value), &labels); err == nil && labels[endpoint.OwnerLabelKey] != im.ownerID {
+						shardRejected = append(shardRejected, change.key)
+						continue
+					}
+				}
+				if change.labels == nil {
+					delete(data, key)
+					continue
+				}
+				value, err := json.Marshal(change.labels)
+				if err != nil {
+					return err
+				}
+				data[key] = string(value)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range shardRejected {
+			log.Warnf("Skipping %s record %s as it is owned by another instance", key.recordType, key.dnsName)
+			rejected[key] = true
+		}
+	}
+	return rejected, nil
+}
+
+// updateShard updates the data of the ConfigMap and creates it if it doesn't exist, retrying with
+// the current data if another instance changed it in the meantime
+func (im *ConfigMapRegistry) updateShard(name string, update func(data map[string]string) error) error {
+	for attempt := 0; ; attempt++ {
+		cm, err := im.client.CoreV1().ConfigMaps(im.namespace).Get(name, metav1.GetOptions{})
+		exists := err == nil
+		if kubeerrors.IsNotFound(err) {
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: im.namespace,
+					Name:      name,
+					Labels:    map[string]string{configMapRegistryLabelKey: im.name},
+				},
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to get ownership ConfigMap %s/%s: %v", im.namespace, name, err)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		if err := update(cm.Data); err != nil {
+			return err
+		}
+
+		if im.dryRun {
+			return nil
+		}
+		if exists {
+			// the update carries the resource version which was read, so it fails if the ConfigMap changed since
+			_, err = im.client.CoreV1().ConfigMaps(im.namespace).Update(cm)
+		} else {
+			_, err = im.client.CoreV1().ConfigMaps(im.namespace).Create(cm)
+		}
+		if err == nil {
+			return nil
+		}
+		if !kubeerrors.IsConflict(err) && !kubeerrors.IsAlreadyExists(err) || attempt >= maxShardUpdateRetries {
+			return fmt.Errorf("failed to update ownership ConfigMap %s/%s: %v", im.namespace, name, err)
+		}
+		log.Debugf("Ownership ConfigMap %s/%s changed concurrently, retrying", im.namespace, name)
+	}
+}
+
+// shardOf returns the shard holding the labels of the records of the name and type
+func (im *ConfigMapRegistry) shardOf(key ownedKey) int {
+	h := fnv.New32a()
+	h.Write([]byte(ownershipKey(key)))
+	return int(h.Sum32() % uint32(im.shards))
+}
+
+func (im *ConfigMapRegistry) shardName(shard int) string {
+	return fmt.Sprintf("%s-%d", im.name, shard)
+}
+
+// ownershipKey returns the key of the records of the name and type in a shard, e.g. a.foo.example.org.
+// Keys of ConfigMaps can't contain '*', so the one of a wildcard is written as '_'.
+func ownershipKey(key ownedKey) string {
+	name := key.dnsName
+	if strings.HasPrefix(name, "*.") {
+		name = "_" + strings.TrimPrefix(name, "*")
+	}
+	return strings.ToLower(key.recordType) + "." + name
+}
+
+// parseOwnershipKey returns the name and type of the records of a key written by ownershipKey
+func parseOwnershipKey(key string) (ownedKey, bool) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ownedKey{}, false
+	}
+	name := parts[1]
+	if strings.HasPrefix(name, "_.") {
+		name = "*" + strings.TrimPrefix(name, "_")
+	}
+	return ownedKey{dnsName: name, recordType: strings.ToUpper(parts[0])}, true
+}
+
+// withoutRejected drops the changes of the rejected records. Replacements are dropped for the
+// whole name, as the old records can't be removed without creating the new ones.
+func withoutRejected(changes *plan.Changes, rejected map[ownedKey]bool) *plan.Changes {
+	rejectedNames := map[string]bool{}
+	for key := range rejected {
+		rejectedNames[key.dnsName] = true
+	}
+	keep := func(records []*endpoint.Endpoint, byName bool) []*endpoint.Endpoint {
+		kept := []*endpoint.Endpoint{}
+		for _, r := range records {
+			if byName && rejectedNames[r.DNSName] || !byName && rejected[ownedKey{r.DNSName, r.RecordType}] {
+				continue
+			}
+			kept = append(kept, r)
+		}
+		return kept
+	}
+	return &plan.Changes{
+		Create:     keep(changes.Create, false),
+		UpdateOld:  keep(changes.UpdateOld, false),
+		UpdateNew:  keep(changes.UpdateNew, false),
+		Delete:     changes.Delete,
+		ReplaceOld: keep(changes.ReplaceOld, true),
+		ReplaceNew: keep(changes.ReplaceNew, true),
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/internal/testutils"
+	"github.com/kubernetes-incubator/external-dns/plan"
+	"github.com/kubernetes-incubator/external-dns/provider"
+)
+
+func TestConfigMapRegistryNew(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	p := provider.NewInMemoryProvider()
+
+	_, err := NewConfigMapRegistry(p, client, "default", "external-dns", 2, "", false)
+	assert.Error(t, err, "should require an owner id")
+	_, err = NewConfigMapRegistry(p, client, "default", "external-dns", 0, "owner", false)
+	assert.Error(t, err, "should require shards")
+
+	r, err := NewConfigMapRegistry(p, client, "default", "external-dns", 2, "owner", false)
+	require.NoError(t, err)
+	assert.Equal(t, p, r.provider)
+}
+
+func TestOwnershipKey(t *testing.T) {
+	for _, key := range []ownedKey{
+		{"foo.example.org", endpoint.RecordTypeA},
+		{"*.example.org", endpoint.RecordTypeCNAME},
+		{"_acme-challenge.example.org", endpoint.RecordTypeTXT},
+	} {
+		parsed, ok := parseOwnershipKey(ownershipKey(key))
+		assert.True(t, ok)
+		assert.Equal(t, key, parsed)
+	}
+	assert.Equal(t, "cname._.example.org", ownershipKey(ownedKey{"*.example.org", endpoint.RecordTypeCNAME}))
+
+	_, ok := parseOwnershipKey("invalid")
+	assert.False(t, ok)
+}
+
+func TestConfigMapRegistryRecords(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+			newEndpointWithOwner("*.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+		},
+	})
+	r, err := NewConfigMapRegistry(p, fake.NewSimpleClientset(), "default", "external-dns", 4, "owner", false)
+	require.NoError(t, err)
+
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+		newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+		newEndpointWithOwner("*.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, ""),
+		newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+	}), "records without ConfigMaps have no owner")
+
+	_, err = r.updateOwnership([]ownershipChange{
+		{ownedKey{"foo.test-zone.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "owner", endpoint.ResourceLabelKey: "ingress/default/foo"}},
+		{ownedKey{"foo.test-zone.example.org", endpoint.RecordTypeAAAA}, endpoint.Labels{endpoint.OwnerLabelKey: "other"}},
+		{ownedKey{"*.test-zone.example.org", endpoint.RecordTypeCNAME}, endpoint.Labels{endpoint.OwnerLabelKey: "owner"}},
+	})
+	require.NoError(t, err)
+
+	records, err = r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwnerResource("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner", "ingress/default/foo"),
+		newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "other"),
+		newEndpointWithOwner("*.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, "owner"),
+		newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+	}))
+}
+
+func TestConfigMapRegistryApplyChanges(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+		},
+	})
+	client := fake.NewSimpleClientset()
+	r, err := NewConfigMapRegistry(p, client, "default", "external-dns", 2, "owner", false)
+	require.NoError(t, err)
+	_, err = r.updateOwnership([]ownershipChange{
+		{ownedKey{"foo.test-zone.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "owner"}},
+		{ownedKey{"bar.test-zone.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "owner"}},
+		{ownedKey{"taken.test-zone.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "other"}},
+	})
+	require.NoError(t, err)
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwnerResource("new.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, "", "ingress/default/new"),
+			newEndpointWithOwner("taken.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, ""),
+		},
+		UpdateOld: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			newEndpointWithOwnerResource("foo.test-zone.example.org", "4.3.2.1", endpoint.RecordTypeA, "owner", "ingress/default/foo"),
+		},
+		Delete: []*endpoint.Endpoint{
+			newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, "owner"),
+		},
+	}
+	p.OnApplyChanges = func(got *plan.Changes) {
+		// the records claimed by another instance in the meantime are left alone
+		assert.True(t, testutils.SameEndpoints(got.Create, []*endpoint.Endpoint{
+			newEndpointWithOwnerResource("new.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, "owner", "ingress/default/new"),
+		}))
+	}
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwnerResource("foo.test-zone.example.org", "4.3.2.1", endpoint.RecordTypeA, "owner", "ingress/default/foo"),
+		newEndpointWithOwnerResource("new.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, "owner", "ingress/default/new"),
+	}))
+
+	// deleted records are released, the ones of other owners kept
+	data := map[string]string{}
+	for _, shard := range []string{"external-dns-0", "external-dns-1"} {
+		cm, err := client.CoreV1().ConfigMaps("default").Get(shard, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "external-dns", cm.Labels[configMapRegistryLabelKey])
+		for k, v := range cm.Data {
+			data[k] = v
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"a.foo.test-zone.example.org":   `{"owner":"owner","resource":"ingress/default/foo"}`,
+		"a.new.test-zone.example.org":   `{"owner":"owner","resource":"ingress/default/new"}`,
+		"a.taken.test-zone.example.org": `{"owner":"other"}`,
+	}, data)
+}
+
+func TestConfigMapRegistryConflicts(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "external-dns-0"},
+	})
+	r, err := NewConfigMapRegistry(provider.NewInMemoryProvider(), client, "default", "external-dns", 1, "owner", false)
+	require.NoError(t, err)
+
+	// another instance claims the record while this one updates the shard
+	conflicts := 0
+	client.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "external-dns-0"},
+			Data:       map[string]string{"a.foo.example.org": `{"owner":"other"}`},
+		}
+		if err := client.Tracker().Update(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, cm, "default"); err != nil {
+			return true, nil, err
+		}
+		return true, nil, kubeerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "external-dns-0", errors.New("changed"))
+	})
+
+	rejected, err := r.updateOwnership([]ownershipChange{
+		{ownedKey{"foo.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "owner"}},
+		{ownedKey{"bar.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "owner"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, conflicts)
+	assert.Equal(t, map[ownedKey]bool{{"foo.example.org", endpoint.RecordTypeA}: true}, rejected)
+
+	cm, err := client.CoreV1().ConfigMaps("default").Get("external-dns-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"a.foo.example.org": `{"owner":"other"}`,
+		"a.bar.example.org": `{"owner":"owner"}`,
+	}, cm.Data)
+}
+
+func TestConfigMapRegistryDryRun(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	r, err := NewConfigMapRegistry(provider.NewInMemoryProvider(), client, "default", "external-dns", 1, "owner", true)
+	require.NoError(t, err)
+
+	_, err = r.updateOwnership([]ownershipChange{
+		{ownedKey{"foo.example.org", endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "owner"}},
+	})
+	require.NoError(t, err)
+
+	_, err = client.CoreV1().ConfigMaps("default").Get("external-dns-0", metav1.GetOptions{})
+	assert.True(t, kubeerrors.IsNotFound(err), "should not write ConfigMaps")
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package registry
 
 import (
+	"context"
+
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/plan"
 	"github.com/kubernetes-incubator/external-dns/provider"
@@ -35,11 +37,31 @@ func NewNoopRegistry(provider provider.Provider) (*NoopRegistry, error) {
 }
 
 // Records returns the current records from the dns provider
-func (im *NoopRegistry) Records() ([]*endpoint.Endpoint, error) {
-	return im.provider.Records()
+func (im *NoopRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return im.provider.Records(ctx)
+}
+
+// ProviderSpecificKeys returns the provider specific properties persisted by the dns provider
+func (im *NoopRegistry) ProviderSpecificKeys() []string {
+	return provider.ProviderSpecificKeys(im.provider)
+}
+
+// AdjustEndpoints fills in the provider specific properties of the endpoints like the dns provider reads them back
+func (im *NoopRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	return provider.AdjustEndpoints(im.provider, endpoints)
+}
+
+// LabelKeys returns nothing as labels are not persisted
+func (im *NoopRegistry) LabelKeys() []string {
+	return nil
 }
 
-// ApplyChanges propagates changes to the dns provider
-func (im *NoopRegistry) ApplyChanges(changes *plan.Changes) error {
-	return im.provider.ApplyChanges(changes)
+// ApplyChanges propagates changes to the dns provider, stage by stage
+func (im *NoopRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	for _, stage := range changes.Stages() {
+		if err := im.provider.ApplyChanges(ctx, stage); err != nil {
+			return err
+		}
+	}
+	return nil
 }
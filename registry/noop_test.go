@@ -17,6 +17,7 @@ limitations under the License.
 package registry
 
 import (
+	"context"
 	"testing"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -53,13 +54,13 @@ func testNoopRecords(t *testing.T) {
 			RecordType: endpoint.RecordTypeCNAME,
 		},
 	}
-	p.ApplyChanges(&plan.Changes{
+	p.ApplyChanges(context.Background(), &plan.Changes{
 		Create: providerRecords,
 	})
 
 	r, _ := NewNoopRegistry(p)
 
-	eps, err := r.Records()
+	eps, err := r.Records(context.Background())
 	require.NoError(t, err)
 	assert.True(t, testutils.SameEndpoints(eps, providerRecords))
 }
@@ -88,13 +89,13 @@ func testNoopApplyChanges(t *testing.T) {
 		},
 	}
 
-	p.ApplyChanges(&plan.Changes{
+	p.ApplyChanges(context.Background(), &plan.Changes{
 		Create: providerRecords,
 	})
 
 	// wrong changes
 	r, _ := NewNoopRegistry(p)
-	err := r.ApplyChanges(&plan.Changes{
+	err := r.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			{
 				DNSName:    "example.org",
@@ -106,7 +107,7 @@ func testNoopApplyChanges(t *testing.T) {
 	assert.EqualError(t, err, provider.ErrRecordAlreadyExists.Error())
 
 	//correct changes
-	require.NoError(t, r.ApplyChanges(&plan.Changes{
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			{
 				DNSName:    "new-record.org",
@@ -129,6 +130,6 @@ func testNoopApplyChanges(t *testing.T) {
 			},
 		},
 	}))
-	res, _ := p.Records()
+	res, _ := p.Records(context.Background())
 	assert.True(t, testutils.SameEndpoints(res, expectedUpdate))
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package registry
 
 import (
+	"context"
+
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/kubernetes-incubator/external-dns/plan"
 	log "github.com/sirupsen/logrus"
@@ -26,9 +28,41 @@ import (
 // Records() returns ALL records registered with DNS provider
 // each entry includes owner information
 // ApplyChanges(changes *plan.Changes) propagates the changes to the DNS Provider API and correspondingly updates ownership depending on type of registry being used
+// ProviderSpecificKeys() returns the keys of provider specific properties persisted by the DNS Provider
+// AdjustEndpoints() fills in the provider specific properties of desired endpoints like the DNS Provider reads them back
+// LabelKeys() returns the keys of labels persisted by the registry, besides the owner
 type Registry interface {
-	Records() ([]*endpoint.Endpoint, error)
-	ApplyChanges(changes *plan.Changes) error
+	Records(ctx context.Context) ([]*endpoint.Endpoint, error)
+	ApplyChanges(ctx context.Context, changes *plan.Changes) error
+	ProviderSpecificKeys() []string
+	AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint
+	LabelKeys() []string
+}
+
+// OrphanFinder is implemented by registries which find the ownership data left behind by records
+// which are gone, e.g. deleted out of band. Orphans returns the data found by the last call of
+// Records as records of their own, which ApplyChanges deletes or updates like any other record.
+type OrphanFinder interface {
+	Orphans() []*endpoint.Endpoint
+}
+
+// ChangesFilter is implemented by registries which only apply some of the changes passed to
+// ApplyChanges, e.g. those of the records they own. FilterChanges returns the changes of records
+// ApplyChanges applies, without the changes of the registry's own ownership data.
+type ChangesFilter interface {
+	FilterChanges(changes *plan.Changes) *plan.Changes
+}
+
+// filterOwnedChanges keeps the creations and the changes of the records owned by ownerID.
+func filterOwnedChanges(ownerID string, changes *plan.Changes) *plan.Changes {
+	filtered := &plan.Changes{
+		Create:    changes.Create,
+		UpdateNew: filterOwnedRecords(ownerID, changes.UpdateNew),
+		UpdateOld: filterOwnedRecords(ownerID, changes.UpdateOld),
+		Delete:    filterOwnedRecords(ownerID, changes.Delete),
+	}
+	filtered.ReplaceOld, filtered.ReplaceNew = filterOwnedReplacements(ownerID, changes.ReplaceOld, changes.ReplaceNew)
+	return filtered
 }
 
 //TODO(ideahitme): consider moving this to Plan
@@ -43,3 +77,30 @@ func filterOwnedRecords(ownerID string, eps []*endpoint.Endpoint) []*endpoint.En
 	}
 	return filtered
 }
+
+// filterOwnedReplacements drops replacements of records which are not owned by ownerID.
+// A replacement only makes sense if all the records it removes can be removed, hence when
+// any of the old records of a DNS name is foreign, neither old nor new records of that name are kept.
+func filterOwnedReplacements(ownerID string, replaceOld, replaceNew []*endpoint.Endpoint) ([]*endpoint.Endpoint, []*endpoint.Endpoint) {
+	foreign := map[string]bool{}
+	for _, ep := range replaceOld {
+		if endpointOwner, ok := ep.Labels[endpoint.OwnerLabelKey]; !ok || endpointOwner != ownerID {
+			log.Debugf(`Skipping replacement of %s because owner id does not match, found: "%s", required: "%s"`, ep.DNSName, endpointOwner, ownerID)
+			foreign[ep.DNSName] = true
+		}
+	}
+
+	filteredOld := []*endpoint.Endpoint{}
+	for _, ep := range replaceOld {
+		if !foreign[ep.DNSName] {
+			filteredOld = append(filteredOld, ep)
+		}
+	}
+	filteredNew := []*endpoint.Endpoint{}
+	for _, ep := range replaceNew {
+		if !foreign[ep.DNSName] {
+			filteredNew = append(filteredNew, ep)
+		}
+	}
+	return filteredOld, filteredNew
+}
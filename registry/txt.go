@@ -17,6 +17,7 @@ limitations under the License.
 package registry
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -34,32 +35,129 @@ type TXTRegistry struct {
 	ownerID  string //refers to the owner id of the current instance
 	mapper   nameMapper
 
+	// the mapper of the layout ownership records are migrated from, nil unless migrating
+	legacyMapper nameMapper
+	// the ownership records which Records found in the legacy layout, rewritten by ApplyChanges
+	migrations *plan.Changes
+	// the values of the ownership records by name, so that they are updated and deleted with the
+	// exact value they have, whatever version of the format they were written in
+	ownershipValues map[string]string
+	// the owners of the ownership records by name, and the owned records of each ownership record,
+	// so that an ownership record shared by records of several types is only created and deleted once
+	ownershipOwners map[string]string
+	ownedRecords    map[string]map[ownedKey]bool
+	// the owned ownership records which Records found without records, see Orphans
+	orphans       []*endpoint.Endpoint
+	deleteOrphans bool
+	orphansDryRun bool
+
+	// the naming scheme set by the options, see newTXTNameMapper
+	txtSuffix     string
+	typed         bool
+	migrateLayout bool
+
+	// signs the ownership records and verifies the ones read, nil unless signing
+	signer         *txtSigner
+	signingKeys    [][]byte
+	sign           bool
+	acceptUnsigned bool
+
 	// cache the records in memory and update on an interval instead.
 	recordsCache            []*endpoint.Endpoint
 	recordsCacheRefreshTime time.Time
 	cacheInterval           time.Duration
 }
 
+// TXTOption allows to change the naming scheme of the ownership records of the TXT registry
+type TXTOption func(*TXTRegistry)
+
+// TXTWithSuffix inserts the suffix after the first label of the names of the ownership records,
+// e.g. foo-txt.example.org, instead of prefixing them
+func TXTWithSuffix(suffix string) TXTOption {
+	return func(im *TXTRegistry) {
+		im.txtSuffix = suffix
+	}
+}
+
+// TXTWithRecordType encodes the record type in the names of the ownership records,
+// e.g. a-foo.example.org, so that records of several types on one name have their own
+func TXTWithRecordType() TXTOption {
+	return func(im *TXTRegistry) {
+		im.typed = true
+	}
+}
+
+// TXTWithMigration also reads ownership records named with only the prefix, the layout without
+// any options, and rewrites the owned ones to the layout given by the other options
+func TXTWithMigration() TXTOption {
+	return func(im *TXTRegistry) {
+		im.migrateLayout = true
+	}
+}
+
+// TXTWithSigningKeys signs the ownership records with the first of the HMAC keys and ignores the
+// ones which aren't signed with any of them, as anyone able to write to the zone could forge them
+func TXTWithSigningKeys(keys [][]byte) TXTOption {
+	return func(im *TXTRegistry) {
+		im.signingKeys = keys
+		im.sign = true
+	}
+}
+
+// TXTWithUnsignedAccepted trusts ownership records without any signature while signing, and signs
+// the owned ones, to start signing the records of an existing installation
+func TXTWithUnsignedAccepted() TXTOption {
+	return func(im *TXTRegistry) {
+		im.acceptUnsigned = true
+	}
+}
+
 // NewTXTRegistry returns new TXTRegistry object
-func NewTXTRegistry(provider provider.Provider, txtPrefix, ownerID string, cacheInterval time.Duration) (*TXTRegistry, error) {
+func NewTXTRegistry(provider provider.Provider, txtPrefix, ownerID string, cacheInterval time.Duration, opts ...TXTOption) (*TXTRegistry, error) {
 	if ownerID == "" {
 		return nil, errors.New("owner id cannot be empty")
 	}
 
-	mapper := newPrefixNameMapper(txtPrefix)
+	im := &TXTRegistry{
+		provider:        provider,
+		ownerID:         ownerID,
+		ownershipValues: map[string]string{},
+		ownershipOwners: map[string]string{},
+		ownedRecords:    map[string]map[ownedKey]bool{},
+		cacheInterval:   cacheInterval,
+	}
+	for _, opt := range opts {
+		opt(im)
+	}
+
+	mapper, err := newTXTNameMapper(txtPrefix, im.txtSuffix, im.typed)
+	if err != nil {
+		return nil, err
+	}
+	im.mapper = mapper
+
+	if im.migrateLayout {
+		if im.txtSuffix == "" && !im.typed {
+			return nil, errors.New("migrating ownership records requires a suffix or the record type in their names")
+		}
+		im.legacyMapper = newPrefixNameMapper(txtPrefix)
+	}
+
+	if im.sign {
+		if im.signer, err = newTXTSigner(im.signingKeys); err != nil {
+			return nil, err
+		}
+	} else if im.acceptUnsigned {
+		return nil, errors.New("accepting unsigned ownership records requires signing keys")
+	}
 
-	return &TXTRegistry{
-		provider:      provider,
-		ownerID:       ownerID,
-		mapper:        mapper,
-		cacheInterval: cacheInterval,
-	}, nil
+	return im, nil
 }
 
 // Records returns the current records from the registry excluding TXT Records
 // If TXT records was created previously to indicate ownership its corresponding value
 // will be added to the endpoints Labels map
-func (im *TXTRegistry) Records() ([]*endpoint.Endpoint, error) {
+func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	// If we have the zones cached AND we have refreshed the cache since the
 	// last given interval, then just use the cached results.
 	if im.recordsCache != nil && time.Since(im.recordsCacheRefreshTime) < im.cacheInterval {
@@ -67,14 +165,17 @@ func (im *TXTRegistry) Records() ([]*endpoint.Endpoint, error) {
 		return im.recordsCache, nil
 	}
 
-	records, err := im.provider.Records()
+	records, err := im.provider.Records(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	endpoints := []*endpoint.Endpoint{}
-
-	labelMap := map[string]endpoint.Labels{}
+	txtRecords := []*endpoint.Endpoint{}
+	txtLabels := map[*endpoint.Endpoint]endpoint.Labels{}
+	unsigned := map[*endpoint.Endpoint]bool{}
+	// the values of the ownership records with an invalid signature, which are taken over like unowned ones
+	forged := map[string]string{}
 
 	for _, record := range records {
 		if record.RecordType != endpoint.RecordTypeTXT {
@@ -90,21 +191,91 @@ func (im *TXTRegistry) Records() ([]*endpoint.Endpoint, error) {
 			endpoints = append(endpoints, record)
 			continue
 		}
+		if err == endpoint.ErrUnknownLabelsVersion {
+			log.Warnf("Ignoring ownership record %s written by a newer version of ExternalDNS", record.DNSName)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
-		endpointDNSName := im.mapper.toEndpointName(record.DNSName)
-		labelMap[endpointDNSName] = labels
+		if im.signer != nil {
+			// records which fail the verification are ignored, so that the records they
+			// claim appear to have no owner
+			valid, signed := im.signer.verify(record.DNSName, record.Targets[0])
+			if !signed && im.acceptUnsigned {
+				unsigned[record] = true
+			} else if !valid {
+				log.Warnf("Ignoring ownership record %s without a valid signature", record.DNSName)
+				forged[record.DNSName] = record.Targets[0]
+				continue
+			}
+		}
+		txtRecords = append(txtRecords, record)
+		txtLabels[record] = labels
+	}
+
+	im.ownershipValues = forged
+	im.ownershipOwners = map[string]string{}
+	for _, record := range txtRecords {
+		im.ownershipValues[record.DNSName] = record.Targets[0]
+		im.ownershipOwners[record.DNSName] = txtLabels[record][endpoint.OwnerLabelKey]
+	}
+
+	labelMap := map[ownedKey]endpoint.Labels{}
+	legacyLabelMap := map[string]endpoint.Labels{}
+	legacyRecords := map[string]*endpoint.Endpoint{}
+	existing := existingRecords(endpoints)
+	migrations := &plan.Changes{}
+	migrated := map[string]bool{}
+	orphans := []*endpoint.Endpoint{}
+
+	for _, record := range txtRecords {
+		name, recordType := im.mapper.toEndpointName(record.DNSName)
+		if im.legacyMapper != nil && !existing[ownedKey{name, recordType}] {
+			// while migrating, a name which doesn't own any records in the new layout may be in the legacy layout
+			if legacyName, _ := im.legacyMapper.toEndpointName(record.DNSName); legacyName != "" && (name == "" || existing[ownedKey{legacyName, ""}]) {
+				legacyLabelMap[legacyName] = txtLabels[record]
+				legacyRecords[legacyName] = record
+				continue
+			}
+		}
+		if name != "" {
+			labelMap[ownedKey{name, recordType}] = txtLabels[record]
+			if !existing[ownedKey{name, recordType}] && txtLabels[record][endpoint.OwnerLabelKey] == im.ownerID {
+				orphans = append(orphans, orphanRecord(record, txtLabels[record]))
+			}
+			if unsigned[record] && txtLabels[record][endpoint.OwnerLabelKey] == im.ownerID {
+				migrations.UpdateOld = append(migrations.UpdateOld, record)
+				migrations.UpdateNew = append(migrations.UpdateNew, endpoint.NewEndpoint(record.DNSName, endpoint.RecordTypeTXT, im.serialize(record.DNSName, txtLabels[record])))
+			}
+		}
 	}
 
 	for _, ep := range endpoints {
 		ep.Labels = endpoint.NewLabels()
-		if labels, ok := labelMap[ep.DNSName]; ok {
-			for k, v := range labels {
-				ep.Labels[k] = v
+		labels, ok := labelMap[ownedKey{ep.DNSName, ep.RecordType}]
+		if !ok {
+			labels, ok = labelMap[ownedKey{ep.DNSName, ""}]
+		}
+		if !ok {
+			labels, ok = legacyLabelMap[ep.DNSName]
+			if ok && labels[endpoint.OwnerLabelKey] == im.ownerID {
+				im.addMigration(migrations, migrated, ep, labels, legacyRecords[ep.DNSName])
 			}
 		}
+		for k, v := range labels {
+			ep.Labels[k] = v
+		}
 	}
+	im.ownedRecords = map[string]map[ownedKey]bool{}
+	for _, ep := range endpoints {
+		if ep.Labels[endpoint.OwnerLabelKey] == im.ownerID {
+			im.addOwnedRecord(ep)
+		}
+	}
+	im.migrations = migrations
+	im.orphans = orphans
+	orphanedRecords.Set(float64(len(orphans)))
 
 	// Update the cache.
 	if im.cacheInterval > 0 {
@@ -115,34 +286,107 @@ func (im *TXTRegistry) Records() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
+// ProviderSpecificKeys returns the provider specific properties persisted by the dns provider
+func (im *TXTRegistry) ProviderSpecificKeys() []string {
+	return provider.ProviderSpecificKeys(im.provider)
+}
+
+// AdjustEndpoints fills in the provider specific properties of the endpoints like the dns provider reads them back
+func (im *TXTRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	return provider.AdjustEndpoints(im.provider, endpoints)
+}
+
+// LabelKeys returns the labels stored in the TXT records, besides the owner
+func (im *TXTRegistry) LabelKeys() []string {
+	return []string{endpoint.ResourceLabelKey, endpoint.CreationTimestampLabelKey, endpoint.ConflictPriorityLabelKey, endpoint.TombstoneLabelKey}
+}
+
+// FilterChanges returns the changes of records ApplyChanges applies: the creations of records
+// whose ownership record isn't foreign and the changes of the owned records
+func (im *TXTRegistry) FilterChanges(changes *plan.Changes) *plan.Changes {
+	filtered := filterOwnedChanges(im.ownerID, changes)
+	creates := []*endpoint.Endpoint{}
+	for _, r := range filtered.Create {
+		if _, owner := im.foreignOwnership(r); owner == "" {
+			creates = append(creates, r)
+		}
+	}
+	filtered.Create = creates
+	return filtered
+}
+
+// foreignOwnership returns the name and owner of the ownership record of r if another owner owns it
+func (im *TXTRegistry) foreignOwnership(r *endpoint.Endpoint) (string, string) {
+	txtName := im.mapper.toTXTName(r.DNSName, r.RecordType)
+	if owner := im.ownershipOwners[txtName]; owner != im.ownerID {
+		return txtName, owner
+	}
+	return txtName, ""
+}
+
 // ApplyChanges updates dns provider with the changes
 // for each created/deleted record it will also take into account TXT records for creation/deletion
-func (im *TXTRegistry) ApplyChanges(changes *plan.Changes) error {
-	filteredChanges := &plan.Changes{
-		Create:    changes.Create,
-		UpdateNew: filterOwnedRecords(im.ownerID, changes.UpdateNew),
-		UpdateOld: filterOwnedRecords(im.ownerID, changes.UpdateOld),
-		Delete:    filterOwnedRecords(im.ownerID, changes.Delete),
+func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	if err := im.migrate(ctx); err != nil {
+		return err
 	}
+	changes, orphans := im.splitOrphans(changes)
+
+	filteredChanges := filterOwnedChanges(im.ownerID, changes)
+
+	// several records of different types can share the same DNS name, but there is
+	// only one TXT record per name, so make sure it is only added once per change list
+	txtCreate, txtDelete, txtUpdateOld, txtUpdateNew := map[string]bool{}, map[string]bool{}, map[string]bool{}, map[string]bool{}
+
+	// updates of taken over ownership records are added once the updates of records got their TXT records
+	var takenOverOld, takenOverNew []*endpoint.Endpoint
+	takenOverOrphans := map[string]bool{}
+	creates := []*endpoint.Endpoint{}
 	for _, r := range filteredChanges.Create {
+		if txtName, owner := im.foreignOwnership(r); owner != "" {
+			log.Warnf("Skipping creation of %s, its ownership record %s belongs to %s", r, txtName, owner)
+			continue
+		}
 		if r.Labels == nil {
 			r.Labels = make(map[string]string)
 		}
 		r.Labels[endpoint.OwnerLabelKey] = im.ownerID
-		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true))
-		filteredChanges.Create = append(filteredChanges.Create, txt)
+		creates = append(creates, r)
+		txt := im.ownershipRecord(r)
+		if !txtCreate[txt.DNSName] {
+			if value, ok := im.ownershipValues[txt.DNSName]; ok {
+				// an existing ownership record, e.g. of a record of another type on the name, an orphan
+				// or a forged one, is taken over instead of created again
+				takenOverOld = append(takenOverOld, endpoint.NewEndpoint(txt.DNSName, endpoint.RecordTypeTXT, value))
+				takenOverNew = append(takenOverNew, txt)
+				txtUpdateOld[txt.DNSName], txtUpdateNew[txt.DNSName] = true, true
+				delete(orphans, txt.DNSName)
+				takenOverOrphans[txt.DNSName] = true
+			} else {
+				creates = append(creates, txt)
+			}
+			txtCreate[txt.DNSName] = true
+			im.ownershipValues[txt.DNSName] = txt.Targets[0]
+		}
+		im.addOwnedRecord(r)
 
 		if im.cacheInterval > 0 {
 			im.addToCache(r)
 		}
 	}
+	filteredChanges.Create = creates
 
 	for _, r := range filteredChanges.Delete {
-		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true))
-
-		// when we delete TXT records for which value has changed (due to new label) this would still work because
-		// !!! TXT record value is uniquely generated from the Labels of the endpoint. Hence old TXT record can be uniquely reconstructed
-		filteredChanges.Delete = append(filteredChanges.Delete, txt)
+		// TXT records are deleted with the value they were read with, which may be in an older format.
+		// Otherwise the TXT record value is uniquely generated from the Labels of the endpoint.
+		txt := im.existingOwnershipRecord(r)
+		im.removeOwnedRecord(r)
+		// an ownership record shared by records of several types stays as long as one of them
+		if !txtDelete[txt.DNSName] && len(im.ownedRecords[txt.DNSName]) == 0 {
+			filteredChanges.Delete = append(filteredChanges.Delete, txt)
+			txtDelete[txt.DNSName] = true
+			delete(im.ownershipValues, txt.DNSName)
+		}
 
 		if im.cacheInterval > 0 {
 			im.removeFromCache(r)
@@ -151,41 +395,301 @@ func (im *TXTRegistry) ApplyChanges(changes *plan.Changes) error {
 
 	// make sure TXT records are consistently updated as well
 	for _, r := range filteredChanges.UpdateOld {
-		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true))
-		// when we updateOld TXT records for which value has changed (due to new label) this would still work because
-		// !!! TXT record value is uniquely generated from the Labels of the endpoint. Hence old TXT record can be uniquely reconstructed
-		filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, txt)
+		// like deleted TXT records, the old TXT records are the ones which were read
+		txt := im.existingOwnershipRecord(r)
+		if !txtUpdateOld[txt.DNSName] {
+			filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, txt)
+			txtUpdateOld[txt.DNSName] = true
+		}
 		// remove old version of record from cache
 		if im.cacheInterval > 0 {
 			im.removeFromCache(r)
 		}
 	}
 
+	// an ownership record shared by several updated records gets the labels of one which isn't
+	// deleted, and only keeps a tombstone if all the records it owns carry one
+	updated := map[string]*endpoint.Endpoint{}
+	tombstoned := map[string]int{}
+	for _, r := range filteredChanges.UpdateNew {
+		name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+		if hasTombstone(r) {
+			tombstoned[name]++
+		}
+		if current, ok := updated[name]; !ok || preferredLabels(r, current) {
+			updated[name] = r
+		}
+	}
+
 	// make sure TXT records are consistently updated as well
 	for _, r := range filteredChanges.UpdateNew {
-		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true))
-		filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, txt)
+		name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+		if !txtUpdateNew[name] {
+			labels := updated[name].Labels
+			if tombstoned[name] > 0 && tombstoned[name] < len(im.ownedRecords[name]) {
+				labels = withoutTombstone(labels)
+			}
+			txt := endpoint.NewEndpoint(name, endpoint.RecordTypeTXT, im.serialize(name, labels))
+			filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, txt)
+			txtUpdateNew[txt.DNSName] = true
+			im.ownershipValues[txt.DNSName] = txt.Targets[0]
+		}
 		// add new version of record to cache
 		if im.cacheInterval > 0 {
 			im.addToCache(r)
 		}
 	}
 
-	return im.provider.ApplyChanges(filteredChanges)
+	// a replaced name keeps its TXT record unless its name contains the record type, then it only
+	// has to be rewritten with the labels of the new records
+	txtReplaced := map[string]*endpoint.Endpoint{}
+
+	for _, r := range filteredChanges.ReplaceOld {
+		txt := im.existingOwnershipRecord(r)
+		if _, ok := txtReplaced[txt.DNSName]; !ok {
+			txtReplaced[txt.DNSName] = txt
+		}
+		im.removeOwnedRecord(r)
+
+		if im.cacheInterval > 0 {
+			im.removeFromCache(r)
+		}
+	}
+
+	for _, r := range filteredChanges.ReplaceNew {
+		if r.Labels == nil {
+			r.Labels = make(map[string]string)
+		}
+		r.Labels[endpoint.OwnerLabelKey] = im.ownerID
+		txt := im.ownershipRecord(r)
+		if old, ok := txtReplaced[txt.DNSName]; ok {
+			if old != nil {
+				filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, old)
+				filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, txt)
+				txtReplaced[txt.DNSName] = nil
+				im.ownershipValues[txt.DNSName] = txt.Targets[0]
+			}
+		} else if !txtCreate[txt.DNSName] {
+			filteredChanges.Create = append(filteredChanges.Create, txt)
+			txtCreate[txt.DNSName] = true
+			im.ownershipValues[txt.DNSName] = txt.Targets[0]
+		}
+		im.addOwnedRecord(r)
+
+		if im.cacheInterval > 0 {
+			im.addToCache(r)
+		}
+	}
+
+	// TXT records of replaced records which aren't rewritten are named after the old record type
+	for _, r := range filteredChanges.ReplaceOld {
+		name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+		if txt := txtReplaced[name]; txt != nil {
+			if !txtDelete[name] && len(im.ownedRecords[name]) == 0 {
+				filteredChanges.Delete = append(filteredChanges.Delete, txt)
+				txtDelete[name] = true
+				delete(im.ownershipValues, name)
+			}
+			txtReplaced[name] = nil
+		}
+	}
+
+	filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, takenOverOld...)
+	filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, takenOverNew...)
+
+	// the remaining orphans are deleted, or marked with a tombstone, unless their name is changed already
+	deletedOrphans := map[string]bool{}
+	for name, labels := range orphans {
+		value, ok := im.ownershipValues[name]
+		if !ok || txtDelete[name] || txtUpdateOld[name] {
+			continue
+		}
+		old := endpoint.NewEndpoint(name, endpoint.RecordTypeTXT, value)
+		if labels == nil {
+			filteredChanges.Delete = append(filteredChanges.Delete, old)
+			txtDelete[name] = true
+			delete(im.ownershipValues, name)
+			deletedOrphans[name] = true
+			continue
+		}
+		txt := endpoint.NewEndpoint(name, endpoint.RecordTypeTXT, im.serialize(name, labels))
+		filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, old)
+		filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, txt)
+		txtUpdateOld[name], txtUpdateNew[name] = true, true
+		im.ownershipValues[name] = txt.Targets[0]
+	}
+
+	// records of a different type can only be created once the ones they replace are gone
+	for _, stage := range filteredChanges.Stages() {
+		if err := im.provider.ApplyChanges(ctx, stage); err != nil {
+			return err
+		}
+	}
+	if len(deletedOrphans) > 0 || len(takenOverOrphans) > 0 {
+		im.removeOrphans(deletedOrphans, takenOverOrphans)
+	}
+	return nil
 }
 
 /**
   TXT registry specific private methods
 */
 
+// ownershipRecord returns the TXT record which holds the labels of the record
+func (im *TXTRegistry) ownershipRecord(r *endpoint.Endpoint) *endpoint.Endpoint {
+	name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+	return endpoint.NewEndpoint(name, endpoint.RecordTypeTXT, im.serialize(name, r.Labels))
+}
+
+// serialize returns the value of the ownership record with the name and the labels, signed if signing
+func (im *TXTRegistry) serialize(txtDNSName string, labels endpoint.Labels) string {
+	if im.signer != nil {
+		return im.signer.serialize(txtDNSName, labels)
+	}
+	return labels.SerializeTXT()
+}
+
+// existingOwnershipRecord returns the TXT record of the record with the value it was read with,
+// or the value it would be written with if it wasn't read
+func (im *TXTRegistry) existingOwnershipRecord(r *endpoint.Endpoint) *endpoint.Endpoint {
+	name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+	if value, ok := im.ownershipValues[name]; ok {
+		return endpoint.NewEndpoint(name, endpoint.RecordTypeTXT, value)
+	}
+	return im.ownershipRecord(r)
+}
+
+// addOwnedRecord adds the record to the records owned by its ownership record
+func (im *TXTRegistry) addOwnedRecord(r *endpoint.Endpoint) {
+	name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+	if im.ownedRecords[name] == nil {
+		im.ownedRecords[name] = map[ownedKey]bool{}
+	}
+	im.ownedRecords[name][ownedKey{r.DNSName, r.RecordType}] = true
+}
+
+// removeOwnedRecord removes the record from the records owned by its ownership record
+func (im *TXTRegistry) removeOwnedRecord(r *endpoint.Endpoint) {
+	name := im.mapper.toTXTName(r.DNSName, r.RecordType)
+	delete(im.ownedRecords[name], ownedKey{r.DNSName, r.RecordType})
+	if len(im.ownedRecords[name]) == 0 {
+		delete(im.ownedRecords, name)
+	}
+}
+
+// hasTombstone returns true if the record is marked as missing from the desired state
+func hasTombstone(r *endpoint.Endpoint) bool {
+	_, ok := r.Labels[endpoint.TombstoneLabelKey]
+	return ok
+}
+
+// preferredLabels returns true if the labels of the record rather than the ones of the current
+// record go into their shared ownership record: the ones of records without tombstone first, then
+// the ones of the first record type
+func preferredLabels(r, current *endpoint.Endpoint) bool {
+	if hasTombstone(r) != hasTombstone(current) {
+		return hasTombstone(current)
+	}
+	return r.RecordType < current.RecordType
+}
+
+// withoutTombstone returns a copy of the labels without the tombstone
+func withoutTombstone(labels endpoint.Labels) endpoint.Labels {
+	copied := endpoint.Labels{}
+	for k, v := range labels {
+		if k != endpoint.TombstoneLabelKey {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+// addMigration adds the changes which move the labels of the record from the TXT record in the
+// legacy layout to the one in the new layout
+func (im *TXTRegistry) addMigration(migrations *plan.Changes, migrated map[string]bool, ep *endpoint.Endpoint, labels endpoint.Labels, legacy *endpoint.Endpoint) {
+	name := im.mapper.toTXTName(ep.DNSName, ep.RecordType)
+	txt := endpoint.NewEndpoint(name, endpoint.RecordTypeTXT, im.serialize(name, labels))
+	if !migrated[txt.DNSName] {
+		migrations.Create = append(migrations.Create, txt)
+		migrated[txt.DNSName] = true
+	}
+	if !migrated[legacy.DNSName] {
+		migrations.Delete = append(migrations.Delete, legacy)
+		migrated[legacy.DNSName] = true
+	}
+}
+
+// migrate rewrites the owned ownership records which Records found in the legacy layout, or
+// without signature
+func (im *TXTRegistry) migrate(ctx context.Context) error {
+	if im.migrations == nil || len(im.migrations.Create) == 0 && len(im.migrations.UpdateNew) == 0 {
+		return nil
+	}
+
+	if len(im.migrations.Create) > 0 {
+		log.Infof("Migrating %d ownership records to the new layout", len(im.migrations.Delete))
+	}
+	if len(im.migrations.UpdateNew) > 0 {
+		log.Infof("Signing %d unsigned ownership records", len(im.migrations.UpdateNew))
+	}
+	if err := im.provider.ApplyChanges(ctx, im.migrations); err != nil {
+		return err
+	}
+	for _, txt := range im.migrations.Delete {
+		delete(im.ownershipValues, txt.DNSName)
+	}
+	for _, txt := range im.migrations.Create {
+		im.ownershipValues[txt.DNSName] = txt.Targets[0]
+	}
+	for _, txt := range im.migrations.UpdateNew {
+		im.ownershipValues[txt.DNSName] = txt.Targets[0]
+	}
+	im.migrations = nil
+	return nil
+}
+
+// ownedKey identifies the records owned by a TXT record, the record type is empty if the
+// TXT record owns the records of all types on the name
+type ownedKey struct {
+	dnsName    string
+	recordType string
+}
+
+// existingRecords returns the names and the names and types of the records
+func existingRecords(endpoints []*endpoint.Endpoint) map[ownedKey]bool {
+	existing := map[ownedKey]bool{}
+	for _, ep := range endpoints {
+		existing[ownedKey{ep.DNSName, ""}] = true
+		existing[ownedKey{ep.DNSName, ep.RecordType}] = true
+	}
+	return existing
+}
+
 /**
   nameMapper defines interface which maps the dns name defined for the source
   to the dns name which TXT record will be created with
 */
 
 type nameMapper interface {
-	toEndpointName(string) string
-	toTXTName(string) string
+	// toEndpointName returns the name of the records owned by the TXT record, and their type if the
+	// TXT record only owns the records of one type. The name is empty if the TXT record isn't one.
+	toEndpointName(txtDNSName string) (string, string)
+	toTXTName(endpointDNSName, recordType string) string
+}
+
+// newTXTNameMapper returns the nameMapper of the naming scheme
+func newTXTNameMapper(prefix, suffix string, typed bool) (nameMapper, error) {
+	var mapper nameMapper = newPrefixNameMapper(prefix)
+	if suffix != "" {
+		if prefix != "" {
+			return nil, errors.New("txt prefix and suffix are mutually exclusive")
+		}
+		mapper = newSuffixNameMapper(suffix)
+	}
+	if typed {
+		mapper = newTypedNameMapper(mapper)
+	}
+	return mapper, nil
 }
 
 type prefixNameMapper struct {
@@ -198,17 +702,94 @@ func newPrefixNameMapper(prefix string) prefixNameMapper {
 	return prefixNameMapper{prefix: prefix}
 }
 
-func (pr prefixNameMapper) toEndpointName(txtDNSName string) string {
+func (pr prefixNameMapper) toEndpointName(txtDNSName string) (string, string) {
 	if strings.HasPrefix(txtDNSName, pr.prefix) {
-		return strings.TrimPrefix(txtDNSName, pr.prefix)
+		return strings.TrimPrefix(txtDNSName, pr.prefix), ""
 	}
-	return ""
+	return "", ""
 }
 
-func (pr prefixNameMapper) toTXTName(endpointDNSName string) string {
+func (pr prefixNameMapper) toTXTName(endpointDNSName, recordType string) string {
 	return pr.prefix + endpointDNSName
 }
 
+// suffixNameMapper inserts the suffix after the first label, e.g. foo-txt.example.org, so that the
+// TXT records stay in the zone of the records without sharing their name. Wildcards keep their
+// first label, and the TXT record of the apex of a zone lands in its parent zone.
+type suffixNameMapper struct {
+	suffix string
+}
+
+var _ nameMapper = suffixNameMapper{}
+
+func newSuffixNameMapper(suffix string) suffixNameMapper {
+	return suffixNameMapper{suffix: suffix}
+}
+
+func (sr suffixNameMapper) toEndpointName(txtDNSName string) (string, string) {
+	wildcard, label, rest := splitFirstLabel(txtDNSName)
+	if label == sr.suffix || !strings.HasSuffix(label, sr.suffix) {
+		return "", ""
+	}
+	return wildcard + strings.TrimSuffix(label, sr.suffix) + rest, ""
+}
+
+func (sr suffixNameMapper) toTXTName(endpointDNSName, recordType string) string {
+	wildcard, label, rest := splitFirstLabel(endpointDNSName)
+	return wildcard + label + sr.suffix + rest
+}
+
+// typedNameMapper prefixes the first label of the names of the wrapped mapper with the record
+// type, e.g. a-foo.example.org or cname-foo.example.org
+type typedNameMapper struct {
+	mapper nameMapper
+}
+
+var _ nameMapper = typedNameMapper{}
+
+func newTypedNameMapper(mapper nameMapper) typedNameMapper {
+	return typedNameMapper{mapper: mapper}
+}
+
+func (tm typedNameMapper) toEndpointName(txtDNSName string) (string, string) {
+	name, _ := tm.mapper.toEndpointName(txtDNSName)
+	wildcard, label, rest := splitFirstLabel(name)
+	parts := strings.SplitN(label, "-", 2)
+	if len(parts) != 2 || parts[1] == "" || !typedRecordTypes[strings.ToUpper(parts[0])] {
+		return "", ""
+	}
+	return wildcard + parts[1] + rest, strings.ToUpper(parts[0])
+}
+
+func (tm typedNameMapper) toTXTName(endpointDNSName, recordType string) string {
+	wildcard, label, rest := splitFirstLabel(endpointDNSName)
+	return tm.mapper.toTXTName(wildcard+strings.ToLower(recordType)+"-"+label+rest, recordType)
+}
+
+// typedRecordTypes are the record types the typedNameMapper recognizes in the names of TXT records
+var typedRecordTypes = map[string]bool{
+	endpoint.RecordTypeA:     true,
+	endpoint.RecordTypeAAAA:  true,
+	endpoint.RecordTypeCNAME: true,
+	endpoint.RecordTypeTXT:   true,
+	endpoint.RecordTypeSRV:   true,
+	endpoint.RecordTypeMX:    true,
+	endpoint.RecordTypeNS:    true,
+	endpoint.RecordTypeCAA:   true,
+	endpoint.RecordTypePTR:   true,
+}
+
+// splitFirstLabel splits a DNS name into the wildcard label, if any, the first other label and the rest
+func splitFirstLabel(dnsName string) (wildcard, label, rest string) {
+	if strings.HasPrefix(dnsName, "*.") {
+		wildcard, dnsName = "*.", strings.TrimPrefix(dnsName, "*.")
+	}
+	if i := strings.Index(dnsName, "."); i >= 0 {
+		return wildcard, dnsName[:i], dnsName[i:]
+	}
+	return wildcard, dnsName, ""
+}
+
 func (im *TXTRegistry) addToCache(ep *endpoint.Endpoint) {
 	if im.recordsCache != nil {
 		im.recordsCache = append(im.recordsCache, ep)
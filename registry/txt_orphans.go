@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/plan"
+)
+
+var (
+	orphanedRecords = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "orphaned_records",
+			Help:      "Number of owned ownership records without any of the records they own",
+		},
+	)
+	orphansDeletedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "orphaned_records_deleted_total",
+			Help:      "Number of orphaned ownership records deleted",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(orphanedRecords)
+	prometheus.MustRegister(orphansDeletedTotal)
+}
+
+// TXTWithOrphanDeletion returns the owned ownership records which don't own any records anymore as
+// orphans, see OrphanFinder, so that they are deleted. In dry-run, the orphans the provider only
+// pretends to delete are neither forgotten nor counted as deleted.
+func TXTWithOrphanDeletion(dryRun bool) TXTOption {
+	return func(im *TXTRegistry) {
+		im.deleteOrphans = true
+		im.orphansDryRun = dryRun
+	}
+}
+
+// Orphans returns the owned ownership records without records found by the last call of Records,
+// if deleting them is enabled. They are TXT records labeled like the records they owned.
+func (im *TXTRegistry) Orphans() []*endpoint.Endpoint {
+	if !im.deleteOrphans {
+		return nil
+	}
+	return im.orphans
+}
+
+// orphanRecord returns the orphan of the ownership record with the labels
+func orphanRecord(txt *endpoint.Endpoint, labels endpoint.Labels) *endpoint.Endpoint {
+	orphan := endpoint.NewEndpoint(txt.DNSName, endpoint.RecordTypeTXT, txt.Targets...)
+	for k, v := range labels {
+		orphan.Labels[k] = v
+	}
+	return orphan
+}
+
+// splitOrphans separates the changes of the orphans returned by Orphans from the changes of records,
+// which include managed TXT records. The changes of orphans are returned by name: deleted ones map
+// to nil, updated ones, e.g. marked with a tombstone, to their new labels.
+func (im *TXTRegistry) splitOrphans(changes *plan.Changes) (*plan.Changes, map[string]endpoint.Labels) {
+	orphans := map[string]endpoint.Labels{}
+	if !im.deleteOrphans || len(im.orphans) == 0 {
+		return changes, orphans
+	}
+	values := map[string]string{}
+	for _, orphan := range im.orphans {
+		values[orphan.DNSName] = orphan.Targets[0]
+	}
+	records := func(eps []*endpoint.Endpoint, orphan func(ep *endpoint.Endpoint)) []*endpoint.Endpoint {
+		filtered := []*endpoint.Endpoint{}
+		for _, ep := range eps {
+			if value, ok := values[ep.DNSName]; ok && ep.RecordType == endpoint.RecordTypeTXT && len(ep.Targets) == 1 && ep.Targets[0] == value {
+				orphan(ep)
+				continue
+			}
+			filtered = append(filtered, ep)
+		}
+		return filtered
+	}
+
+	return &plan.Changes{
+		Create: changes.Create,
+		Delete: records(changes.Delete, func(ep *endpoint.Endpoint) {
+			orphans[ep.DNSName] = nil
+		}),
+		UpdateOld: records(changes.UpdateOld, func(*endpoint.Endpoint) {}),
+		UpdateNew: records(changes.UpdateNew, func(ep *endpoint.Endpoint) {
+			orphans[ep.DNSName] = ep.Labels
+		}),
+		ReplaceOld: changes.ReplaceOld,
+		ReplaceNew: changes.ReplaceNew,
+	}, orphans
+}
+
+// removeOrphans forgets the deleted and the taken over orphans, so that they aren't returned again
+// from the cache
+func (im *TXTRegistry) removeOrphans(deleted, takenOver map[string]bool) {
+	if im.orphansDryRun {
+		return
+	}
+	remaining := []*endpoint.Endpoint{}
+	for _, orphan := range im.orphans {
+		if !deleted[orphan.DNSName] && !takenOver[orphan.DNSName] {
+			remaining = append(remaining, orphan)
+		}
+	}
+	im.orphans = remaining
+	orphanedRecords.Set(float64(len(remaining)))
+	orphansDeletedTotal.Add(float64(len(deleted)))
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/internal/testutils"
+	"github.com/kubernetes-incubator/external-dns/plan"
+	"github.com/kubernetes-incubator/external-dns/provider"
+)
+
+func newOrphanTestProvider() *provider.InMemoryProvider {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("txt.gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/gone\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("txt.back.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("txt.foreign.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	return p
+}
+
+func TestTXTRegistryOrphans(t *testing.T) {
+	p := newOrphanTestProvider()
+
+	r, err := NewTXTRegistry(p, "txt.", "owner", time.Hour)
+	require.NoError(t, err)
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, r.Orphans(), "should only return orphans if deleting them")
+
+	r, err = NewTXTRegistry(p, "txt.", "owner", time.Hour, TXTWithOrphanDeletion(false))
+	require.NoError(t, err)
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+	}))
+	assert.True(t, testutils.SameEndpoints(r.Orphans(), []*endpoint.Endpoint{
+		newEndpointWithOwnerResource("txt.gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/gone\"", endpoint.RecordTypeTXT, "owner", "ingress/default/gone"),
+		newEndpointWithOwner("txt.back.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, "owner"),
+	}), "should only return the owned ownership records without records")
+
+	// orphans are deleted with their value, a recreated record takes its orphan over
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("back.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+		},
+		Delete: r.Orphans(),
+	}
+	expected := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("back.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, "owner"),
+		},
+		UpdateOld: []*endpoint.Endpoint{
+			newEndpointWithOwner("txt.back.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			newEndpointWithOwner("txt.back.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+		Delete: []*endpoint.Endpoint{
+			newEndpointWithOwner("txt.gone.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/gone\"", endpoint.RecordTypeTXT, ""),
+		},
+	}
+	p.OnApplyChanges = func(got *plan.Changes) {
+		mExpected := map[string][]*endpoint.Endpoint{
+			"Create":    expected.Create,
+			"UpdateNew": expected.UpdateNew,
+			"UpdateOld": expected.UpdateOld,
+			"Delete":    expected.Delete,
+		}
+		mGot := map[string][]*endpoint.Endpoint{
+			"Create":    got.Create,
+			"UpdateNew": got.UpdateNew,
+			"UpdateOld": got.UpdateOld,
+			"Delete":    got.Delete,
+		}
+		assert.True(t, testutils.SamePlanChanges(mGot, mExpected))
+	}
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+	assert.Empty(t, r.Orphans(), "should forget the deleted orphans")
+}
+
+func TestTXTRegistryOrphansDryRun(t *testing.T) {
+	p := newOrphanTestProvider()
+	r, err := NewTXTRegistry(p, "txt.", "owner", time.Hour, TXTWithOrphanDeletion(true))
+	require.NoError(t, err)
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{Delete: r.Orphans()}))
+	assert.Len(t, r.Orphans(), 2, "should keep the orphans only pretended to be deleted")
+}
+
+func TestTXTRegistryOrphansTombstoned(t *testing.T) {
+	p := newOrphanTestProvider()
+	r, err := NewTXTRegistry(p, "txt.", "owner", 0, TXTWithOrphanDeletion(false))
+	require.NoError(t, err)
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+
+	// policies which delay deletions mark the orphans with tombstones instead
+	policy := &plan.DelayedDeletionPolicy{GraceCycles: 2}
+	changes := (&plan.Plan{
+		Policies: []plan.Policy{policy},
+		Orphans:  r.Orphans(),
+	}).Calculate().Changes
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, r.Orphans(), 2)
+	for _, orphan := range r.Orphans() {
+		assert.NotEmpty(t, orphan.Labels[endpoint.TombstoneLabelKey], orphan.DNSName)
+	}
+
+	changes = (&plan.Plan{
+		Policies: []plan.Policy{policy},
+		Orphans:  r.Orphans(),
+	}).Calculate().Changes
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+
+	_, err = r.Records(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, r.Orphans())
+
+	all, err := p.Records(context.Background())
+	require.NoError(t, err)
+	for _, record := range all {
+		assert.NotContains(t, []string{"txt.gone.test-zone.example.org", "txt.back.test-zone.example.org"}, record.DNSName)
+	}
+}
+
+func TestTXTRegistryManagedTXTRecords(t *testing.T) {
+	for _, opts := range [][]TXTOption{nil, {TXTWithOrphanDeletion(false)}} {
+		p := newOrphanTestProvider()
+		p.ApplyChanges(context.Background(), &plan.Changes{
+			Create: []*endpoint.Endpoint{
+				newEndpointWithOwner("note.test-zone.example.org", "hello", endpoint.RecordTypeTXT, ""),
+				newEndpointWithOwner("txt.note.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			},
+		})
+		r, err := NewTXTRegistry(p, "txt.", "owner", 0, opts...)
+		require.NoError(t, err)
+
+		// owned TXT records are updated and deleted like records of any other type
+		records, err := r.Records(context.Background())
+		require.NoError(t, err)
+		var note *endpoint.Endpoint
+		for _, record := range records {
+			if record.DNSName == "note.test-zone.example.org" {
+				note = record
+			}
+		}
+		require.NotNil(t, note)
+		updated := newEndpointWithOwner("note.test-zone.example.org", "world", endpoint.RecordTypeTXT, "owner")
+		require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
+			UpdateOld: []*endpoint.Endpoint{note},
+			UpdateNew: []*endpoint.Endpoint{updated},
+		}))
+
+		records, err = r.Records(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, records, newEndpointWithOwner("note.test-zone.example.org", "world", endpoint.RecordTypeTXT, "owner"))
+
+		require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
+			Delete: []*endpoint.Endpoint{updated},
+		}))
+		all, err := p.Records(context.Background())
+		require.NoError(t, err)
+		for _, record := range all {
+			assert.NotContains(t, []string{"note.test-zone.example.org", "txt.note.test-zone.example.org"}, record.DNSName)
+		}
+	}
+}
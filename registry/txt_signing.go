@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
+
+// LoadTXTSigningKeys reads the keys to sign ownership records with from the files, one key per
+// file, e.g. the keys of a mounted Secret. Surrounding whitespace is not part of a key.
+func LoadTXTSigningKeys(files []string) ([][]byte, error) {
+	keys := make([][]byte, 0, len(files))
+	for _, file := range files {
+		key, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TXT signing key: %v", err)
+		}
+		key = bytes.TrimSpace(key)
+		if len(key) == 0 {
+			return nil, fmt.Errorf("TXT signing key file %s is empty", file)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// txtSigner signs the values of ownership records with HMAC-SHA256 over the name and the labels of
+// the TXT record, so that a value can't be copied to another name. The first key signs, all of
+// them verify, so that keys can be rotated.
+type txtSigner struct {
+	keys [][]byte
+}
+
+func newTXTSigner(keys [][]byte) (*txtSigner, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("signing ownership records requires a key")
+	}
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, errors.New("TXT signing keys cannot be empty")
+		}
+	}
+	return &txtSigner{keys: keys}, nil
+}
+
+// serialize returns the signed value of the TXT record with the labels
+func (s *txtSigner) serialize(txtDNSName string, labels endpoint.Labels) string {
+	return labels.SerializeSignedTXT(func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString(mac(s.keys[0], txtDNSName, payload))
+	})
+}
+
+// verify returns whether the value of the TXT record is signed with one of the keys, and
+// whether it is signed at all
+func (s *txtSigner) verify(txtDNSName, value string) (valid bool, signed bool) {
+	payload, signature := endpoint.SplitTXTSignature(value)
+	if signature == "" {
+		return false, false
+	}
+	sum, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false, true
+	}
+	for _, key := range s.keys {
+		if hmac.Equal(sum, mac(key, txtDNSName, payload)) {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func mac(key []byte, txtDNSName, payload string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(strings.ToLower(strings.TrimSuffix(txtDNSName, "."))))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
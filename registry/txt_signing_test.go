@@ -0,0 +1,186 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/kubernetes-incubator/external-dns/internal/testutils"
+	"github.com/kubernetes-incubator/external-dns/plan"
+	"github.com/kubernetes-incubator/external-dns/provider"
+)
+
+func TestLoadTXTSigningKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txt-signing")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key := filepath.Join(dir, "key")
+	require.NoError(t, ioutil.WriteFile(key, []byte("secret\n"), 0600))
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, ioutil.WriteFile(empty, []byte("\n"), 0600))
+
+	keys, err := LoadTXTSigningKeys([]string{key})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("secret")}, keys)
+
+	_, err = LoadTXTSigningKeys([]string{key, empty})
+	assert.Error(t, err)
+	_, err = LoadTXTSigningKeys([]string{filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
+
+func TestTXTSigner(t *testing.T) {
+	_, err := newTXTSigner(nil)
+	assert.Error(t, err)
+	_, err = newTXTSigner([][]byte{[]byte("new"), {}})
+	assert.Error(t, err)
+
+	old, err := newTXTSigner([][]byte{[]byte("old")})
+	require.NoError(t, err)
+	rotated, err := newTXTSigner([][]byte{[]byte("new"), []byte("old")})
+	require.NoError(t, err)
+
+	labels := endpoint.Labels{endpoint.OwnerLabelKey: "owner"}
+	value := old.serialize("foo.example.org", labels)
+
+	for _, tc := range []struct {
+		title  string
+		signer *txtSigner
+		name   string
+		value  string
+		valid  bool
+		signed bool
+	}{
+		{"signed", old, "foo.example.org", value, true, true},
+		{"name in other case", old, "FOO.example.org.", value, true, true},
+		{"rotated key", rotated, "foo.example.org", value, true, true},
+		{"copied to another name", old, "bar.example.org", value, false, true},
+		{"tampered labels", old, "foo.example.org", strings.Replace(value, "owner=owner", "owner=other", 1), false, true},
+		{"invalid signature", old, "foo.example.org", strings.TrimSuffix(value, `"`) + `!"`, false, true},
+		{"unsigned", old, "foo.example.org", labels.SerializeTXT(), false, false},
+		{"unversioned", old, "foo.example.org", labels.Serialize(true), false, false},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			valid, signed := tc.signer.verify(tc.name, tc.value)
+			assert.Equal(t, tc.valid, valid)
+			assert.Equal(t, tc.signed, signed)
+		})
+	}
+
+	// the new key signs once it is the first one
+	_, signed := old.verify("foo.example.org", rotated.serialize("foo.example.org", labels))
+	assert.True(t, signed)
+	valid, _ := old.verify("foo.example.org", rotated.serialize("foo.example.org", labels))
+	assert.False(t, valid)
+}
+
+func TestTXTRegistrySigning(t *testing.T) {
+	signer, err := newTXTSigner([][]byte{[]byte("secret")})
+	require.NoError(t, err)
+	owned := endpoint.Labels{endpoint.OwnerLabelKey: "owner"}
+
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.foo.test-zone.example.org", signer.serialize("txt.foo.test-zone.example.org", owned), endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("forged.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.forged.test-zone.example.org", signer.serialize("txt.foo.test-zone.example.org", owned), endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("unsigned.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.unsigned.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("other.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.other.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+
+	// records with a missing or invalid signature have no owner
+	r, err := NewTXTRegistry(p, "txt.", "owner", 0, TXTWithSigningKeys([][]byte{[]byte("secret")}))
+	require.NoError(t, err)
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("forged.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+		newEndpointWithOwner("unsigned.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+		newEndpointWithOwner("other.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+	}))
+
+	// new ownership records are signed, forged ones are taken over
+	p.OnApplyChanges = func(got *plan.Changes) {
+		for _, txt := range append(got.Create, got.UpdateNew...) {
+			if txt.RecordType == endpoint.RecordTypeTXT {
+				valid, _ := signer.verify(txt.DNSName, txt.Targets[0])
+				assert.True(t, valid, txt.DNSName)
+			}
+		}
+	}
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("new.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("forged.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+		},
+	}))
+	p.OnApplyChanges = func(*plan.Changes) {}
+
+	// unsigned records are trusted and the owned ones signed while accepting them
+	_, err = NewTXTRegistry(p, "txt.", "owner", 0, TXTWithUnsignedAccepted())
+	assert.Error(t, err, "should require keys")
+	r, err = NewTXTRegistry(p, "txt.", "owner", 0, TXTWithSigningKeys([][]byte{[]byte("secret")}), TXTWithUnsignedAccepted())
+	require.NoError(t, err)
+	expected := []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("forged.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("forged.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+		newEndpointWithOwner("unsigned.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("other.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "other"),
+		newEndpointWithOwner("new.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+	}
+	records, err = r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, expected))
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{}))
+
+	all, err := p.Records(context.Background())
+	require.NoError(t, err)
+	for _, record := range all {
+		if record.RecordType != endpoint.RecordTypeTXT {
+			continue
+		}
+		_, signed := signer.verify(record.DNSName, record.Targets[0])
+		assert.Equal(t, record.DNSName != "txt.other.test-zone.example.org", signed, record.DNSName)
+	}
+
+	// and stay owned once the unsigned ones aren't accepted anymore
+	r, err = NewTXTRegistry(p, "txt.", "owner", time.Hour, TXTWithSigningKeys([][]byte{[]byte("secret")}))
+	require.NoError(t, err)
+	records, err = r.Records(context.Background())
+	require.NoError(t, err)
+	expected[4] = newEndpointWithOwner("other.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "")
+	assert.True(t, testutils.SameEndpoints(records, expected))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package registry
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -58,6 +59,17 @@ func testTXTRegistryNew(t *testing.T) {
 
 	_, ok = r.mapper.(prefixNameMapper)
 	assert.True(t, ok)
+
+	r, err = NewTXTRegistry(p, "", "owner", time.Hour, TXTWithSuffix("-txt"), TXTWithRecordType(), TXTWithMigration())
+	require.NoError(t, err)
+	assert.Equal(t, newTypedNameMapper(newSuffixNameMapper("-txt")), r.mapper)
+	assert.Equal(t, newPrefixNameMapper(""), r.legacyMapper)
+
+	_, err = NewTXTRegistry(p, "txt.", "owner", time.Hour, TXTWithSuffix("-txt"))
+	assert.Error(t, err)
+
+	_, err = NewTXTRegistry(p, "txt.", "owner", time.Hour, TXTWithMigration())
+	assert.Error(t, err)
 }
 
 func testTXTRegistryRecords(t *testing.T) {
@@ -68,7 +80,7 @@ func testTXTRegistryRecords(t *testing.T) {
 func testTXTRegistryRecordsPrefixed(t *testing.T) {
 	p := provider.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	p.ApplyChanges(&plan.Changes{
+	p.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwner("foo.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, ""),
 			newEndpointWithOwner("bar.test-zone.example.org", "my-domain.com", endpoint.RecordTypeCNAME, ""),
@@ -133,7 +145,7 @@ func testTXTRegistryRecordsPrefixed(t *testing.T) {
 	}
 
 	r, _ := NewTXTRegistry(p, "txt.", "owner", time.Hour)
-	records, _ := r.Records()
+	records, _ := r.Records(context.Background())
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 }
@@ -141,7 +153,7 @@ func testTXTRegistryRecordsPrefixed(t *testing.T) {
 func testTXTRegistryRecordsNoPrefix(t *testing.T) {
 	p := provider.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	p.ApplyChanges(&plan.Changes{
+	p.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwner("foo.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, ""),
 			newEndpointWithOwner("bar.test-zone.example.org", "my-domain.com", endpoint.RecordTypeCNAME, ""),
@@ -207,7 +219,7 @@ func testTXTRegistryRecordsNoPrefix(t *testing.T) {
 	}
 
 	r, _ := NewTXTRegistry(p, "", "owner", time.Hour)
-	records, _ := r.Records()
+	records, _ := r.Records(context.Background())
 
 	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
 }
@@ -215,12 +227,15 @@ func testTXTRegistryRecordsNoPrefix(t *testing.T) {
 func testTXTRegistryApplyChanges(t *testing.T) {
 	t.Run("With Prefix", testTXTRegistryApplyChangesWithPrefix)
 	t.Run("No prefix", testTXTRegistryApplyChangesNoPrefix)
+	t.Run("With Multiple Types", testTXTRegistryApplyChangesMultipleTypes)
+	t.Run("With Shared Ownership Record", testTXTRegistryApplyChangesSharedOwnership)
+	t.Run("With Type Change", testTXTRegistryApplyChangesTypeChange)
 }
 
 func testTXTRegistryApplyChangesWithPrefix(t *testing.T) {
 	p := provider.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	p.ApplyChanges(&plan.Changes{
+	p.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwner("foo.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, ""),
 			newEndpointWithOwner("bar.test-zone.example.org", "my-domain.com", endpoint.RecordTypeCNAME, ""),
@@ -252,7 +267,7 @@ func testTXTRegistryApplyChangesWithPrefix(t *testing.T) {
 	expected := &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwnerResource("new-record-1.test-zone.example.org", "new-loadbalancer-1.lb.com", "", "owner", "ingress/default/my-ingress"),
-			newEndpointWithOwner("txt.new-record-1.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/my-ingress\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("txt.new-record-1.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner,external-dns/resource=ingress/default/my-ingress\"", endpoint.RecordTypeTXT, ""),
 		},
 		Delete: []*endpoint.Endpoint{
 			newEndpointWithOwner("foobar.test-zone.example.org", "foobar.loadbalancer.com", endpoint.RecordTypeCNAME, "owner"),
@@ -260,7 +275,7 @@ func testTXTRegistryApplyChangesWithPrefix(t *testing.T) {
 		},
 		UpdateNew: []*endpoint.Endpoint{
 			newEndpointWithOwnerResource("tar.test-zone.example.org", "new-tar.loadbalancer.com", endpoint.RecordTypeCNAME, "owner", "ingress/default/my-ingress-2"),
-			newEndpointWithOwner("txt.tar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=ingress/default/my-ingress-2\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("txt.tar.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner,external-dns/resource=ingress/default/my-ingress-2\"", endpoint.RecordTypeTXT, ""),
 		},
 		UpdateOld: []*endpoint.Endpoint{
 			newEndpointWithOwner("tar.test-zone.example.org", "tar.loadbalancer.com", endpoint.RecordTypeCNAME, "owner"),
@@ -282,14 +297,16 @@ func testTXTRegistryApplyChangesWithPrefix(t *testing.T) {
 		}
 		assert.True(t, testutils.SamePlanChanges(mGot, mExpected))
 	}
-	err := r.ApplyChanges(changes)
+	_, err := r.Records(context.Background())
+	require.NoError(t, err)
+	err = r.ApplyChanges(context.Background(), changes)
 	require.NoError(t, err)
 }
 
 func testTXTRegistryApplyChangesNoPrefix(t *testing.T) {
 	p := provider.NewInMemoryProvider()
 	p.CreateZone(testZone)
-	p.ApplyChanges(&plan.Changes{
+	p.ApplyChanges(context.Background(), &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwner("foo.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, ""),
 			newEndpointWithOwner("bar.test-zone.example.org", "my-domain.com", endpoint.RecordTypeCNAME, ""),
@@ -321,7 +338,7 @@ func testTXTRegistryApplyChangesNoPrefix(t *testing.T) {
 	expected := &plan.Changes{
 		Create: []*endpoint.Endpoint{
 			newEndpointWithOwner("new-record-1.test-zone.example.org", "new-loadbalancer-1.lb.com", endpoint.RecordTypeCNAME, "owner"),
-			newEndpointWithOwner("new-record-1.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("new-record-1.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
 		},
 		Delete: []*endpoint.Endpoint{
 			newEndpointWithOwner("foobar.test-zone.example.org", "foobar.loadbalancer.com", endpoint.RecordTypeCNAME, "owner"),
@@ -345,8 +362,237 @@ func testTXTRegistryApplyChangesNoPrefix(t *testing.T) {
 		}
 		assert.True(t, testutils.SamePlanChanges(mGot, mExpected))
 	}
-	err := r.ApplyChanges(changes)
+	_, err := r.Records(context.Background())
+	require.NoError(t, err)
+	err = r.ApplyChanges(context.Background(), changes)
+	require.NoError(t, err)
+}
+
+func testTXTRegistryApplyChangesMultipleTypes(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	r, _ := NewTXTRegistry(p, "txt.", "owner", time.Hour)
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+		},
+	}
+	expected := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+			newEndpointWithOwner("txt.dual.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+		Delete:    []*endpoint.Endpoint{},
+		UpdateNew: []*endpoint.Endpoint{},
+		UpdateOld: []*endpoint.Endpoint{},
+	}
+	p.OnApplyChanges = func(got *plan.Changes) {
+		mExpected := map[string][]*endpoint.Endpoint{
+			"Create":    expected.Create,
+			"UpdateNew": expected.UpdateNew,
+			"UpdateOld": expected.UpdateOld,
+			"Delete":    expected.Delete,
+		}
+		mGot := map[string][]*endpoint.Endpoint{
+			"Create":    got.Create,
+			"UpdateNew": got.UpdateNew,
+			"UpdateOld": got.UpdateOld,
+			"Delete":    got.Delete,
+		}
+		assert.True(t, testutils.SamePlanChanges(mGot, mExpected))
+	}
+	_, err := r.Records(context.Background())
+	require.NoError(t, err)
+	err = r.ApplyChanges(context.Background(), changes)
+	require.NoError(t, err)
+}
+
+func testTXTRegistryApplyChangesSharedOwnership(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.dual.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("foreign.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.foreign.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	r, _ := NewTXTRegistry(p, "txt.", "owner", time.Hour)
+
+	// a record of another type on a name takes the existing ownership record over, records on names
+	// owned by others aren't created
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+			newEndpointWithOwner("foreign.test-zone.example.org", "2001:db8::2", endpoint.RecordTypeAAAA, ""),
+		},
+	}
+	expected := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+		},
+		UpdateOld: []*endpoint.Endpoint{
+			newEndpointWithOwner("txt.dual.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			newEndpointWithOwner("txt.dual.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+		Delete: []*endpoint.Endpoint{},
+	}
+	p.OnApplyChanges = func(got *plan.Changes) {
+		mExpected := map[string][]*endpoint.Endpoint{
+			"Create":    expected.Create,
+			"UpdateNew": expected.UpdateNew,
+			"UpdateOld": expected.UpdateOld,
+			"Delete":    expected.Delete,
+		}
+		mGot := map[string][]*endpoint.Endpoint{
+			"Create":    got.Create,
+			"UpdateNew": got.UpdateNew,
+			"UpdateOld": got.UpdateOld,
+			"Delete":    got.Delete,
+		}
+		assert.True(t, testutils.SamePlanChanges(mGot, mExpected))
+	}
+	_, err := r.Records(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+
+	// the ownership record stays as long as any of the records it owns
+	changes = &plan.Changes{
+		Delete: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		},
+	}
+	expected = &plan.Changes{
+		Create:    []*endpoint.Endpoint{},
+		UpdateOld: []*endpoint.Endpoint{},
+		UpdateNew: []*endpoint.Endpoint{},
+		Delete: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		},
+	}
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+
+	changes = &plan.Changes{
+		Delete: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+		},
+	}
+	expected = &plan.Changes{
+		Create:    []*endpoint.Endpoint{},
+		UpdateOld: []*endpoint.Endpoint{},
+		UpdateNew: []*endpoint.Endpoint{},
+		Delete: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+			newEndpointWithOwner("txt.dual.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	}
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+}
+
+func TestTXTRegistrySharedOwnershipTombstone(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("dual.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+			newEndpointWithOwner("txt.dual.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	r, _ := NewTXTRegistry(p, "txt.", "owner", 0)
+	_, err := r.Records(context.Background())
+	require.NoError(t, err)
+
+	// a tombstone of one of the records doesn't go into the ownership record shared with the other
+	tombstoned := newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner")
+	tombstoned.Labels[endpoint.TombstoneLabelKey] = "2018-01-01T00:00:00Z"
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{newEndpointWithOwner("dual.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner")},
+		UpdateNew: []*endpoint.Endpoint{tombstoned},
+	}))
+
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		assert.NotContains(t, record.Labels, endpoint.TombstoneLabelKey)
+	}
+}
+
+func testTXTRegistryApplyChangesTypeChange(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("txt.bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	r, _ := NewTXTRegistry(p, "txt.", "owner", time.Hour)
+
+	changes := &plan.Changes{
+		ReplaceOld: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+			newEndpointWithOwner("bar.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "other"),
+		},
+		ReplaceNew: []*endpoint.Endpoint{
+			newEndpointWithOwnerResource("foo.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, "", "ingress/default/my-ingress"),
+			newEndpointWithOwnerResource("bar.test-zone.example.org", "bar.loadbalancer.com", endpoint.RecordTypeCNAME, "", "ingress/default/my-ingress"),
+		},
+	}
+	expected := []*plan.Changes{
+		{
+			Create:    []*endpoint.Endpoint{},
+			UpdateNew: []*endpoint.Endpoint{},
+			UpdateOld: []*endpoint.Endpoint{},
+			Delete: []*endpoint.Endpoint{
+				newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+			},
+		},
+		{
+			Create: []*endpoint.Endpoint{
+				newEndpointWithOwnerResource("foo.test-zone.example.org", "foo.loadbalancer.com", endpoint.RecordTypeCNAME, "owner", "ingress/default/my-ingress"),
+			},
+			UpdateNew: []*endpoint.Endpoint{
+				newEndpointWithOwner("txt.foo.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner,external-dns/resource=ingress/default/my-ingress\"", endpoint.RecordTypeTXT, ""),
+			},
+			UpdateOld: []*endpoint.Endpoint{
+				newEndpointWithOwner("txt.foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			},
+			Delete: []*endpoint.Endpoint{},
+		},
+	}
+	stage := 0
+	p.OnApplyChanges = func(got *plan.Changes) {
+		require.True(t, stage < len(expected), "unexpected call to ApplyChanges")
+		mExpected := map[string][]*endpoint.Endpoint{
+			"Create":    expected[stage].Create,
+			"UpdateNew": expected[stage].UpdateNew,
+			"UpdateOld": expected[stage].UpdateOld,
+			"Delete":    expected[stage].Delete,
+		}
+		mGot := map[string][]*endpoint.Endpoint{
+			"Create":    got.Create,
+			"UpdateNew": got.UpdateNew,
+			"UpdateOld": got.UpdateOld,
+			"Delete":    got.Delete,
+		}
+		assert.True(t, testutils.SamePlanChanges(mGot, mExpected))
+		stage++
+	}
+	_, err := r.Records(context.Background())
+	require.NoError(t, err)
+	err = r.ApplyChanges(context.Background(), changes)
 	require.NoError(t, err)
+	assert.Equal(t, len(expected), stage)
 }
 
 func TestCacheMethods(t *testing.T) {
@@ -410,6 +656,214 @@ func TestCacheMethods(t *testing.T) {
 	}
 }
 
+func TestTXTNameMappers(t *testing.T) {
+	for _, tc := range []struct {
+		title      string
+		mapper     nameMapper
+		dnsName    string
+		recordType string
+		txtName    string
+	}{
+		{"prefix", newPrefixNameMapper("txt."), "foo.example.org", endpoint.RecordTypeA, "txt.foo.example.org"},
+		{"suffix", newSuffixNameMapper("-txt"), "foo.example.org", endpoint.RecordTypeA, "foo-txt.example.org"},
+		{"suffix wildcard", newSuffixNameMapper("-txt"), "*.foo.example.org", endpoint.RecordTypeA, "*.foo-txt.example.org"},
+		{"typed", newTypedNameMapper(newPrefixNameMapper("")), "foo.example.org", endpoint.RecordTypeCNAME, "cname-foo.example.org"},
+		{"typed prefix", newTypedNameMapper(newPrefixNameMapper("txt.")), "foo.example.org", endpoint.RecordTypeAAAA, "txt.aaaa-foo.example.org"},
+		{"typed suffix", newTypedNameMapper(newSuffixNameMapper("-txt")), "foo.example.org", endpoint.RecordTypeA, "a-foo-txt.example.org"},
+		{"typed wildcard", newTypedNameMapper(newPrefixNameMapper("")), "*.example.org", endpoint.RecordTypeA, "*.a-example.org"},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.txtName, tc.mapper.toTXTName(tc.dnsName, tc.recordType))
+
+			dnsName, recordType := tc.mapper.toEndpointName(tc.txtName)
+			assert.Equal(t, tc.dnsName, dnsName)
+			if _, typed := tc.mapper.(typedNameMapper); typed {
+				assert.Equal(t, tc.recordType, recordType)
+			} else {
+				assert.Empty(t, recordType)
+			}
+		})
+	}
+
+	// names which don't follow the scheme aren't TXT records of the mapper
+	for _, txtName := range []string{"foo.example.org", "unknown-foo.example.org", "a-.example.org"} {
+		name, _ := newTypedNameMapper(newPrefixNameMapper("")).toEndpointName(txtName)
+		assert.Empty(t, name, txtName)
+	}
+	for _, txtName := range []string{"foo.example.org", "-txt.example.org"} {
+		name, _ := newSuffixNameMapper("-txt").toEndpointName(txtName)
+		assert.Empty(t, name, txtName)
+	}
+}
+
+func TestTXTRegistryTyped(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+			newEndpointWithOwner("a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("aaaa-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, ""),
+			newEndpointWithOwner("cname-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	r, err := NewTXTRegistry(p, "", "owner", time.Hour, TXTWithRecordType())
+	require.NoError(t, err)
+
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "other"),
+		newEndpointWithOwner("bar.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, "owner"),
+	}))
+
+	// a replaced record gets the TXT record of its new type
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("baz.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, ""),
+		},
+		ReplaceOld: []*endpoint.Endpoint{
+			newEndpointWithOwner("bar.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, "owner"),
+		},
+		ReplaceNew: []*endpoint.Endpoint{
+			newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+		},
+	}
+	expected := []*plan.Changes{
+		{
+			Delete: []*endpoint.Endpoint{
+				newEndpointWithOwner("bar.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, "owner"),
+			},
+		},
+		{
+			Create: []*endpoint.Endpoint{
+				newEndpointWithOwner("baz.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, "owner"),
+				newEndpointWithOwner("cname-baz.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+				newEndpointWithOwner("a-bar.test-zone.example.org", "\"heritage=external-dns,version=2,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+				newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, "owner"),
+			},
+			Delete: []*endpoint.Endpoint{
+				newEndpointWithOwner("cname-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			},
+		},
+	}
+	stage := 0
+	p.OnApplyChanges = func(got *plan.Changes) {
+		require.True(t, stage < len(expected))
+		assert.True(t, testutils.SameEndpoints(got.Create, expected[stage].Create), "create of stage %d", stage)
+		assert.True(t, testutils.SameEndpoints(got.Delete, expected[stage].Delete), "delete of stage %d", stage)
+		assert.Empty(t, got.UpdateOld)
+		assert.Empty(t, got.UpdateNew)
+		stage++
+	}
+	require.NoError(t, r.ApplyChanges(context.Background(), changes))
+	assert.Equal(t, len(expected), stage)
+}
+
+func TestTXTRegistryMigration(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, ""),
+			newEndpointWithOwner("foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("baz.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, ""),
+			newEndpointWithOwner("cname-baz-txt.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	r, err := NewTXTRegistry(p, "", "owner", 0, TXTWithSuffix("-txt"), TXTWithRecordType(), TXTWithMigration())
+	require.NoError(t, err)
+
+	// records in both layouts are owned
+	expectedRecords := []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("foo.test-zone.example.org", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+		newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, "other"),
+		newEndpointWithOwner("baz.test-zone.example.org", "lb.example.com", endpoint.RecordTypeCNAME, "owner"),
+	}
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
+
+	// the owned records of the legacy layout are rewritten, the ones of other owners are left alone
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{}))
+
+	all, err := p.Records(context.Background())
+	require.NoError(t, err)
+	txtNames := []string{}
+	for _, record := range all {
+		if record.RecordType == endpoint.RecordTypeTXT {
+			txtNames = append(txtNames, record.DNSName)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"a-foo-txt.test-zone.example.org",
+		"aaaa-foo-txt.test-zone.example.org",
+		"bar.test-zone.example.org",
+		"cname-baz-txt.test-zone.example.org",
+	}, txtNames)
+
+	records, err = r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, expectedRecords))
+	assert.Empty(t, r.migrations.Create)
+}
+
+func TestTXTRegistryVersionedFormat(t *testing.T) {
+	p := provider.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "\"heritage=external-dns,version=3,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+	r, err := NewTXTRegistry(p, "", "owner", time.Hour)
+	require.NoError(t, err)
+
+	// records of an unknown version are neither owned nor fail the synchronization
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+	}))
+
+	// the legacy TXT record is updated with the value it was read with, labels with separators survive
+	updated := newEndpointWithOwnerResource("foo.test-zone.example.org", "4.3.2.1", endpoint.RecordTypeA, "owner", "service/default/a,b=c")
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner")},
+		UpdateNew: []*endpoint.Endpoint{updated},
+	}))
+
+	r, err = NewTXTRegistry(p, "", "owner", time.Hour)
+	require.NoError(t, err)
+	records, err = r.Records(context.Background())
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwnerResource("foo.test-zone.example.org", "4.3.2.1", endpoint.RecordTypeA, "owner", "service/default/a,b=c"),
+		newEndpointWithOwner("bar.test-zone.example.org", "5.6.7.8", endpoint.RecordTypeA, ""),
+	}))
+
+	// the versioned TXT record is deleted with its value too
+	require.NoError(t, r.ApplyChanges(context.Background(), &plan.Changes{
+		Delete: []*endpoint.Endpoint{updated},
+	}))
+	all, err := p.Records(context.Background())
+	require.NoError(t, err)
+	for _, record := range all {
+		assert.NotEqual(t, "foo.test-zone.example.org", record.DNSName)
+	}
+}
+
 /**
 
 helper methods
@@ -17,6 +17,7 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"encoding/gob"
 	"net"
 	"time"
@@ -43,15 +44,19 @@ func NewConnectorSource(remoteServer string) (Source, error) {
 }
 
 // Endpoints returns endpoint objects.
-func (cs *connectorSource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (cs *connectorSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	endpoints := []*endpoint.Endpoint{}
 
-	conn, err := net.DialTimeout("tcp", cs.remoteServer, dialTimeout)
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", cs.remoteServer)
 	if err != nil {
 		log.Errorf("Connection error: %v", err)
 		return nil, err
 	}
 	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
 
 	decoder := gob.NewDecoder(conn)
 	if err := decoder.Decode(&endpoints); err != nil {
@@ -63,3 +68,7 @@ func (cs *connectorSource) Endpoints() ([]*endpoint.Endpoint, error) {
 
 	return endpoints, nil
 }
+
+// AddEventHandler does nothing, the remote server can't notify about changes.
+func (cs *connectorSource) AddEventHandler(handler func()) {
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -108,19 +109,27 @@ func NewCRDSource(crdClient rest.Interface, namespace, kind string, scheme *runt
 }
 
 // Endpoints returns endpoint objects.
-func (cs *crdSource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (cs *crdSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	endpoints := []*endpoint.Endpoint{}
 
-	result, err := cs.List(&metav1.ListOptions{})
+	result, err := cs.List(ctx, &metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
 	for _, dnsEndpoint := range result.Items {
-		endpoints = append(endpoints, dnsEndpoint.Spec.Endpoints...)
+		for _, ep := range dnsEndpoint.Spec.Endpoints {
+			ep.DNSName = endpoint.NormalizeDNSName(ep.DNSName)
+			// invalid structured targets are kept, so that the controller rejects the endpoint
+			// without deleting the record it wants
+			if err := ep.MergeStructuredTargets(); err != nil {
+				log.Debugf("Unable to merge the structured targets of endpoint %s of %s/%s: %v", ep.DNSName, dnsEndpoint.Namespace, dnsEndpoint.Name, err)
+			}
+			endpoints = append(endpoints, ep)
+		}
 		dnsEndpoint.Status.ObservedGeneration = dnsEndpoint.Generation
 		// Update the ObservedGeneration
-		_, err = cs.UpdateStatus(&dnsEndpoint)
+		_, err = cs.UpdateStatus(ctx, &dnsEndpoint)
 		if err != nil {
 			log.Warnf("Could not update ObservedGeneration of the CRD: %v", err)
 		}
@@ -129,9 +138,14 @@ func (cs *crdSource) Endpoints() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
-func (cs *crdSource) List(opts *metav1.ListOptions) (result *endpoint.DNSEndpointList, err error) {
+// AddEventHandler does nothing, DNSEndpoint objects are not watched.
+func (cs *crdSource) AddEventHandler(handler func()) {
+}
+
+func (cs *crdSource) List(ctx context.Context, opts *metav1.ListOptions) (result *endpoint.DNSEndpointList, err error) {
 	result = &endpoint.DNSEndpointList{}
 	err = cs.crdClient.Get().
+		Context(ctx).
 		Namespace(cs.namespace).
 		Resource(cs.crdResource).
 		VersionedParams(opts, cs.codec).
@@ -140,9 +154,10 @@ func (cs *crdSource) List(opts *metav1.ListOptions) (result *endpoint.DNSEndpoin
 	return
 }
 
-func (cs *crdSource) UpdateStatus(dnsEndpoint *endpoint.DNSEndpoint) (result *endpoint.DNSEndpoint, err error) {
+func (cs *crdSource) UpdateStatus(ctx context.Context, dnsEndpoint *endpoint.DNSEndpoint) (result *endpoint.DNSEndpoint, err error) {
 	result = &endpoint.DNSEndpoint{}
 	err = cs.crdClient.Put().
+		Context(ctx).
 		Namespace(dnsEndpoint.Namespace).
 		Resource(cs.crdResource).
 		Name(dnsEndpoint.Name).
@@ -18,6 +18,7 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -123,6 +124,7 @@ func testCRDSourceEndpoints(t *testing.T) {
 		registeredKind       string
 		kind                 string
 		endpoints            []*endpoint.Endpoint
+		expected             []*endpoint.Endpoint
 		expectEndpoints      bool
 		expectError          bool
 	}{
@@ -231,6 +233,65 @@ func testCRDSourceEndpoints(t *testing.T) {
 			expectEndpoints: true,
 			expectError:     false,
 		},
+		{
+			title:                "valid crd gvk with structured targets",
+			registeredAPIVersion: "test.k8s.io/v1alpha1",
+			apiVersion:           "test.k8s.io/v1alpha1",
+			registeredKind:       "DNSEndpoint",
+			kind:                 "DNSEndpoint",
+			endpoints: []*endpoint.Endpoint{
+				{DNSName: "_sip._tcp.example.org",
+					Targets:    endpoint.Targets{"10 20 5060 sip1.example.org"},
+					SRVTargets: []endpoint.SRVTarget{endpoint.NewSRVTarget(10, 20, 5060, "sip2.example.org")},
+					RecordType: endpoint.RecordTypeSRV,
+					RecordTTL:  180,
+				},
+				{DNSName: "_invalid._tcp.example.org",
+					SRVTargets: []endpoint.SRVTarget{endpoint.NewSRVTarget(10, 20, 5060, "")},
+					RecordType: endpoint.RecordTypeSRV,
+					RecordTTL:  180,
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "_sip._tcp.example.org",
+					Targets:    endpoint.Targets{"10 20 5060 sip1.example.org", "10 20 5060 sip2.example.org"},
+					RecordType: endpoint.RecordTypeSRV,
+					RecordTTL:  180,
+				},
+				{DNSName: "_invalid._tcp.example.org",
+					RecordType: endpoint.RecordTypeSRV,
+					RecordTTL:  180,
+				},
+			},
+			expectEndpoints: true,
+			expectError:     false,
+		},
+		{
+			title:                "invalid endpoints are passed on to be rejected by the controller",
+			registeredAPIVersion: "test.k8s.io/v1alpha1",
+			apiVersion:           "test.k8s.io/v1alpha1",
+			registeredKind:       "DNSEndpoint",
+			kind:                 "DNSEndpoint",
+			endpoints: []*endpoint.Endpoint{
+				{DNSName: "abc.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+					RecordTTL:  180,
+				},
+				{DNSName: "in_valid.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+					RecordTTL:  180,
+				},
+				{DNSName: "xyz.example.org",
+					Targets:    endpoint.Targets{"not-an-ip"},
+					RecordType: endpoint.RecordTypeA,
+					RecordTTL:  180,
+				},
+			},
+			expectEndpoints: true,
+			expectError:     false,
+		},
 	} {
 		t.Run(ti.title, func(t *testing.T) {
 			restClient := startCRDServerToServeTargets(ti.endpoints, ti.registeredAPIVersion, ti.registeredKind, ti.registeredNamespace, "")
@@ -242,7 +303,7 @@ func testCRDSourceEndpoints(t *testing.T) {
 
 			cs, _ := NewCRDSource(restClient, ti.namespace, ti.kind, scheme)
 
-			receivedEndpoints, err := cs.Endpoints()
+			receivedEndpoints, err := cs.Endpoints(context.Background())
 			if ti.expectError {
 				require.Errorf(t, err, "Received err %v", err)
 			} else {
@@ -254,7 +315,11 @@ func testCRDSourceEndpoints(t *testing.T) {
 			}
 
 			// Validate received endpoints against expected endpoints.
-			validateEndpoints(t, receivedEndpoints, ti.endpoints)
+			expected := ti.expected
+			if expected == nil {
+				expected = ti.endpoints
+			}
+			validateEndpoints(t, receivedEndpoints, expected)
 		})
 	}
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package source
 
 import (
+	"context"
+
 	log "github.com/sirupsen/logrus"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -33,17 +35,17 @@ func NewDedupSource(source Source) Source {
 }
 
 // Endpoints collects endpoints from its wrapped source and returns them without duplicates.
-func (ms *dedupSource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (ms *dedupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	result := []*endpoint.Endpoint{}
 	collected := map[string]bool{}
 
-	endpoints, err := ms.source.Endpoints()
+	endpoints, err := ms.source.Endpoints(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, ep := range endpoints {
-		identifier := ep.DNSName + " / " + ep.Targets.String()
+		identifier := ep.DNSName + " / " + ep.RecordType + " / " + endpoint.NewTargetSet(ep.RecordType, ep.Targets).String()
 
 		if _, ok := collected[identifier]; ok {
 			log.Debugf("Removing duplicate endpoint %s", ep)
@@ -56,3 +58,8 @@ func (ms *dedupSource) Endpoints() ([]*endpoint.Endpoint, error) {
 
 	return result, nil
 }
+
+// AddEventHandler adds the handler to the wrapped Source.
+func (ms *dedupSource) AddEventHandler(handler func()) {
+	ms.source.AddEventHandler(handler)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"testing"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -89,6 +90,37 @@ func testDedupEndpoints(t *testing.T) {
 				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}},
 			},
 		},
+		{
+			"two endpoints with same dnsname and same targets in different order return one endpoint",
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4", "4.5.6.7"}},
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"4.5.6.7", "1.2.3.4"}},
+			},
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4", "4.5.6.7"}},
+			},
+		},
+		{
+			"two endpoints with same dnsname and equivalent hostname targets return one endpoint",
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.example.org"}},
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"LB.example.org."}},
+			},
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.example.org"}},
+			},
+		},
+		{
+			"two endpoints with same dnsname and same target but different record types return two endpoints",
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.example.org"}},
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeTXT, Targets: endpoint.Targets{"lb.example.org"}},
+			},
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.example.org"}},
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeTXT, Targets: endpoint.Targets{"lb.example.org"}},
+			},
+		},
 	} {
 		t.Run(tc.title, func(t *testing.T) {
 			mockSource := new(testutils.MockSource)
@@ -97,7 +129,7 @@ func testDedupEndpoints(t *testing.T) {
 			// Create our object under test and get the endpoints.
 			source := NewDedupSource(mockSource)
 
-			endpoints, err := source.Endpoints()
+			endpoints, err := source.Endpoints(context.Background())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -21,6 +21,7 @@ Note: currently only supports IP targets (A records), not hostname targets
 package source
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net"
@@ -55,7 +56,7 @@ func NewFakeSource(fqdnTemplate string) (Source, error) {
 }
 
 // Endpoints returns endpoint objects.
-func (sc *fakeSource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (sc *fakeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	endpoints := make([]*endpoint.Endpoint, 10)
 
 	for i := 0; i < 10; i++ {
@@ -65,6 +66,10 @@ func (sc *fakeSource) Endpoints() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
+// AddEventHandler does nothing, the fake endpoints change on every call of Endpoints.
+func (sc *fakeSource) AddEventHandler(handler func()) {
+}
+
 func (sc *fakeSource) generateEndpoint() (*endpoint.Endpoint, error) {
 	ep := endpoint.NewEndpoint(
 		generateDNSName(4, sc.dnsName),
@@ -18,6 +18,7 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -90,7 +91,7 @@ func NewIstioGatewaySource(
 
 // Endpoints returns endpoint objects for each host-target combination that should be processed.
 // Retrieves all gateway resources in the source's namespace(s).
-func (sc *gatewaySource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (sc *gatewaySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	configs, err := sc.istioClient.List(istiomodel.Gateway.Type, sc.namespace)
 	if err != nil {
 		return nil, err
@@ -148,6 +149,10 @@ func (sc *gatewaySource) Endpoints() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
+// AddEventHandler does nothing, Gateway objects are not watched.
+func (sc *gatewaySource) AddEventHandler(handler func()) {
+}
+
 func (sc *gatewaySource) endpointsFromTemplate(config *istiomodel.Config) ([]*endpoint.Endpoint, error) {
 	// Process the whole template string
 	var buf bytes.Buffer
@@ -219,6 +224,8 @@ func (sc *gatewaySource) setResourceLabel(config istiomodel.Config, endpoints []
 	for _, ep := range endpoints {
 		ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("gateway/%s/%s", config.Namespace, config.Name)
 	}
+	setConflictLabels(endpoints, config.CreationTimestamp.Time, config.Annotations)
+	setDampingLabel(endpoints, config.Annotations)
 }
 
 func (sc *gatewaySource) targetsFromIstioIngressStatus() (targets endpoint.Targets, err error) {
@@ -17,6 +17,7 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"testing"
 
 	istionetworking "istio.io/api/networking/v1alpha3"
@@ -81,7 +82,7 @@ func (suite *GatewaySuite) SetupTest() {
 }
 
 func (suite *GatewaySuite) TestResourceLabelIsSet() {
-	endpoints, _ := suite.source.Endpoints()
+	endpoints, _ := suite.source.Endpoints(context.Background())
 	for _, ep := range endpoints {
 		suite.Equal("gateway/default/foo-gateway-with-targets", ep.Labels[endpoint.ResourceLabelKey], "should set correct resource label")
 	}
@@ -942,7 +943,7 @@ func testGatewayEndpoints(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			res, err := gatewaySource.Endpoints()
+			res, err := gatewaySource.Endpoints(context.Background())
 			if ti.expectError {
 				assert.Error(t, err)
 			} else {
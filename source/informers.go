@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// The time to wait for the caches of the informers to be populated
+	informerSyncTimeout = 60 * time.Second
+)
+
+// waitForCacheSync waits until the caches of the informers are populated
+func waitForCacheSync(synced ...cache.InformerSynced) error {
+	stopChan := make(chan struct{})
+	timer := time.AfterFunc(informerSyncTimeout, func() { close(stopChan) })
+	defer timer.Stop()
+
+	if !cache.WaitForCacheSync(stopChan, synced...) {
+		return fmt.Errorf("timed out waiting for caches to sync after %s", informerSyncTimeout)
+	}
+	return nil
+}
+
+// changedFunc reports whether an update of an object is relevant for the endpoints of a source
+type changedFunc func(oldObj, newObj interface{}) bool
+
+// eventHandlerFuncs returns informer event handlers which call handler on any addition
+// or deletion, and on updates for which changed reports a relevant change
+func eventHandlerFuncs(handler func(), changed changedFunc) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			handler()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if changed(oldObj, newObj) {
+				handler()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			handler()
+		},
+	}
+}
+
+// metadataChanged reports whether the labels or annotations of an object differ
+func metadataChanged(oldMeta, newMeta metav1.Object) bool {
+	return !labels.Equals(oldMeta.GetLabels(), newMeta.GetLabels()) ||
+		!labels.Equals(oldMeta.GetAnnotations(), newMeta.GetAnnotations())
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eventRecorder returns an event handler and a channel receiving a value for each call of the handler
+func eventRecorder() (func(), <-chan struct{}) {
+	events := make(chan struct{}, 100)
+	return func() { events <- struct{}{} }, events
+}
+
+// expectEvent fails the test if no event is received in time
+func expectEvent(t *testing.T, events <-chan struct{}) {
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the event handler to be called")
+	}
+}
+
+func TestEventHandlerFuncs(t *testing.T) {
+	handler, events := eventRecorder()
+	changed := true
+	funcs := eventHandlerFuncs(handler, func(oldObj, newObj interface{}) bool { return changed })
+
+	funcs.OnAdd(nil)
+	funcs.OnUpdate(nil, nil)
+	funcs.OnDelete(nil)
+	assert.Len(t, events, 3)
+
+	changed = false
+	funcs.OnUpdate(nil, nil)
+	assert.Len(t, events, 3, "unchanged updates should be ignored")
+}
+
+func TestMetadataChanged(t *testing.T) {
+	meta := &metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}}
+	assert.False(t, metadataChanged(meta, &metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}, ResourceVersion: "2"}))
+	assert.True(t, metadataChanged(meta, &metav1.ObjectMeta{Labels: map[string]string{"app": "bar"}}))
+	assert.True(t, metadataChanged(meta, &metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}, Annotations: map[string]string{hostnameAnnotationKey: "foo.example.org"}}))
+}
+
+func TestWaitForCacheSync(t *testing.T) {
+	assert.NoError(t, waitForCacheSync(func() bool { return true }))
+}
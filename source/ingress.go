@@ -18,7 +18,9 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"text/template"
@@ -28,6 +30,9 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	extinformers "k8s.io/client-go/informers/extensions/v1beta1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -37,12 +42,13 @@ import (
 // Ingress implementation will use the spec.rules.host value for the hostname
 // Use targetAnnotationKey to explicitly set Endpoint. (useful if the ingress
 // controller does not update, or to override with alternative endpoint)
+// Ingresses are read from the cache of a shared informer.
 type ingressSource struct {
-	client                kubernetes.Interface
 	namespace             string
 	annotationFilter      string
 	fqdnTemplate          *template.Template
 	combineFQDNAnnotation bool
+	ingressInformer       extinformers.IngressInformer
 }
 
 // NewIngressSource creates a new ingressSource with the given config.
@@ -60,30 +66,40 @@ func NewIngressSource(kubeClient kubernetes.Interface, namespace, annotationFilt
 		}
 	}
 
+	// Use a shared informer to listen for add/update/delete of ingresses in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	ingressInformer := informerFactory.Extensions().V1beta1().Ingresses()
+
+	informerFactory.Start(wait.NeverStop)
+	if err := waitForCacheSync(ingressInformer.Informer().HasSynced); err != nil {
+		return nil, err
+	}
+
 	return &ingressSource{
-		client:                kubeClient,
 		namespace:             namespace,
 		annotationFilter:      annotationFilter,
 		fqdnTemplate:          tmpl,
 		combineFQDNAnnotation: combineFqdnAnnotation,
+		ingressInformer:       ingressInformer,
 	}, nil
 }
 
 // Endpoints returns endpoint objects for each host-target combination that should be processed.
 // Retrieves all ingress resources on all namespaces
-func (sc *ingressSource) Endpoints() ([]*endpoint.Endpoint, error) {
-	ingresses, err := sc.client.Extensions().Ingresses(sc.namespace).List(metav1.ListOptions{})
+func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	ingresses, err := sc.ingressInformer.Lister().Ingresses(sc.namespace).List(labels.Everything())
 	if err != nil {
 		return nil, err
 	}
-	ingresses.Items, err = sc.filterByAnnotations(ingresses.Items)
+	ingresses, err = sc.filterByAnnotations(ingresses)
 	if err != nil {
 		return nil, err
 	}
 
 	endpoints := []*endpoint.Endpoint{}
 
-	for _, ing := range ingresses.Items {
+	for _, ing := range ingresses {
 		// Check controller annotation to see if we are responsible.
 		controller, ok := ing.Annotations[controllerAnnotationKey]
 		if ok && controller != controllerAnnotationValue {
@@ -92,11 +108,11 @@ func (sc *ingressSource) Endpoints() ([]*endpoint.Endpoint, error) {
 			continue
 		}
 
-		ingEndpoints := endpointsFromIngress(&ing)
+		ingEndpoints := endpointsFromIngress(ing)
 
 		// apply template if host is missing on ingress
 		if (sc.combineFQDNAnnotation || len(ingEndpoints) == 0) && sc.fqdnTemplate != nil {
-			iEndpoints, err := sc.endpointsFromTemplate(&ing)
+			iEndpoints, err := sc.endpointsFromTemplate(ing)
 			if err != nil {
 				return nil, err
 			}
@@ -125,6 +141,27 @@ func (sc *ingressSource) Endpoints() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
+// AddEventHandler calls the handler whenever ingresses change.
+func (sc *ingressSource) AddEventHandler(handler func()) {
+	log.Debug("Adding event handler for ingresses")
+	sc.ingressInformer.Informer().AddEventHandler(eventHandlerFuncs(handler, ingressChanged))
+}
+
+// ingressChanged reports whether the spec, status, labels or annotations of an ingress changed
+func ingressChanged(oldObj, newObj interface{}) bool {
+	oldIng, ok := oldObj.(*v1beta1.Ingress)
+	if !ok {
+		return true
+	}
+	newIng, ok := newObj.(*v1beta1.Ingress)
+	if !ok {
+		return true
+	}
+	return metadataChanged(oldIng, newIng) ||
+		!reflect.DeepEqual(oldIng.Spec, newIng.Spec) ||
+		!reflect.DeepEqual(oldIng.Status, newIng.Status)
+}
+
 func (sc *ingressSource) endpointsFromTemplate(ing *v1beta1.Ingress) ([]*endpoint.Endpoint, error) {
 	// Process the whole template string
 	var buf bytes.Buffer
@@ -159,7 +196,7 @@ func (sc *ingressSource) endpointsFromTemplate(ing *v1beta1.Ingress) ([]*endpoin
 }
 
 // filterByAnnotations filters a list of ingresses by a given annotation selector.
-func (sc *ingressSource) filterByAnnotations(ingresses []v1beta1.Ingress) ([]v1beta1.Ingress, error) {
+func (sc *ingressSource) filterByAnnotations(ingresses []*v1beta1.Ingress) ([]*v1beta1.Ingress, error) {
 	labelSelector, err := metav1.ParseToLabelSelector(sc.annotationFilter)
 	if err != nil {
 		return nil, err
@@ -174,7 +211,7 @@ func (sc *ingressSource) filterByAnnotations(ingresses []v1beta1.Ingress) ([]v1b
 		return ingresses, nil
 	}
 
-	filteredList := []v1beta1.Ingress{}
+	filteredList := []*v1beta1.Ingress{}
 
 	for _, ingress := range ingresses {
 		// convert the ingress' annotations to an equivalent label selector
@@ -189,10 +226,12 @@ func (sc *ingressSource) filterByAnnotations(ingresses []v1beta1.Ingress) ([]v1b
 	return filteredList, nil
 }
 
-func (sc *ingressSource) setResourceLabel(ingress v1beta1.Ingress, endpoints []*endpoint.Endpoint) {
+func (sc *ingressSource) setResourceLabel(ingress *v1beta1.Ingress, endpoints []*endpoint.Endpoint) {
 	for _, ep := range endpoints {
 		ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("ingress/%s/%s", ingress.Namespace, ingress.Name)
 	}
+	setConflictLabels(endpoints, ingress.CreationTimestamp.Time, ingress.Annotations)
+	setDampingLabel(endpoints, ingress.Annotations)
 }
 
 // endpointsFromIngress extracts the endpoints from ingress object
@@ -17,6 +17,7 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"testing"
 
 	"k8s.io/api/core/v1"
@@ -44,15 +45,6 @@ func (suite *IngressSuite) SetupTest() {
 	fakeClient := fake.NewSimpleClientset()
 	var err error
 
-	suite.sc, err = NewIngressSource(
-		fakeClient,
-		"",
-		"",
-		"{{.Name}}",
-		false,
-	)
-	suite.NoError(err, "should initialize ingress source")
-
 	suite.fooWithTargets = (fakeIngress{
 		name:      "foo-with-targets",
 		namespace: "default",
@@ -62,10 +54,19 @@ func (suite *IngressSuite) SetupTest() {
 	}).Ingress()
 	_, err = fakeClient.Extensions().Ingresses(suite.fooWithTargets.Namespace).Create(suite.fooWithTargets)
 	suite.NoError(err, "should succeed")
+
+	suite.sc, err = NewIngressSource(
+		fakeClient,
+		"",
+		"",
+		"{{.Name}}",
+		false,
+	)
+	suite.NoError(err, "should initialize ingress source")
 }
 
 func (suite *IngressSuite) TestResourceLabelIsSet() {
-	endpoints, _ := suite.sc.Endpoints()
+	endpoints, _ := suite.sc.Endpoints(context.Background())
 	for _, ep := range endpoints {
 		suite.Equal("ingress/default/foo-with-targets", ep.Labels[endpoint.ResourceLabelKey], "should set correct resource label")
 	}
@@ -77,6 +78,32 @@ func TestIngress(t *testing.T) {
 	t.Run("Endpoints", testIngressEndpoints)
 }
 
+// TestIngressSourceEventHandler tests that changes of ingresses are reported to the event handler.
+func TestIngressSourceEventHandler(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ingressSource, err := NewIngressSource(fakeClient, "", "", "", false)
+	require.NoError(t, err)
+
+	handler, events := eventRecorder()
+	ingressSource.AddEventHandler(handler)
+
+	ingress := (fakeIngress{
+		name:      "foo",
+		namespace: "default",
+		dnsnames:  []string{"foo.example.org"},
+		ips:       []string{"8.8.8.8"},
+	}).Ingress()
+	_, err = fakeClient.Extensions().Ingresses(ingress.Namespace).Create(ingress)
+	require.NoError(t, err)
+	expectEvent(t, events)
+
+	endpoints, err := ingressSource.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+	})
+}
+
 func TestNewIngressSource(t *testing.T) {
 	for _, ti := range []struct {
 		title                    string
@@ -215,6 +242,21 @@ func testEndpointsFromIngress(t *testing.T) {
 	}
 }
 
+func TestEndpointsFromIngressOwnProviderSpecific(t *testing.T) {
+	ingress := (fakeIngress{
+		dnsnames:    []string{"*.example.org", "www.example.org"},
+		hostnames:   []string{"lb.com"},
+		annotations: map[string]string{aliasAnnotationKey: "true"},
+	}).Ingress()
+
+	endpoints := endpointsFromIngress(ingress)
+	require.Len(t, endpoints, 2)
+	// adjusting the properties of the wildcard host leaves the other host alone
+	endpoints[0].WithProviderSpecific("cloudflare/proxied", "false")
+	delete(endpoints[0].ProviderSpecific, "alias")
+	assert.Equal(t, endpoint.ProviderSpecific{"alias": "true"}, endpoints[1].ProviderSpecific)
+}
+
 func testIngressEndpoints(t *testing.T) {
 	namespace := "testing"
 	for _, ti := range []struct {
@@ -942,6 +984,11 @@ func testIngressEndpoints(t *testing.T) {
 			}
 
 			fakeClient := fake.NewSimpleClientset()
+			for _, ingress := range ingresses {
+				_, err := fakeClient.Extensions().Ingresses(ingress.Namespace).Create(ingress)
+				require.NoError(t, err)
+			}
+
 			ingressSource, _ := NewIngressSource(
 				fakeClient,
 				ti.targetNamespace,
@@ -949,12 +996,8 @@ func testIngressEndpoints(t *testing.T) {
 				ti.fqdnTemplate,
 				ti.combineFQDNAndAnnotation,
 			)
-			for _, ingress := range ingresses {
-				_, err := fakeClient.Extensions().Ingresses(ingress.Namespace).Create(ingress)
-				require.NoError(t, err)
-			}
 
-			res, err := ingressSource.Endpoints()
+			res, err := ingressSource.Endpoints(context.Background())
 			if ti.expectError {
 				assert.Error(t, err)
 			} else {
@@ -16,7 +16,11 @@ limitations under the License.
 
 package source
 
-import "github.com/kubernetes-incubator/external-dns/endpoint"
+import (
+	"context"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+)
 
 // multiSource is a Source that merges the endpoints of its nested Sources.
 type multiSource struct {
@@ -24,11 +28,11 @@ type multiSource struct {
 }
 
 // Endpoints collects endpoints of all nested Sources and returns them in a single slice.
-func (ms *multiSource) Endpoints() ([]*endpoint.Endpoint, error) {
+func (ms *multiSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	result := []*endpoint.Endpoint{}
 
 	for _, s := range ms.children {
-		endpoints, err := s.Endpoints()
+		endpoints, err := s.Endpoints(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -39,6 +43,13 @@ func (ms *multiSource) Endpoints() ([]*endpoint.Endpoint, error) {
 	return result, nil
 }
 
+// AddEventHandler adds the handler to all nested Sources.
+func (ms *multiSource) AddEventHandler(handler func()) {
+	for _, s := range ms.children {
+		s.AddEventHandler(handler)
+	}
+}
+
 // NewMultiSource creates a new multiSource.
 func NewMultiSource(children []Source) Source {
 	return &multiSource{children: children}
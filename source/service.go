@@ -18,9 +18,12 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	log "github.com/sirupsen/logrus"
@@ -29,7 +32,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 )
@@ -42,9 +50,10 @@ const (
 // It will find all services that are under our jurisdiction, i.e. annotated
 // desired hostname and matching or no controller annotation. For each of the
 // matched services' entrypoints it will return a corresponding
-// Endpoint object.
+// Endpoint object. Services, pods and nodes are read from the caches of
+// shared informers instead of being listed on every call. The pod informer
+// is only started once a headless service has to be processed.
 type serviceSource struct {
-	client           kubernetes.Interface
 	namespace        string
 	annotationFilter string
 	// process Services with legacy annotations
@@ -54,6 +63,17 @@ type serviceSource struct {
 	publishInternal       bool
 	publishHostIP         bool
 	serviceTypeFilter     map[string]struct{}
+	informerFactory       kubeinformers.SharedInformerFactory
+	serviceInformer       coreinformers.ServiceInformer
+	// nil if nodes can't be listed, NodePort services are skipped then
+	nodeInformer coreinformers.NodeInformer
+
+	// guards podInformer and handlers
+	podMutex sync.Mutex
+	// nil until the first headless service is processed
+	podInformer coreinformers.PodInformer
+	// the event handlers to add to the pod informer once it is started
+	handlers []func()
 }
 
 // NewServiceSource creates a new serviceSource with the given config.
@@ -78,8 +98,31 @@ func NewServiceSource(kubeClient kubernetes.Interface, namespace, annotationFilt
 		serviceTypes[serviceType] = struct{}{}
 	}
 
+	// Use shared informers to listen for add/update/delete of services and nodes in the specified namespace.
+	// Pods are only watched once headless services are in use, see pods.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	serviceInformer := informerFactory.Core().V1().Services()
+	synced := []cache.InformerSynced{serviceInformer.Informer().HasSynced}
+
+	var nodeInformer coreinformers.NodeInformer
+	if _, err := kubeClient.CoreV1().Nodes().List(metav1.ListOptions{Limit: 1}); err != nil {
+		if !errors.IsForbidden(err) {
+			return nil, err
+		}
+		// Continue without nodes, as it makes sense to publish the other services.
+		log.Debugf("Unable to list nodes (Forbidden), NodePort services will be skipped")
+	} else {
+		nodeInformer = informerFactory.Core().V1().Nodes()
+		synced = append(synced, nodeInformer.Informer().HasSynced)
+	}
+
+	informerFactory.Start(wait.NeverStop)
+	if err := waitForCacheSync(synced...); err != nil {
+		return nil, err
+	}
+
 	return &serviceSource{
-		client:                kubeClient,
 		namespace:             namespace,
 		annotationFilter:      annotationFilter,
 		compatibility:         compatibility,
@@ -88,23 +131,26 @@ func NewServiceSource(kubeClient kubernetes.Interface, namespace, annotationFilt
 		publishInternal:       publishInternal,
 		publishHostIP:         publishHostIP,
 		serviceTypeFilter:     serviceTypes,
+		informerFactory:       informerFactory,
+		serviceInformer:       serviceInformer,
+		nodeInformer:          nodeInformer,
 	}, nil
 }
 
 // Endpoints returns endpoint objects for each service that should be processed.
-func (sc *serviceSource) Endpoints() ([]*endpoint.Endpoint, error) {
-	services, err := sc.client.CoreV1().Services(sc.namespace).List(metav1.ListOptions{})
+func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	services, err := sc.serviceInformer.Lister().Services(sc.namespace).List(labels.Everything())
 	if err != nil {
 		return nil, err
 	}
-	services.Items, err = sc.filterByAnnotations(services.Items)
+	services, err = sc.filterByAnnotations(services)
 	if err != nil {
 		return nil, err
 	}
 
 	// filter on service types if at least one has been provided
 	if len(sc.serviceTypeFilter) > 0 {
-		services.Items = sc.filterByServiceType(services.Items)
+		services = sc.filterByServiceType(services)
 	}
 
 	// get the ip addresses of all the nodes and cache them for this run
@@ -115,7 +161,7 @@ func (sc *serviceSource) Endpoints() ([]*endpoint.Endpoint, error) {
 
 	endpoints := []*endpoint.Endpoint{}
 
-	for _, svc := range services.Items {
+	for _, svc := range services {
 		// Check controller annotation to see if we are responsible.
 		controller, ok := svc.Annotations[controllerAnnotationKey]
 		if ok && controller != controllerAnnotationValue {
@@ -124,16 +170,16 @@ func (sc *serviceSource) Endpoints() ([]*endpoint.Endpoint, error) {
 			continue
 		}
 
-		svcEndpoints := sc.endpoints(&svc, nodeTargets)
+		svcEndpoints := sc.endpoints(svc, nodeTargets)
 
 		// process legacy annotations if no endpoints were returned and compatibility mode is enabled.
 		if len(svcEndpoints) == 0 && sc.compatibility != "" {
-			svcEndpoints = legacyEndpointsFromService(&svc, sc.compatibility)
+			svcEndpoints = legacyEndpointsFromService(svc, sc.compatibility)
 		}
 
 		// apply template if none of the above is found
 		if (sc.combineFQDNAnnotation || len(svcEndpoints) == 0) && sc.fqdnTemplate != nil {
-			sEndpoints, err := sc.endpointsFromTemplate(&svc, nodeTargets)
+			sEndpoints, err := sc.endpointsFromTemplate(svc, nodeTargets)
 			if err != nil {
 				return nil, err
 			}
@@ -162,16 +208,106 @@ func (sc *serviceSource) Endpoints() ([]*endpoint.Endpoint, error) {
 	return endpoints, nil
 }
 
+// AddEventHandler calls the handler whenever services, pods or nodes change.
+func (sc *serviceSource) AddEventHandler(handler func()) {
+	log.Debug("Adding event handler for services")
+	sc.serviceInformer.Informer().AddEventHandler(eventHandlerFuncs(handler, serviceChanged))
+	if sc.nodeInformer != nil {
+		sc.nodeInformer.Informer().AddEventHandler(eventHandlerFuncs(handler, nodeChanged))
+	}
+
+	sc.podMutex.Lock()
+	defer sc.podMutex.Unlock()
+	if sc.podInformer != nil {
+		sc.podInformer.Informer().AddEventHandler(eventHandlerFuncs(handler, podChanged))
+	}
+	sc.handlers = append(sc.handlers, handler)
+}
+
+// pods returns the lister of the pod informer. The informer is started on the
+// first call, so that pods are only watched when headless services are in use.
+func (sc *serviceSource) pods() (corelisters.PodLister, error) {
+	sc.podMutex.Lock()
+	defer sc.podMutex.Unlock()
+
+	if sc.podInformer == nil {
+		log.Debug("Starting to watch pods for headless services")
+		sc.podInformer = sc.informerFactory.Core().V1().Pods()
+		for _, handler := range sc.handlers {
+			sc.podInformer.Informer().AddEventHandler(eventHandlerFuncs(handler, podChanged))
+		}
+		sc.informerFactory.Start(wait.NeverStop)
+	}
+	if err := waitForCacheSync(sc.podInformer.Informer().HasSynced); err != nil {
+		return nil, err
+	}
+	return sc.podInformer.Lister(), nil
+}
+
+// serviceChanged reports whether the spec, status, labels or annotations of a service changed
+func serviceChanged(oldObj, newObj interface{}) bool {
+	oldSvc, ok := oldObj.(*v1.Service)
+	if !ok {
+		return true
+	}
+	newSvc, ok := newObj.(*v1.Service)
+	if !ok {
+		return true
+	}
+	return metadataChanged(oldSvc, newSvc) ||
+		!reflect.DeepEqual(oldSvc.Spec, newSvc.Spec) ||
+		!reflect.DeepEqual(oldSvc.Status, newSvc.Status)
+}
+
+// podChanged reports whether a change of a pod affects the endpoints of headless
+// services, i.e. its labels, annotations, spec, phase or addresses changed.
+// Other status updates like the ones of container probes are ignored.
+func podChanged(oldObj, newObj interface{}) bool {
+	oldPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		return true
+	}
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok {
+		return true
+	}
+	return metadataChanged(oldPod, newPod) ||
+		!reflect.DeepEqual(oldPod.Spec, newPod.Spec) ||
+		oldPod.Status.Phase != newPod.Status.Phase ||
+		oldPod.Status.PodIP != newPod.Status.PodIP ||
+		oldPod.Status.HostIP != newPod.Status.HostIP
+}
+
+// nodeChanged reports whether the addresses or labels of a node changed.
+// Nodes report their status regularly, which doesn't affect any endpoints.
+func nodeChanged(oldObj, newObj interface{}) bool {
+	oldNode, ok := oldObj.(*v1.Node)
+	if !ok {
+		return true
+	}
+	newNode, ok := newObj.(*v1.Node)
+	if !ok {
+		return true
+	}
+	return !labels.Equals(oldNode.Labels, newNode.Labels) ||
+		!reflect.DeepEqual(oldNode.Status.Addresses, newNode.Status.Addresses)
+}
+
 func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname string, ttl endpoint.TTL) []*endpoint.Endpoint {
 	var endpoints []*endpoint.Endpoint
 
-	pods, err := sc.client.CoreV1().Pods(svc.Namespace).List(metav1.ListOptions{LabelSelector: labels.Set(svc.Spec.Selector).AsSelectorPreValidated().String()})
+	podLister, err := sc.pods()
+	if err != nil {
+		log.Errorf("List Pods of service[%s] error:%v", svc.GetName(), err)
+		return endpoints
+	}
+	pods, err := podLister.Pods(svc.Namespace).List(labels.Set(svc.Spec.Selector).AsSelectorPreValidated())
 	if err != nil {
 		log.Errorf("List Pods of service[%s] error:%v", svc.GetName(), err)
 		return endpoints
 	}
 
-	for _, v := range pods.Items {
+	for _, v := range pods {
 		headlessDomain := hostname
 		if v.Spec.Hostname != "" {
 			headlessDomain = v.Spec.Hostname + "." + headlessDomain
@@ -182,9 +318,9 @@ func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname stri
 			// To reduce traffice on the DNS API only add record for running Pods. Good Idea?
 			if v.Status.Phase == v1.PodRunning {
 				if ttl.IsConfigured() {
-					endpoints = append(endpoints, endpoint.NewEndpointWithTTL(headlessDomain, endpoint.RecordTypeA, ttl, v.Status.HostIP))
+					endpoints = append(endpoints, endpoint.NewEndpointWithTTL(headlessDomain, suitableType(v.Status.HostIP), ttl, v.Status.HostIP))
 				} else {
-					endpoints = append(endpoints, endpoint.NewEndpoint(headlessDomain, endpoint.RecordTypeA, v.Status.HostIP))
+					endpoints = append(endpoints, endpoint.NewEndpoint(headlessDomain, suitableType(v.Status.HostIP), v.Status.HostIP))
 				}
 			} else {
 				log.Debugf("Pod %s is not in running phase", v.Spec.Hostname)
@@ -194,9 +330,9 @@ func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname stri
 			// To reduce traffice on the DNS API only add record for running Pods. Good Idea?
 			if v.Status.Phase == v1.PodRunning {
 				if ttl.IsConfigured() {
-					endpoints = append(endpoints, endpoint.NewEndpointWithTTL(headlessDomain, endpoint.RecordTypeA, ttl, v.Status.PodIP))
+					endpoints = append(endpoints, endpoint.NewEndpointWithTTL(headlessDomain, suitableType(v.Status.PodIP), ttl, v.Status.PodIP))
 				} else {
-					endpoints = append(endpoints, endpoint.NewEndpoint(headlessDomain, endpoint.RecordTypeA, v.Status.PodIP))
+					endpoints = append(endpoints, endpoint.NewEndpoint(headlessDomain, suitableType(v.Status.PodIP), v.Status.PodIP))
 				}
 			} else {
 				log.Debugf("Pod %s is not in running phase", v.Spec.Hostname)
@@ -239,7 +375,7 @@ func (sc *serviceSource) endpoints(svc *v1.Service, nodeTargets endpoint.Targets
 }
 
 // filterByAnnotations filters a list of services by a given annotation selector.
-func (sc *serviceSource) filterByAnnotations(services []v1.Service) ([]v1.Service, error) {
+func (sc *serviceSource) filterByAnnotations(services []*v1.Service) ([]*v1.Service, error) {
 	labelSelector, err := metav1.ParseToLabelSelector(sc.annotationFilter)
 	if err != nil {
 		return nil, err
@@ -254,7 +390,7 @@ func (sc *serviceSource) filterByAnnotations(services []v1.Service) ([]v1.Servic
 		return services, nil
 	}
 
-	filteredList := []v1.Service{}
+	filteredList := []*v1.Service{}
 
 	for _, service := range services {
 		// convert the service's annotations to an equivalent label selector
@@ -270,8 +406,8 @@ func (sc *serviceSource) filterByAnnotations(services []v1.Service) ([]v1.Servic
 }
 
 // filterByServiceType filters services according their types
-func (sc *serviceSource) filterByServiceType(services []v1.Service) []v1.Service {
-	filteredList := []v1.Service{}
+func (sc *serviceSource) filterByServiceType(services []*v1.Service) []*v1.Service {
+	filteredList := []*v1.Service{}
 	for _, service := range services {
 		// Check if the service is of the given type or not
 		if _, ok := sc.serviceTypeFilter[string(service.Spec.Type)]; ok {
@@ -282,14 +418,16 @@ func (sc *serviceSource) filterByServiceType(services []v1.Service) []v1.Service
 	return filteredList
 }
 
-func (sc *serviceSource) setResourceLabel(service v1.Service, endpoints []*endpoint.Endpoint) {
+func (sc *serviceSource) setResourceLabel(service *v1.Service, endpoints []*endpoint.Endpoint) {
 	for _, ep := range endpoints {
 		ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("service/%s/%s", service.Namespace, service.Name)
 	}
+	setConflictLabels(endpoints, service.CreationTimestamp.Time, service.Annotations)
+	setDampingLabel(endpoints, service.Annotations)
 }
 
 func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, nodeTargets endpoint.Targets) []*endpoint.Endpoint {
-	hostname = strings.TrimSuffix(hostname, ".")
+	hostname = endpoint.NormalizeDNSName(strings.TrimSuffix(hostname, "."))
 	ttl, err := getTTLFromAnnotations(svc.Annotations)
 	if err != nil {
 		log.Warn(err)
@@ -303,6 +441,14 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, nod
 		DNSName:    hostname,
 	}
 
+	epAAAA := &endpoint.Endpoint{
+		RecordTTL:  ttl,
+		RecordType: endpoint.RecordTypeAAAA,
+		Labels:     endpoint.NewLabels(),
+		Targets:    make(endpoint.Targets, 0, defaultTargetsCapacity),
+		DNSName:    hostname,
+	}
+
 	epCNAME := &endpoint.Endpoint{
 		RecordTTL:  ttl,
 		RecordType: endpoint.RecordTypeCNAME,
@@ -331,10 +477,12 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, nod
 	}
 
 	for _, t := range targets {
-		if suitableType(t) == endpoint.RecordTypeA {
+		switch suitableType(t) {
+		case endpoint.RecordTypeA:
 			epA.Targets = append(epA.Targets, t)
-		}
-		if suitableType(t) == endpoint.RecordTypeCNAME {
+		case endpoint.RecordTypeAAAA:
+			epAAAA.Targets = append(epAAAA.Targets, t)
+		case endpoint.RecordTypeCNAME:
 			epCNAME.Targets = append(epCNAME.Targets, t)
 		}
 	}
@@ -342,6 +490,9 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, nod
 	if len(epA.Targets) > 0 {
 		endpoints = append(endpoints, epA)
 	}
+	if len(epAAAA.Targets) > 0 {
+		endpoints = append(endpoints, epAAAA)
+	}
 	if len(epCNAME.Targets) > 0 {
 		endpoints = append(endpoints, epCNAME)
 	}
@@ -378,17 +529,17 @@ func (sc *serviceSource) extractNodeTargets() (endpoint.Targets, error) {
 		externalIPs endpoint.Targets
 	)
 
-	nodes, err := sc.client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if sc.nodeInformer == nil {
+		// Return an empty list because it makes sense to continue and try other sources.
+		return endpoint.Targets{}, nil
+	}
+
+	nodes, err := sc.nodeInformer.Lister().List(labels.Everything())
 	if err != nil {
-		if errors.IsForbidden(err) {
-			// Return an empty list because it makes sense to continue and try other sources.
-			log.Debugf("Unable to list nodes (Forbidden), returning empty list of targets (NodePort services will be skipped)")
-			return endpoint.Targets{}, nil
-		}
 		return nil, err
 	}
 
-	for _, node := range nodes.Items {
+	for _, node := range nodes {
 		for _, address := range node.Status.Addresses {
 			switch address.Type {
 			case v1.NodeExternalIP:
@@ -411,8 +562,8 @@ func (sc *serviceSource) extractNodePortEndpoints(svc *v1.Service, nodeTargets e
 
 	for _, port := range svc.Spec.Ports {
 		if port.NodePort > 0 {
-			// build a target with a priority of 0, weight of 0, and pointing the given port on the given host
-			target := fmt.Sprintf("0 50 %d %s", port.NodePort, hostname)
+			// build a target with a priority of 0, weight of 50, and pointing the given port on the given host
+			target := endpoint.NewSRVTarget(0, 50, uint16(port.NodePort), hostname).String()
 
 			// figure out the portname
 			portName := port.Name
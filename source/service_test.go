@@ -17,11 +17,13 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"net"
 	"testing"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
@@ -41,17 +43,6 @@ func (suite *ServiceSuite) SetupTest() {
 	fakeClient := fake.NewSimpleClientset()
 	var err error
 
-	suite.sc, err = NewServiceSource(
-		fakeClient,
-		"",
-		"",
-		"{{.Name}}",
-		false,
-		"",
-		false,
-		false,
-		[]string{},
-	)
 	suite.fooWithTargets = &v1.Service{
 		Spec: v1.ServiceSpec{
 			Type: v1.ServiceTypeLoadBalancer,
@@ -71,20 +62,159 @@ func (suite *ServiceSuite) SetupTest() {
 		},
 	}
 
-	suite.NoError(err, "should initialize service source")
-
 	_, err = fakeClient.CoreV1().Services(suite.fooWithTargets.Namespace).Create(suite.fooWithTargets)
 	suite.NoError(err, "should successfully create service")
 
+	suite.sc, err = NewServiceSource(
+		fakeClient,
+		"",
+		"",
+		"{{.Name}}",
+		false,
+		"",
+		false,
+		false,
+		[]string{},
+	)
+	suite.NoError(err, "should initialize service source")
 }
 
 func (suite *ServiceSuite) TestResourceLabelIsSet() {
-	endpoints, _ := suite.sc.Endpoints()
+	endpoints, _ := suite.sc.Endpoints(context.Background())
 	for _, ep := range endpoints {
 		suite.Equal("service/default/foo-with-targets", ep.Labels[endpoint.ResourceLabelKey], "should set correct resource label")
 	}
 }
 
+// TestServiceSourceEventHandler tests that changes of services are reported to the event handler.
+func TestServiceSourceEventHandler(t *testing.T) {
+	kubernetes := fake.NewSimpleClientset()
+	client, err := NewServiceSource(kubernetes, v1.NamespaceAll, "", "", false, "", false, false, []string{})
+	require.NoError(t, err)
+
+	handler, events := eventRecorder()
+	client.AddEventHandler(handler)
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testing",
+			Name:        "foo",
+			Annotations: map[string]string{hostnameAnnotationKey: "foo.example.org."},
+		},
+		Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+		},
+	}
+	_, err = kubernetes.CoreV1().Services(service.Namespace).Create(service)
+	require.NoError(t, err)
+	expectEvent(t, events)
+
+	endpoints, err := client.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4"}},
+	})
+}
+
+// TestServiceSourceWatchesPodsOnlyForHeadlessServices tests that pods are only watched once a headless service is processed.
+func TestServiceSourceWatchesPodsOnlyForHeadlessServices(t *testing.T) {
+	kubernetes := fake.NewSimpleClientset()
+	client, err := NewServiceSource(kubernetes, v1.NamespaceAll, "", "", false, "", false, false, []string{})
+	require.NoError(t, err)
+	sc := client.(*serviceSource)
+
+	_, err = client.Endpoints(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, sc.podInformer, "pods shouldn't be watched without headless services")
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testing",
+			Name:        "foo",
+			Annotations: map[string]string{hostnameAnnotationKey: "foo.example.org."},
+		},
+		Spec: v1.ServiceSpec{
+			Type:      v1.ServiceTypeClusterIP,
+			ClusterIP: v1.ClusterIPNone,
+			Selector:  map[string]string{"component": "foo"},
+		},
+	}
+	_, err = kubernetes.CoreV1().Services(service.Namespace).Create(service)
+	require.NoError(t, err)
+	require.NoError(t, waitForCacheSync(func() bool {
+		services, err := sc.serviceInformer.Lister().List(labels.Everything())
+		return err == nil && len(services) == 1
+	}))
+
+	_, err = client.Endpoints(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, sc.podInformer, "pods should be watched for headless services")
+}
+
+func TestServiceChanged(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "1"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+	}
+
+	unchanged := svc.DeepCopy()
+	unchanged.ResourceVersion = "2"
+	assert.False(t, serviceChanged(svc, unchanged))
+
+	withStatus := svc.DeepCopy()
+	withStatus.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}
+	assert.True(t, serviceChanged(svc, withStatus))
+
+	withAnnotation := svc.DeepCopy()
+	withAnnotation.Annotations = map[string]string{hostnameAnnotationKey: "foo.example.org"}
+	assert.True(t, serviceChanged(svc, withAnnotation))
+}
+
+func TestPodChanged(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-0", Labels: map[string]string{"component": "foo"}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "1.1.1.1"},
+	}
+
+	probed := pod.DeepCopy()
+	probed.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	assert.False(t, podChanged(pod, probed))
+
+	moved := pod.DeepCopy()
+	moved.Status.PodIP = "1.1.1.2"
+	assert.True(t, podChanged(pod, moved))
+
+	stopped := pod.DeepCopy()
+	stopped.Status.Phase = v1.PodSucceeded
+	assert.True(t, podChanged(pod, stopped))
+
+	relabeled := pod.DeepCopy()
+	relabeled.Labels = map[string]string{"component": "bar"}
+	assert.True(t, podChanged(pod, relabeled))
+}
+
+func TestNodeChanged(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "54.10.11.1"}},
+		},
+	}
+
+	heartbeat := node.DeepCopy()
+	heartbeat.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue, LastHeartbeatTime: metav1.Now()}}
+	assert.False(t, nodeChanged(node, heartbeat))
+
+	readdressed := node.DeepCopy()
+	readdressed.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "54.10.11.2"}}
+	assert.True(t, nodeChanged(node, readdressed))
+
+	relabeled := node.DeepCopy()
+	relabeled.Labels = map[string]string{"role": "edge"}
+	assert.True(t, nodeChanged(node, relabeled))
+}
+
 func TestServiceSource(t *testing.T) {
 	suite.Run(t, new(ServiceSuite))
 	t.Run("Interface", testServiceSourceImplementsSource)
@@ -213,6 +343,29 @@ func testServiceSourceEndpoints(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"annotated services split IPv4 and IPv6 load balancer targets into A and AAAA endpoints",
+			"",
+			"",
+			"testing",
+			"foo",
+			v1.ServiceTypeLoadBalancer,
+			"",
+			"",
+			false,
+			map[string]string{},
+			map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			"",
+			[]string{"1.2.3.4", "2001:db8::1"},
+			[]string{},
+			[]*endpoint.Endpoint{
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordType: endpoint.RecordTypeA},
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"2001:db8::1"}, RecordType: endpoint.RecordTypeAAAA},
+			},
+			false,
+		},
 		{
 			"annotated ClusterIp aren't processed without explicit authorization",
 			"",
@@ -963,7 +1116,7 @@ func testServiceSourceEndpoints(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			endpoints, err := client.Endpoints()
+			endpoints, err := client.Endpoints(context.Background())
 			if tc.expectError {
 				require.Error(t, err)
 			} else {
@@ -1098,7 +1251,7 @@ func TestClusterIpServices(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			endpoints, err := client.Endpoints()
+			endpoints, err := client.Endpoints(context.Background())
 			if tc.expectError {
 				require.Error(t, err)
 			} else {
@@ -1295,7 +1448,7 @@ func TestNodePortServices(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			endpoints, err := client.Endpoints()
+			endpoints, err := client.Endpoints(context.Background())
 			if tc.expectError {
 				require.Error(t, err)
 			} else {
@@ -1496,7 +1649,7 @@ func TestHeadlessServices(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			endpoints, err := client.Endpoints()
+			endpoints, err := client.Endpoints(context.Background())
 			if tc.expectError {
 				require.Error(t, err)
 			} else {
@@ -1697,7 +1850,7 @@ func TestHeadlessServicesHostIP(t *testing.T) {
 			)
 			require.NoError(t, err)
 
-			endpoints, err := client.Endpoints()
+			endpoints, err := client.Endpoints(context.Background())
 			if tc.expectError {
 				require.Error(t, err)
 			} else {
@@ -1738,7 +1891,7 @@ func BenchmarkServiceEndpoints(b *testing.B) {
 	require.NoError(b, err)
 
 	for i := 0; i < b.N; i++ {
-		_, err := client.Endpoints()
+		_, err := client.Endpoints(context.Background())
 		require.NoError(b, err)
 	}
 }
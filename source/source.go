@@ -17,11 +17,15 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net"
 	"strconv"
 	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 )
@@ -37,6 +41,10 @@ const (
 	ttlAnnotationKey = "external-dns.alpha.kubernetes.io/ttl"
 	// The annotation used for switching to the alias record types e. g. AWS Alias records instead of a normal CNAME
 	aliasAnnotationKey = "external-dns.alpha.kubernetes.io/alias"
+	// The annotation used for defining the priority of a resource when several resources want the same DNS name
+	conflictPriorityAnnotationKey = "external-dns.alpha.kubernetes.io/conflict-priority"
+	// The annotation used for opting the records of a resource out of change damping
+	dampingAnnotationKey = "external-dns.alpha.kubernetes.io/damping"
 	// The value of the controller annotation so that we feel responsible
 	controllerAnnotationValue = "dns-controller"
 )
@@ -48,7 +56,10 @@ const (
 
 // Source defines the interface Endpoint sources should implement.
 type Source interface {
-	Endpoints() ([]*endpoint.Endpoint, error)
+	Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error)
+	// AddEventHandler adds a handler which is called whenever the objects the endpoints are generated
+	// from change. Sources which can't watch their objects never call it.
+	AddEventHandler(handler func())
 }
 
 func getTTLFromAnnotations(annotations map[string]string) (endpoint.TTL, error) {
@@ -67,6 +78,45 @@ func getTTLFromAnnotations(annotations map[string]string) (endpoint.TTL, error)
 	return endpoint.TTL(ttlValue), nil
 }
 
+func getConflictPriorityFromAnnotations(annotations map[string]string) (string, error) {
+	priorityAnnotation, exists := annotations[conflictPriorityAnnotationKey]
+	if !exists {
+		return "", nil
+	}
+	priority, err := strconv.ParseInt(priorityAnnotation, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("\"%v\" is not a valid conflict priority value", priorityAnnotation)
+	}
+	return strconv.FormatInt(priority, 10), nil
+}
+
+// setConflictLabels adds the labels used by conflict resolvers to pick between several resources
+// acquiring the same DNS name, i.e. the creation time and the priority of the resource.
+func setConflictLabels(endpoints []*endpoint.Endpoint, creationTimestamp time.Time, annotations map[string]string) {
+	priority, err := getConflictPriorityFromAnnotations(annotations)
+	if err != nil {
+		log.Warn(err)
+	}
+	for _, ep := range endpoints {
+		if !creationTimestamp.IsZero() {
+			ep.Labels[endpoint.CreationTimestampLabelKey] = creationTimestamp.UTC().Format(time.RFC3339)
+		}
+		if priority != "" {
+			ep.Labels[endpoint.ConflictPriorityLabelKey] = priority
+		}
+	}
+}
+
+// setDampingLabel opts the endpoints out of change damping if the resource is annotated accordingly
+func setDampingLabel(endpoints []*endpoint.Endpoint, annotations map[string]string) {
+	if annotations[dampingAnnotationKey] != "false" {
+		return
+	}
+	for _, ep := range endpoints {
+		ep.Labels[endpoint.DampingLabelKey] = "false"
+	}
+}
+
 func getHostnamesFromAnnotations(annotations map[string]string) []string {
 	hostnameAnnotation, exists := annotations[hostnameAnnotationKey]
 	if !exists {
@@ -107,25 +157,35 @@ func getTargetsFromTargetAnnotation(annotations map[string]string) endpoint.Targ
 }
 
 // suitableType returns the DNS resource record type suitable for the target.
-// In this case type A for IPs and type CNAME for everything else.
+// In this case type A for IPv4 addresses, type AAAA for IPv6 addresses and type CNAME for everything else.
 func suitableType(target string) string {
-	if net.ParseIP(target) != nil {
-		return endpoint.RecordTypeA
+	ip := net.ParseIP(target)
+	if ip == nil {
+		return endpoint.RecordTypeCNAME
+	}
+	if ip.To4() == nil {
+		return endpoint.RecordTypeAAAA
 	}
-	return endpoint.RecordTypeCNAME
+	return endpoint.RecordTypeA
 }
 
-// endpointsForHostname returns the endpoint objects for each host-target combination.
+// endpointsForHostname returns the endpoint objects for each host-target combination. Each endpoint
+// gets its own copy of the provider specific properties, so that they can be adjusted per endpoint.
 func endpointsForHostname(hostname string, targets endpoint.Targets, ttl endpoint.TTL, providerSpecific endpoint.ProviderSpecific) []*endpoint.Endpoint {
 	var endpoints []*endpoint.Endpoint
 
+	hostname = endpoint.NormalizeDNSName(strings.TrimSuffix(hostname, "."))
+
 	var aTargets endpoint.Targets
+	var aaaaTargets endpoint.Targets
 	var cnameTargets endpoint.Targets
 
 	for _, t := range targets {
 		switch suitableType(t) {
 		case endpoint.RecordTypeA:
 			aTargets = append(aTargets, t)
+		case endpoint.RecordTypeAAAA:
+			aaaaTargets = append(aaaaTargets, t)
 		default:
 			cnameTargets = append(cnameTargets, t)
 		}
@@ -133,24 +193,36 @@ func endpointsForHostname(hostname string, targets endpoint.Targets, ttl endpoin
 
 	if len(aTargets) > 0 {
 		epA := &endpoint.Endpoint{
-			DNSName:          strings.TrimSuffix(hostname, "."),
+			DNSName:          hostname,
 			Targets:          aTargets,
 			RecordTTL:        ttl,
 			RecordType:       endpoint.RecordTypeA,
 			Labels:           endpoint.NewLabels(),
-			ProviderSpecific: providerSpecific,
+			ProviderSpecific: providerSpecific.DeepCopy(),
 		}
 		endpoints = append(endpoints, epA)
 	}
 
+	if len(aaaaTargets) > 0 {
+		epAAAA := &endpoint.Endpoint{
+			DNSName:          hostname,
+			Targets:          aaaaTargets,
+			RecordTTL:        ttl,
+			RecordType:       endpoint.RecordTypeAAAA,
+			Labels:           endpoint.NewLabels(),
+			ProviderSpecific: providerSpecific.DeepCopy(),
+		}
+		endpoints = append(endpoints, epAAAA)
+	}
+
 	if len(cnameTargets) > 0 {
 		epCNAME := &endpoint.Endpoint{
-			DNSName:          strings.TrimSuffix(hostname, "."),
+			DNSName:          hostname,
 			Targets:          cnameTargets,
 			RecordTTL:        ttl,
 			RecordType:       endpoint.RecordTypeCNAME,
 			Labels:           endpoint.NewLabels(),
-			ProviderSpecific: providerSpecific,
+			ProviderSpecific: providerSpecific.DeepCopy(),
 		}
 		endpoints = append(endpoints, epCNAME)
 	}
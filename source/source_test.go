@@ -19,6 +19,7 @@ package source
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/stretchr/testify/assert"
@@ -76,11 +77,77 @@ func TestGetTTLFromAnnotations(t *testing.T) {
 	}
 }
 
+func TestGetConflictPriorityFromAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		title            string
+		annotations      map[string]string
+		expectedPriority string
+		expectedErr      error
+	}{
+		{
+			title:            "priority annotation not present",
+			annotations:      map[string]string{"foo": "bar"},
+			expectedPriority: "",
+			expectedErr:      nil,
+		},
+		{
+			title:            "priority annotation value is not a number",
+			annotations:      map[string]string{conflictPriorityAnnotationKey: "foo"},
+			expectedPriority: "",
+			expectedErr:      fmt.Errorf("\"foo\" is not a valid conflict priority value"),
+		},
+		{
+			title:            "priority annotation value is negative number",
+			annotations:      map[string]string{conflictPriorityAnnotationKey: "-10"},
+			expectedPriority: "-10",
+			expectedErr:      nil,
+		},
+		{
+			title:            "priority annotation value is normalized",
+			annotations:      map[string]string{conflictPriorityAnnotationKey: "+010"},
+			expectedPriority: "10",
+			expectedErr:      nil,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			priority, err := getConflictPriorityFromAnnotations(tc.annotations)
+			assert.Equal(t, tc.expectedPriority, priority)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestSetConflictLabels(t *testing.T) {
+	created := time.Date(2018, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	endpoints := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	setConflictLabels(endpoints, created, map[string]string{conflictPriorityAnnotationKey: "5"})
+	assert.Equal(t, "2018-01-02T02:04:05Z", endpoints[0].Labels[endpoint.CreationTimestampLabelKey])
+	assert.Equal(t, "5", endpoints[0].Labels[endpoint.ConflictPriorityLabelKey])
+
+	endpoints = []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	setConflictLabels(endpoints, time.Time{}, map[string]string{})
+	assert.NotContains(t, endpoints[0].Labels, endpoint.CreationTimestampLabelKey)
+	assert.NotContains(t, endpoints[0].Labels, endpoint.ConflictPriorityLabelKey)
+}
+
+func TestSetDampingLabel(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	setDampingLabel(endpoints, map[string]string{dampingAnnotationKey: "false"})
+	assert.Equal(t, "false", endpoints[0].Labels[endpoint.DampingLabelKey])
+
+	endpoints = []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	setDampingLabel(endpoints, map[string]string{dampingAnnotationKey: "true"})
+	assert.NotContains(t, endpoints[0].Labels, endpoint.DampingLabelKey)
+}
+
 func TestSuitableType(t *testing.T) {
 	for _, tc := range []struct {
 		target, recordType, expected string
 	}{
 		{"8.8.8.8", "", "A"},
+		{"2001:db8::1", "", "AAAA"},
+		{"::ffff:8.8.8.8", "", "A"},
 		{"foo.example.org", "", "CNAME"},
 		{"bar.eu-central-1.elb.amazonaws.com", "", "CNAME"},
 	} {
@@ -92,3 +159,13 @@ func TestSuitableType(t *testing.T) {
 		}
 	}
 }
+
+func TestEndpointsForHostnameOwnProviderSpecific(t *testing.T) {
+	providerSpecific := endpoint.ProviderSpecific{"alias": "true"}
+	endpoints := endpointsForHostname("example.org", endpoint.Targets{"1.2.3.4", "lb.com"}, 0, providerSpecific)
+	assert.Len(t, endpoints, 2)
+
+	endpoints[0].WithProviderSpecific("cloudflare/proxied", "false")
+	assert.Equal(t, endpoint.ProviderSpecific{"alias": "true"}, endpoints[1].ProviderSpecific)
+	assert.Equal(t, endpoint.ProviderSpecific{"alias": "true"}, providerSpecific)
+}